@@ -17,6 +17,7 @@ package backend
 
 import (
 	"crypto/sha256"
+	"errors"
 	"hash"
 
 	"github.com/airchains-network/gnark/constraint/solver"
@@ -62,7 +63,17 @@ type ProverConfig struct {
 	HashToFieldFn  hash.Hash
 	ChallengeHash  hash.Hash
 	KZGFoldingHash hash.Hash
+	IOPPHash       hash.Hash
 	Accelerator    string
+
+	// NonZK acknowledges that the proof may leak the secret witness, see
+	// WithNonZKProving
+	NonZK bool
+
+	// out-of-core proving, see WithOutOfCoreProving
+	OutOfCoreProvingKey string
+	OutOfCoreSpillDir   string
+	OutOfCoreChunkSize  int
 }
 
 // NewProverConfig returns a default ProverConfig with given prover options opts
@@ -73,6 +84,7 @@ func NewProverConfig(opts ...ProverOption) (ProverConfig, error) {
 		// separation tags for PLONK and Groth16
 		ChallengeHash:  sha256.New(),
 		KZGFoldingHash: sha256.New(),
+		IOPPHash:       sha256.New(),
 	}
 	for _, option := range opts {
 		if err := option(&opt); err != nil {
@@ -123,6 +135,17 @@ func WithProverKZGFoldingHashFunction(hFunc hash.Hash) ProverOption {
 	}
 }
 
+// WithProverIOPPHashFunction sets the hash function used by the interactive
+// oracle proof of proximity (FRI) for committing to the polynomials and for
+// deriving its challenges. If not set then by default SHA2-256 is used. Used
+// mainly for compatibility between different systems and efficient recursion.
+func WithProverIOPPHashFunction(hFunc hash.Hash) ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.IOPPHash = hFunc
+		return nil
+	}
+}
+
 // WithIcicleAcceleration requests to use [ICICLE] GPU proving backend for the
 // prover. This option requires that the program is compiled with `icicle` build
 // tag and the ICICLE dependencies are properly installed. See [ICICLE] for
@@ -136,6 +159,46 @@ func WithIcicleAcceleration() ProverOption {
 	}
 }
 
+// WithNonZKProving allows provers which do not blind the polynomials, and
+// whose proofs are sound but not zero-knowledge, to run. Such proofs may leak
+// information about the secret witness. The provers lacking zero-knowledge
+// (currently the PlonkFRI prover over Goldilocks) return an error if this
+// option is not set.
+func WithNonZKProving() ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.NonZK = true
+		return nil
+	}
+}
+
+// WithOutOfCoreProving requests the Groth16 prover to run out-of-core, for
+// circuits too large for the proving key and the prover buffers to fit in
+// memory.
+//
+// The proving key is memory-mapped from provingKeyPath, which must hold a key
+// serialized with WriteRawTo or WriteIndexedTo, and its points are decoded (without subgroup
+// checks, as with UnsafeReadFrom) and processed by the multi-exponentiations in
+// chunks of chunkSize points. If chunkSize is not positive, a default value is
+// used. The vectors a, b and c of the solution, on which the FFTs are
+// computed, are directly written by the solver in temporary files created in
+// spillDir (the default temporary directory if empty), so that the operating
+// system can page them out to disk. The wire values stay in memory. The
+// proving key given to Prove can be empty, otherwise it must be the key
+// stored at provingKeyPath.
+//
+// This option is only supported by the Groth16 prover on unix platforms.
+func WithOutOfCoreProving(provingKeyPath, spillDir string, chunkSize int) ProverOption {
+	return func(pc *ProverConfig) error {
+		if provingKeyPath == "" {
+			return errors.New("out-of-core proving requires a proving key path")
+		}
+		pc.OutOfCoreProvingKey = provingKeyPath
+		pc.OutOfCoreSpillDir = spillDir
+		pc.OutOfCoreChunkSize = chunkSize
+		return nil
+	}
+}
+
 // VerifierOption defines option for altering the behavior of the verifier. See
 // the descriptions of functions returning instances of this type for
 // implemented options.
@@ -146,6 +209,7 @@ type VerifierConfig struct {
 	HashToFieldFn  hash.Hash
 	ChallengeHash  hash.Hash
 	KZGFoldingHash hash.Hash
+	IOPPHash       hash.Hash
 }
 
 // NewVerifierConfig returns a default [VerifierConfig] with given verifier
@@ -156,6 +220,7 @@ func NewVerifierConfig(opts ...VerifierOption) (VerifierConfig, error) {
 		// separation tags for PLONK and Groth16
 		ChallengeHash:  sha256.New(),
 		KZGFoldingHash: sha256.New(),
+		IOPPHash:       sha256.New(),
 	}
 	for _, option := range opts {
 		if err := option(&opt); err != nil {
@@ -197,3 +262,14 @@ func WithVerifierKZGFoldingHashFunction(hFunc hash.Hash) VerifierOption {
 		return nil
 	}
 }
+
+// WithVerifierIOPPHashFunction sets the hash function used by the interactive
+// oracle proof of proximity (FRI) for committing to the polynomials and for
+// deriving its challenges. If not set then by default SHA2-256 is used. Used
+// mainly for compatibility between different systems and efficient recursion.
+func WithVerifierIOPPHashFunction(hFunc hash.Hash) VerifierOption {
+	return func(pc *VerifierConfig) error {
+		pc.IOPPHash = hFunc
+		return nil
+	}
+}
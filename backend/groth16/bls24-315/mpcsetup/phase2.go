@@ -171,7 +171,7 @@ func InitPhase2(r1cs *cs.R1CS, srs1 *Phase1) (Phase2, Phase2Evaluations) {
 	// Set δ public key
 	var delta fr.Element
 	delta.SetOne()
-	c2.PublicKey = newPublicKey(delta, nil, 1)
+	c2.PublicKey = newPublicKey(delta, nil, 1, randomSampler)
 
 	// Hash initial contribution
 	c2.Hash = c2.hash()
@@ -179,17 +179,32 @@ func InitPhase2(r1cs *cs.R1CS, srs1 *Phase1) (Phase2, Phase2Evaluations) {
 }
 
 func (c *Phase2) Contribute() {
+	c.contribute(randomSampler)
+}
+
+// ContributeFromBeacon makes the final contribution to the phase2 object, with
+// randomness derived from a public random beacon hashed nbIterations times (see
+// Phase1.ContributeFromBeacon). This mutates c.
+func (c *Phase2) ContributeFromBeacon(beacon []byte, nbIterations int) error {
+	if len(beacon) == 0 || nbIterations < 1 {
+		return errInvalidBeacon
+	}
+	c.contribute(beaconSampler(beacon, nbIterations))
+	return nil
+}
+
+func (c *Phase2) contribute(sample sampler) {
 	// Sample toxic δ
 	var delta, deltaInv fr.Element
 	var deltaBI, deltaInvBI big.Int
-	delta.SetRandom()
+	sample(&delta)
 	deltaInv.Inverse(&delta)
 
 	delta.BigInt(&deltaBI)
 	deltaInv.BigInt(&deltaInvBI)
 
 	// Set δ public key
-	c.PublicKey = newPublicKey(delta, c.Hash, 1)
+	c.PublicKey = newPublicKey(delta, c.Hash, 1, sample)
 
 	// Update δ
 	c.Parameters.G1.Delta.ScalarMultiplication(&c.Parameters.G1.Delta, &deltaBI)
@@ -220,6 +235,11 @@ func VerifyPhase2(c0, c1 *Phase2, c ...*Phase2) error {
 }
 
 func verifyPhase2(current, contribution *Phase2) error {
+	if len(contribution.Parameters.G1.L) != len(current.Parameters.G1.L) ||
+		len(contribution.Parameters.G1.Z) != len(current.Parameters.G1.Z) {
+		return errors.New("contribution size doesn't match the previous contribution")
+	}
+
 	// Compute R for δ
 	deltaR := genR(contribution.PublicKey.SG, contribution.PublicKey.SXG, current.Hash[:], 1)
 
@@ -257,6 +277,18 @@ func verifyPhase2(current, contribution *Phase2) error {
 	return nil
 }
 
+func (phase2 *Phase2) clone() Phase2 {
+	r := Phase2{}
+	r.Parameters.G1.Delta = phase2.Parameters.G1.Delta
+	r.Parameters.G1.L = append(r.Parameters.G1.L, phase2.Parameters.G1.L...)
+	r.Parameters.G1.Z = append(r.Parameters.G1.Z, phase2.Parameters.G1.Z...)
+	r.Parameters.G2.Delta = phase2.Parameters.G2.Delta
+	r.PublicKey = phase2.PublicKey
+	r.Hash = append(r.Hash, phase2.Hash...)
+
+	return r
+}
+
 func (c *Phase2) hash() []byte {
 	sha := sha256.New()
 	c.writeTo(sha)
@@ -17,6 +17,7 @@
 package mpcsetup
 
 import (
+	"bytes"
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	cs "github.com/airchains-network/gnark/constraint/bls24-315"
@@ -101,6 +102,91 @@ func TestSetupCircuit(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestTranscript(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	const (
+		nContributions = 2
+		power          = 9
+	)
+	beacon := []byte("random beacon")
+
+	assert := require.New(t)
+
+	// phase 1
+	var transcript1 bytes.Buffer
+	coordinator1, err := NewPhase1Coordinator(&transcript1, power)
+	assert.NoError(err)
+	for i := 0; i < nContributions; i++ {
+		// in practice, the participant receives the serialized current state
+		contribution := coordinator1.Current().clone()
+		contribution.Contribute()
+		assert.NoError(coordinator1.Add(&contribution))
+	}
+	invalid := coordinator1.Current().clone()
+	invalid.Contribute()
+	invalid.Parameters.G1.Tau[2] = invalid.Parameters.G1.Tau[3]
+	assert.Error(coordinator1.Add(&invalid))
+	final1, err := coordinator1.Finalize(beacon, 4)
+	assert.NoError(err)
+	assert.ErrorIs(coordinator1.Add(&invalid), errFinalized)
+
+	srs1, summary, err := VerifyPhase1Transcript(bytes.NewReader(transcript1.Bytes()))
+	assert.NoError(err)
+	assert.Len(summary.Hashes, nContributions+2)
+	assert.Equal(final1.Hash, srs1.Hash)
+	assert.Equal(beacon, summary.Beacon)
+	assert.Equal(4, summary.BeaconIterations)
+
+	// phase 2
+	var myCircuit Circuit
+	ccs, err := frontend.Compile(curve.ID.ScalarField(), r1cs.NewBuilder, &myCircuit)
+	assert.NoError(err)
+	r1cs := ccs.(*cs.R1CS)
+
+	var transcript2 bytes.Buffer
+	coordinator2, err := NewPhase2Coordinator(&transcript2, r1cs, srs1)
+	assert.NoError(err)
+	for i := 0; i < nContributions; i++ {
+		contribution := coordinator2.Current().clone()
+		contribution.Contribute()
+		assert.NoError(coordinator2.Add(&contribution))
+	}
+	final2, err := coordinator2.Finalize(beacon, 4)
+	assert.NoError(err)
+
+	srs2, evals, summary, err := VerifyPhase2Transcript(bytes.NewReader(transcript2.Bytes()), r1cs, srs1)
+	assert.NoError(err)
+	assert.Len(summary.Hashes, nContributions+2)
+	assert.Equal(final2.Hash, srs2.Hash)
+
+	// the final contribution must match the beacon
+	_, _, _, err = VerifyPhase2Transcript(bytes.NewReader(bytes.Replace(transcript2.Bytes(), beacon, []byte("other beacon!"), 1)), r1cs, srs1)
+	assert.Error(err)
+	// truncated transcript
+	_, _, err = VerifyPhase1Transcript(bytes.NewReader(transcript1.Bytes()[:transcript1.Len()-1]))
+	assert.Error(err)
+
+	// Extract the proving and verifying keys
+	pk, vk := ExtractKeys(srs1, srs2, evals, ccs.GetNbConstraints())
+
+	var preImage, hash fr.Element
+	{
+		m := native_mimc.NewMiMC()
+		m.Write(preImage.Marshal())
+		hash.SetBytes(m.Sum(nil))
+	}
+	witness, err := frontend.NewWitness(&Circuit{PreImage: preImage, Hash: hash}, curve.ID.ScalarField())
+	assert.NoError(err)
+	pubWitness, err := witness.Public()
+	assert.NoError(err)
+
+	proof, err := groth16.Prove(ccs, &pk, witness)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, &vk, pubWitness))
+}
+
 func BenchmarkPhase1(b *testing.B) {
 	const power = 14
 
@@ -170,30 +256,3 @@ func (circuit *Circuit) Define(api frontend.API) error {
 
 	return nil
 }
-
-func (phase1 *Phase1) clone() Phase1 {
-	r := Phase1{}
-	r.Parameters.G1.Tau = append(r.Parameters.G1.Tau, phase1.Parameters.G1.Tau...)
-	r.Parameters.G1.AlphaTau = append(r.Parameters.G1.AlphaTau, phase1.Parameters.G1.AlphaTau...)
-	r.Parameters.G1.BetaTau = append(r.Parameters.G1.BetaTau, phase1.Parameters.G1.BetaTau...)
-
-	r.Parameters.G2.Tau = append(r.Parameters.G2.Tau, phase1.Parameters.G2.Tau...)
-	r.Parameters.G2.Beta = phase1.Parameters.G2.Beta
-
-	r.PublicKeys = phase1.PublicKeys
-	r.Hash = append(r.Hash, phase1.Hash...)
-
-	return r
-}
-
-func (phase2 *Phase2) clone() Phase2 {
-	r := Phase2{}
-	r.Parameters.G1.Delta = phase2.Parameters.G1.Delta
-	r.Parameters.G1.L = append(r.Parameters.G1.L, phase2.Parameters.G1.L...)
-	r.Parameters.G1.Z = append(r.Parameters.G1.Z, phase2.Parameters.G1.Z...)
-	r.Parameters.G2.Delta = phase2.Parameters.G2.Delta
-	r.PublicKey = phase2.PublicKey
-	r.Hash = append(r.Hash, phase2.Hash...)
-
-	return r
-}
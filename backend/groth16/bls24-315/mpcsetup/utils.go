@@ -18,6 +18,8 @@ package mpcsetup
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
 	"math/big"
 	"math/bits"
 	"runtime"
@@ -34,13 +36,13 @@ type PublicKey struct {
 	XR  curve.G2Affine
 }
 
-func newPublicKey(x fr.Element, challenge []byte, dst byte) PublicKey {
+func newPublicKey(x fr.Element, challenge []byte, dst byte, sample sampler) PublicKey {
 	var pk PublicKey
 	_, _, g1, _ := curve.Generators()
 
 	var s fr.Element
 	var sBi big.Int
-	s.SetRandom()
+	sample(&s)
 	s.BigInt(&sBi)
 	pk.SG.ScalarMultiplication(&g1, &sBi)
 
@@ -57,6 +59,37 @@ func newPublicKey(x fr.Element, challenge []byte, dst byte) PublicKey {
 	return pk
 }
 
+// sampler sets z to a new toxic value.
+type sampler func(z *fr.Element)
+
+func randomSampler(z *fr.Element) {
+	z.SetRandom()
+}
+
+// beaconSampler returns a deterministic sampler, seeded by hashing the beacon
+// nbIterations times with sha256.
+func beaconSampler(beacon []byte, nbIterations int) sampler {
+	seed := sha256.Sum256(beacon)
+	for i := 1; i < nbIterations; i++ {
+		seed = sha256.Sum256(seed[:])
+	}
+	var counter uint64
+	return func(z *fr.Element) {
+		// expand the seed to twice the size of an element to make the bias negligible
+		var buf [2 * sha256.Size]byte
+		for i := 0; i < 2; i++ {
+			var c [8]byte
+			binary.BigEndian.PutUint64(c[:], counter)
+			counter++
+			h := sha256.New()
+			h.Write(seed[:])
+			h.Write(c[:])
+			h.Sum(buf[i*sha256.Size : i*sha256.Size])
+		}
+		z.SetBytes(buf[:])
+	}
+}
+
 func bitReverse[T any](a []T) {
 	n := uint64(len(a))
 	nn := uint64(64 - bits.TrailingZeros64(n))
@@ -84,7 +84,7 @@ func (phase1 *Phase1) ReadFrom(reader io.Reader) (int64, error) {
 		}
 	}
 	phase1.Hash = make([]byte, 32)
-	nBytes, err := reader.Read(phase1.Hash)
+	nBytes, err := io.ReadFull(reader, phase1.Hash)
 	return dec.BytesRead() + int64(nBytes), err
 }
 
@@ -139,7 +139,7 @@ func (c *Phase2) ReadFrom(reader io.Reader) (int64, error) {
 	}
 
 	c.Hash = make([]byte, 32)
-	n, err := reader.Read(c.Hash)
+	n, err := io.ReadFull(reader, c.Hash)
 	return int64(n) + dec.BytesRead(), err
 
 }
@@ -27,6 +27,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"bytes"
+	"encoding/binary"
 	"math/big"
 
 	"github.com/leanovate/gopter"
@@ -199,6 +201,97 @@ func TestProvingKeySerialization(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func TestProvingKeyIndexedSerialization(t *testing.T) {
+	assert := require.New(t)
+	_, _, p1, p2 := curve.Generators()
+
+	// create a random pk
+	var pk ProvingKey
+	pk.Domain = *fft.NewDomain(8)
+
+	nbWires := 6
+	pk.G1.A = make([]curve.G1Affine, nbWires-1)
+	pk.G1.B = make([]curve.G1Affine, nbWires)
+	pk.G1.K = make([]curve.G1Affine, 4)
+	pk.G1.Z = make([]curve.G1Affine, pk.Domain.Cardinality)
+	pk.G2.B = make([]curve.G2Affine, nbWires)
+	for i := range pk.G1.Z {
+		var s big.Int
+		s.SetUint64(uint64(i + 2))
+		pk.G1.Z[i].ScalarMultiplication(&p1, &s)
+		if i < len(pk.G2.B) {
+			pk.G2.B[i].ScalarMultiplication(&p2, &s)
+			pk.G1.B[i] = pk.G1.Z[i]
+		}
+	}
+	pk.G1.A[0] = p1
+	pk.G1.K[1] = p1
+	pk.G1.Alpha = p1
+	pk.G2.Delta = p2
+
+	pk.NbInfinityA = 1
+	pk.InfinityA = make([]bool, nbWires)
+	pk.InfinityB = make([]bool, nbWires)
+	pk.InfinityA[2] = true
+
+	var err error
+	pk.CommitmentKeys, _, err = pedersen.Setup([]curve.G1Affine{p1, pk.G1.Z[0]})
+	assert.NoError(err)
+
+	for _, compressed := range [][]PkSection{nil, {SectionG1Z, SectionG2B}} {
+		var buf bytes.Buffer
+		written, err := pk.WriteIndexedTo(&buf, compressed...)
+		assert.NoError(err)
+		assert.Equal(int64(buf.Len()), written)
+
+		lazy, err := NewLazyProvingKey(buf.Bytes())
+		assert.NoError(err)
+		z, err := lazy.G1(SectionG1Z)
+		assert.NoError(err)
+		assert.Equal(pk.G1.Z, z)
+		decoded, err := lazy.ProvingKey()
+		assert.NoError(err)
+		assert.Equal(&pk, decoded)
+		assert.NoError(lazy.Close())
+		_, err = lazy.G1(SectionG1Z)
+		assert.ErrorIs(err, errClosedProvingKey)
+		_, err = lazy.G2B()
+		assert.ErrorIs(err, errClosedProvingKey)
+		assert.NoError(lazy.Close())
+
+		// corrupted metadata
+		data := bytes.Clone(buf.Bytes())
+		data[indexedHeaderSize+indexedIndexSize] ^= 1
+		_, err = NewLazyProvingKey(data)
+		assert.Error(err)
+
+		// corrupted points are detected when loading the section
+		data = bytes.Clone(buf.Bytes())
+		data[len(data)-1] ^= 1
+		lazy, err = NewLazyProvingKey(data)
+		assert.NoError(err)
+		_, err = lazy.G1(SectionG1A)
+		assert.NoError(err)
+		_, err = lazy.G2B()
+		assert.Error(err)
+	}
+
+	// the number of wires is checked against the metadata section before
+	// allocating the infinity flags
+	var metadata, domain bytes.Buffer
+	_, err = pk.writeMetadataTo(&metadata)
+	assert.NoError(err)
+	_, err = pk.Domain.WriteTo(&domain)
+	assert.NoError(err)
+	data := metadata.Bytes()
+	var decoded ProvingKey
+	assert.NoError(decoded.readMetadataFrom(bytes.NewReader(data), uint64(len(data))))
+	offset := domain.Len() + 3*curve.SizeOfG1AffineUncompressed + 2*curve.SizeOfG2AffineUncompressed
+	assert.Equal(uint64(nbWires), binary.BigEndian.Uint64(data[offset:]))
+	binary.BigEndian.PutUint64(data[offset:], 1<<62)
+	assert.Error(decoded.readMetadataFrom(bytes.NewReader(data), uint64(len(data))))
+}
+
 func GenG1() gopter.Gen {
 	_, _, g1GenAff, _ := curve.Generators()
 	return func(genParams *gopter.GenParameters) *gopter.GenResult {
@@ -19,7 +19,9 @@ package groth16
 import (
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"math/big"
 	"text/template"
 	"time"
 
@@ -73,6 +75,37 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		close(chDone)
 	}()
 
+	// compute e(Σx.[Kvk(t)]1, -[γ]2)
+	kSumAff, err := vk.foldPublicInputs(proof, publicWitness, opt.HashToFieldFn)
+	if err != nil {
+		return err
+	}
+
+	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
+	if err != nil {
+		return err
+	}
+
+	// wait for (eKrsδ, eArBs)
+	if err := <-chDone; err != nil {
+		return err
+	}
+
+	right = curve.FinalExponentiation(&right, &doubleML)
+	if !vk.e.Equal(&right) {
+		return errPairingCheckFailed
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+	return nil
+}
+
+// foldPublicInputs checks the commitments of the proof and returns
+// [Kvk(t)]₁ = [K₀]₁ + Σ xᵢ·[Kᵢ]₁ + Σ commitments, where xᵢ are the public inputs
+// completed with the hashes of the commitments.
+func (vk *VerifyingKey) foldPublicInputs(proof *Proof, publicWitness fr.Vector, hashToField hash.Hash) (curve.G1Affine, error) {
+	var kSumAff curve.G1Affine
+
 	maxNbPublicCommitted := 0
 	for _, s := range vk.PublicAndCommitmentCommitted { // iterate over commitments
 		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
@@ -86,12 +119,12 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 			copy(commitmentPrehashSerialized[offset:], publicWitness[vk.PublicAndCommitmentCommitted[i][j]-1].Marshal())
 			offset += fr.Bytes
 		}
-		opt.HashToFieldFn.Write(commitmentPrehashSerialized[:offset])
-		hashBts := opt.HashToFieldFn.Sum(nil)
-		opt.HashToFieldFn.Reset()
+		hashToField.Write(commitmentPrehashSerialized[:offset])
+		hashBts := hashToField.Sum(nil)
+		hashToField.Reset()
 		nbBuf := fr.Bytes
-		if opt.HashToFieldFn.Size() < fr.Bytes {
-			nbBuf = opt.HashToFieldFn.Size()
+		if hashToField.Size() < fr.Bytes {
+			nbBuf = hashToField.Size()
 		}
 		var res fr.Element
 		res.SetBytes(hashBts[:nbBuf])
@@ -100,17 +133,16 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	}
 
 	if folded, err := pedersen.FoldCommitments(proof.Commitments, commitmentsSerialized); err != nil {
-		return err
+		return kSumAff, err
 	} else {
 		if err = vk.CommitmentKey.Verify(folded, proof.CommitmentPok); err != nil {
-			return err
+			return kSumAff, err
 		}
 	}
 
-	// compute e(Σx.[Kvk(t)]1, -[γ]2)
 	var kSum curve.G1Jac
 	if _, err := kSum.MultiExp(vk.G1.K[1:], publicWitness, ecc.MultiExpConfig{}); err != nil {
-		return err
+		return kSumAff, err
 	}
 	kSum.AddMixed(&vk.G1.K[0])
 
@@ -118,25 +150,93 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		kSum.AddMixed(&proof.Commitments[i])
 	}
 
-	var kSumAff curve.G1Affine
 	kSumAff.FromJacobian(&kSum)
+	return kSumAff, nil
+}
 
-	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
+// BatchVerify verifies a batch of proofs against the same VerifyingKey.
+//
+// The pairing equations of the proofs are combined with random coefficients
+// rᵢ, so that the cost is a single multi-pairing with n+3 pairs:
+//
+//	∏ e(rᵢ·Arᵢ, Bsᵢ) · e(Σ rᵢ·Krsᵢ, -[δ]₂) · e(Σ rᵢ·Kvkᵢ, -[γ]₂) · e(-(Σ rᵢ)·[α]₁, [β]₂) = 1
+//
+// An error is returned if any of the proofs is invalid, without telling which one.
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []fr.Vector, opts ...backend.VerifierOption) error {
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("invalid batch, got %d proofs and %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+	opt, err := backend.NewVerifierConfig(opts...)
 	if err != nil {
-		return err
+		return fmt.Errorf("new verifier config: %w", err)
+	}
+	if opt.HashToFieldFn == nil {
+		opt.HashToFieldFn = hash_to_field.New([]byte(constraint.CommitmentDst))
 	}
 
-	// wait for (eKrsδ, eArBs)
-	if err := <-chDone; err != nil {
+	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
+	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Int("nbProofs", len(proofs)).Logger()
+	start := time.Now()
+
+	n := len(proofs)
+	p := make([]curve.G1Affine, n+3)
+	q := make([]curve.G2Affine, n+3)
+	r := make([]fr.Element, n)
+	krs := make([]curve.G1Affine, n)
+	kSums := make([]curve.G1Affine, n)
+	var rSum fr.Element
+	var bi big.Int
+	for i, proof := range proofs {
+		if len(publicWitnesses[i]) != nbPublicVars-1 {
+			return fmt.Errorf("invalid witness size for proof %d, got %d, expected %d (public - ONE_WIRE)", i, len(publicWitnesses[i]), nbPublicVars-1)
+		}
+		// check that the points in the proof are in the correct subgroup
+		if !proof.isValid() {
+			return errCorrectSubgroupCheckFailed
+		}
+		if kSums[i], err = vk.foldPublicInputs(proof, publicWitnesses[i], opt.HashToFieldFn); err != nil {
+			return err
+		}
+		if _, err = r[i].SetRandom(); err != nil {
+			return err
+		}
+		rSum.Add(&rSum, &r[i])
+
+		p[i].ScalarMultiplication(&proof.Ar, r[i].BigInt(&bi))
+		q[i].Set(&proof.Bs)
+		krs[i].Set(&proof.Krs)
+	}
+
+	// Σ rᵢ·Krsᵢ and Σ rᵢ·Kvkᵢ
+	var acc curve.G1Jac
+	if _, err := acc.MultiExp(krs, r, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	p[n].FromJacobian(&acc)
+	q[n].Set(&vk.G2.deltaNeg)
+	if _, err := acc.MultiExp(kSums, r, ecc.MultiExpConfig{}); err != nil {
 		return err
 	}
+	p[n+1].FromJacobian(&acc)
+	q[n+1].Set(&vk.G2.gammaNeg)
 
-	right = curve.FinalExponentiation(&right, &doubleML)
-	if !vk.e.Equal(&right) {
+	// -(Σ rᵢ)·[α]₁
+	rSum.Neg(&rSum)
+	p[n+2].ScalarMultiplication(&vk.G1.Alpha, rSum.BigInt(&bi))
+	q[n+2].Set(&vk.G2.Beta)
+
+	ok, err := curve.PairingCheck(p, q)
+	if err != nil {
+		return err
+	}
+	if !ok {
 		return errPairingCheckFailed
 	}
 
-	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+	log.Debug().Dur("took", time.Since(start)).Msg("batch verifier done")
 	return nil
 }
 
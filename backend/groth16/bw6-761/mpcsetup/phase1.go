@@ -57,9 +57,9 @@ func InitPhase1(power int) (phase1 Phase1) {
 	tau.SetOne()
 	alpha.SetOne()
 	beta.SetOne()
-	phase1.PublicKeys.Tau = newPublicKey(tau, nil, 1)
-	phase1.PublicKeys.Alpha = newPublicKey(alpha, nil, 2)
-	phase1.PublicKeys.Beta = newPublicKey(beta, nil, 3)
+	phase1.PublicKeys.Tau = newPublicKey(tau, nil, 1, randomSampler)
+	phase1.PublicKeys.Alpha = newPublicKey(alpha, nil, 2, randomSampler)
+	phase1.PublicKeys.Beta = newPublicKey(beta, nil, 3, randomSampler)
 
 	// First contribution use generators
 	_, _, g1, g2 := curve.Generators()
@@ -87,16 +87,32 @@ func InitPhase1(power int) (phase1 Phase1) {
 
 // Contribute contributes randomness to the phase1 object. This mutates phase1.
 func (phase1 *Phase1) Contribute() {
+	phase1.contribute(randomSampler)
+}
+
+// ContributeFromBeacon makes the final contribution to the phase1 object, with
+// randomness derived from a public random beacon hashed nbIterations times. Anyone
+// can recompute this contribution, which ensures that the final parameters are
+// not chosen by the last participant. This mutates phase1.
+func (phase1 *Phase1) ContributeFromBeacon(beacon []byte, nbIterations int) error {
+	if len(beacon) == 0 || nbIterations < 1 {
+		return errInvalidBeacon
+	}
+	phase1.contribute(beaconSampler(beacon, nbIterations))
+	return nil
+}
+
+func (phase1 *Phase1) contribute(sample sampler) {
 	N := len(phase1.Parameters.G2.Tau)
 
 	// Generate key pairs
 	var tau, alpha, beta fr.Element
-	tau.SetRandom()
-	alpha.SetRandom()
-	beta.SetRandom()
-	phase1.PublicKeys.Tau = newPublicKey(tau, phase1.Hash[:], 1)
-	phase1.PublicKeys.Alpha = newPublicKey(alpha, phase1.Hash[:], 2)
-	phase1.PublicKeys.Beta = newPublicKey(beta, phase1.Hash[:], 3)
+	sample(&tau)
+	sample(&alpha)
+	sample(&beta)
+	phase1.PublicKeys.Tau = newPublicKey(tau, phase1.Hash[:], 1, sample)
+	phase1.PublicKeys.Alpha = newPublicKey(alpha, phase1.Hash[:], 2, sample)
+	phase1.PublicKeys.Beta = newPublicKey(beta, phase1.Hash[:], 3, sample)
 
 	// Compute powers of τ, ατ, and βτ
 	taus := powers(tau, 2*N-1)
@@ -133,6 +149,13 @@ func VerifyPhase1(c0, c1 *Phase1, c ...*Phase1) error {
 
 // verifyPhase1 checks that a contribution is based on a known previous Phase1 state.
 func verifyPhase1(current, contribution *Phase1) error {
+	if len(contribution.Parameters.G1.Tau) != len(current.Parameters.G1.Tau) ||
+		len(contribution.Parameters.G1.AlphaTau) != len(current.Parameters.G1.AlphaTau) ||
+		len(contribution.Parameters.G1.BetaTau) != len(current.Parameters.G1.BetaTau) ||
+		len(contribution.Parameters.G2.Tau) != len(current.Parameters.G2.Tau) {
+		return errors.New("contribution size doesn't match the previous contribution")
+	}
+
 	// Compute R for τ, α, β
 	tauR := genR(contribution.PublicKeys.Tau.SG, contribution.PublicKeys.Tau.SXG, current.Hash[:], 1)
 	alphaR := genR(contribution.PublicKeys.Alpha.SG, contribution.PublicKeys.Alpha.SXG, current.Hash[:], 2)
@@ -196,6 +219,21 @@ func verifyPhase1(current, contribution *Phase1) error {
 	return nil
 }
 
+func (phase1 *Phase1) clone() Phase1 {
+	r := Phase1{}
+	r.Parameters.G1.Tau = append(r.Parameters.G1.Tau, phase1.Parameters.G1.Tau...)
+	r.Parameters.G1.AlphaTau = append(r.Parameters.G1.AlphaTau, phase1.Parameters.G1.AlphaTau...)
+	r.Parameters.G1.BetaTau = append(r.Parameters.G1.BetaTau, phase1.Parameters.G1.BetaTau...)
+
+	r.Parameters.G2.Tau = append(r.Parameters.G2.Tau, phase1.Parameters.G2.Tau...)
+	r.Parameters.G2.Beta = phase1.Parameters.G2.Beta
+
+	r.PublicKeys = phase1.PublicKeys
+	r.Hash = append(r.Hash, phase1.Hash...)
+
+	return r
+}
+
 func (phase1 *Phase1) hash() []byte {
 	sha := sha256.New()
 	phase1.writeTo(sha)
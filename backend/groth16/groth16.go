@@ -20,6 +20,7 @@
 package groth16
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -160,6 +161,79 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 	}
 }
 
+// BatchVerify verifies a batch of proofs against the same VerifyingKey, with a
+// single multi-pairing check. The i-th proof is verified with the i-th public
+// witness. An error is returned if any of the proofs is invalid.
+func BatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) error {
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("invalid batch, got %d proofs and %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+
+	switch _vk := vk.(type) {
+	case *groth16_bls12377.VerifyingKey:
+		_proofs, _witnesses, err := concreteBatch[*groth16_bls12377.Proof, fr_bls12377.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls12377.BatchVerify(_proofs, _vk, _witnesses, opts...)
+	case *groth16_bls12381.VerifyingKey:
+		_proofs, _witnesses, err := concreteBatch[*groth16_bls12381.Proof, fr_bls12381.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls12381.BatchVerify(_proofs, _vk, _witnesses, opts...)
+	case *groth16_bn254.VerifyingKey:
+		_proofs, _witnesses, err := concreteBatch[*groth16_bn254.Proof, fr_bn254.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bn254.BatchVerify(_proofs, _vk, _witnesses, opts...)
+	case *groth16_bw6761.VerifyingKey:
+		_proofs, _witnesses, err := concreteBatch[*groth16_bw6761.Proof, fr_bw6761.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bw6761.BatchVerify(_proofs, _vk, _witnesses, opts...)
+	case *groth16_bls24317.VerifyingKey:
+		_proofs, _witnesses, err := concreteBatch[*groth16_bls24317.Proof, fr_bls24317.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls24317.BatchVerify(_proofs, _vk, _witnesses, opts...)
+	case *groth16_bls24315.VerifyingKey:
+		_proofs, _witnesses, err := concreteBatch[*groth16_bls24315.Proof, fr_bls24315.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls24315.BatchVerify(_proofs, _vk, _witnesses, opts...)
+	case *groth16_bw6633.VerifyingKey:
+		_proofs, _witnesses, err := concreteBatch[*groth16_bw6633.Proof, fr_bw6633.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bw6633.BatchVerify(_proofs, _vk, _witnesses, opts...)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// concreteBatch converts the proofs and the public witnesses of a batch to
+// their curve-specific types.
+func concreteBatch[P Proof, V any](proofs []Proof, publicWitnesses []witness.Witness) ([]P, []V, error) {
+	_proofs := make([]P, len(proofs))
+	_witnesses := make([]V, len(publicWitnesses))
+	for i := range proofs {
+		var ok bool
+		if _proofs[i], ok = proofs[i].(P); !ok {
+			return nil, nil, fmt.Errorf("invalid proof type %T at index %d", proofs[i], i)
+		}
+		if _witnesses[i], ok = publicWitnesses[i].Vector().(V); !ok {
+			return nil, nil, witness.ErrInvalidWitness
+		}
+	}
+	return _proofs, _witnesses, nil
+}
+
 // Prove runs the groth16.Prove algorithm.
 //
 // if the force flag is set:
@@ -3,12 +3,17 @@ package groth16_test
 import (
 	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/airchains-network/gnark/backend"
 	"github.com/airchains-network/gnark/backend/groth16"
+	groth16_bn254 "github.com/airchains-network/gnark/backend/groth16/bn254"
+	"github.com/airchains-network/gnark/backend/witness"
 	"github.com/airchains-network/gnark/constraint"
 	"github.com/airchains-network/gnark/frontend"
 	"github.com/airchains-network/gnark/frontend/cs/r1cs"
@@ -51,6 +56,158 @@ func TestCustomHashToField(t *testing.T) {
 	}
 }
 
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbProofs = 4
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &batchCircuit{})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+
+			proofs := make([]groth16.Proof, nbProofs)
+			publicWitnesses := make([]witness.Witness, nbProofs)
+			for i := range proofs {
+				w, err := frontend.NewWitness(&batchCircuit{X: i + 2, Y: (i + 2) * (i + 2)}, curve.ScalarField())
+				assert.NoError(err)
+				proofs[i], err = groth16.Prove(ccs, pk, w)
+				assert.NoError(err)
+				publicWitnesses[i], err = w.Public()
+				assert.NoError(err)
+			}
+			assert.NoError(groth16.BatchVerify(proofs, vk, publicWitnesses))
+
+			// a single proof verified against the wrong public witness
+			publicWitnesses[0], publicWitnesses[1] = publicWitnesses[1], publicWitnesses[0]
+			assert.Error(groth16.BatchVerify(proofs, vk, publicWitnesses))
+			publicWitnesses[0], publicWitnesses[1] = publicWitnesses[1], publicWitnesses[0]
+
+			// inconsistent batch
+			assert.Error(groth16.BatchVerify(proofs, vk, publicWitnesses[1:]))
+		}, curve.String())
+	}
+}
+
+func TestOutOfCoreProving(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			for _, circuit := range []struct {
+				name                string
+				circuit, assignment frontend.Circuit
+			}{
+				{"commitment", &batchCircuit{}, &batchCircuit{X: 3, Y: 9}},
+				{"mul", &refCircuit{nbConstraints: 50}, &refCircuit{nbConstraints: 50, X: 1, Y: 1}},
+			} {
+				ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, circuit.circuit)
+				assert.NoError(err)
+				pk, vk, err := groth16.Setup(ccs)
+				assert.NoError(err)
+
+				dir := t.TempDir()
+				pkPath := filepath.Join(dir, "pk.raw")
+				f, err := os.Create(pkPath)
+				assert.NoError(err)
+				_, err = pk.WriteRawTo(f)
+				assert.NoError(err)
+				assert.NoError(f.Close())
+
+				w, err := frontend.NewWitness(circuit.assignment, curve.ScalarField())
+				assert.NoError(err)
+				publicWitness, err := w.Public()
+				assert.NoError(err)
+
+				// small chunks so that the multi-exponentiations are split
+				proof, err := groth16.Prove(ccs, groth16.NewProvingKey(curve), w, backend.WithOutOfCoreProving(pkPath, dir, 3))
+				assert.NoError(err, circuit.name)
+				assert.NoError(groth16.Verify(proof, vk, publicWitness), circuit.name)
+
+				// the proving key given to Prove must match the out-of-core one
+				proof, err = groth16.Prove(ccs, pk, w, backend.WithOutOfCoreProving(pkPath, dir, 0))
+				assert.NoError(err, circuit.name)
+				assert.NoError(groth16.Verify(proof, vk, publicWitness), circuit.name)
+				otherPk, _, err := groth16.Setup(ccs)
+				assert.NoError(err)
+				_, err = groth16.Prove(ccs, otherPk, w, backend.WithOutOfCoreProving(pkPath, dir, 0))
+				assert.Error(err, circuit.name)
+
+				// indexed proving key, with compressed sections
+				if pk, ok := pk.(*groth16_bn254.ProvingKey); ok {
+					indexedPath := filepath.Join(dir, "pk.indexed")
+					f, err = os.Create(indexedPath)
+					assert.NoError(err)
+					_, err = pk.WriteIndexedTo(f, groth16_bn254.SectionG1Z, groth16_bn254.SectionG2B)
+					assert.NoError(err)
+					assert.NoError(f.Close())
+					proof, err = groth16.Prove(ccs, groth16.NewProvingKey(curve), w, backend.WithOutOfCoreProving(indexedPath, dir, 3))
+					assert.NoError(err, circuit.name)
+					assert.NoError(groth16.Verify(proof, vk, publicWitness), circuit.name)
+				}
+
+				// compressed proving key
+				compressedPath := filepath.Join(dir, "pk")
+				f, err = os.Create(compressedPath)
+				assert.NoError(err)
+				_, err = pk.WriteTo(f)
+				assert.NoError(err)
+				assert.NoError(f.Close())
+				_, err = groth16.Prove(ccs, groth16.NewProvingKey(curve), w, backend.WithOutOfCoreProving(compressedPath, dir, 0))
+				assert.Error(err, circuit.name)
+			}
+		}, curve.String())
+	}
+}
+
+func TestOutOfCoreProvingMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	assert := test.NewAssert(t)
+	ccs, assignment := referenceCircuit(ecc.BN254)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+
+	dir := t.TempDir()
+	pkPath := filepath.Join(dir, "pk.raw")
+	f, err := os.Create(pkPath)
+	assert.NoError(err)
+	_, err = pk.WriteRawTo(f)
+	assert.NoError(err)
+	assert.NoError(f.Close())
+
+	// the out-of-core prover must allocate less on the heap than the in-memory one,
+	// even without counting the proving key which is already in memory here. The
+	// pages of the memory-mapped proving key and of the temporary files may still
+	// be counted in the resident set size, but the kernel can reclaim them.
+	inMemory := heapAllocated(func() {
+		proof, err := groth16.Prove(ccs, pk, w)
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, publicWitness))
+	})
+	outOfCore := heapAllocated(func() {
+		proof, err := groth16.Prove(ccs, groth16.NewProvingKey(ecc.BN254), w, backend.WithOutOfCoreProving(pkPath, dir, 1<<10))
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, publicWitness))
+	})
+	t.Logf("heap allocations: in-memory %d bytes, out-of-core %d bytes", inMemory, outOfCore)
+	assert.Less(outOfCore, inMemory)
+}
+
+// heapAllocated returns the number of bytes allocated on the heap by f.
+func heapAllocated(f func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -168,6 +325,21 @@ func (c *commitmentCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+type batchCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *batchCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	cmt, err := api.(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	api.AssertIsDifferent(cmt, 0)
+	return nil
+}
+
 type constantHash struct{}
 
 func (h constantHash) Write(p []byte) (n int, err error) { return len(p), nil }
@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package internal
+
+import "errors"
+
+var errMmapNotSupported = errors.New("memory-mapped files are not supported on this platform")
+
+// MapFile maps the content of the file at path in memory, read only.
+func MapFile(path string) (data []byte, unmap func() error, err error) {
+	return nil, nil, errMmapNotSupported
+}
+
+// NewSpillVector returns a vector of n zero values backed by a temporary file.
+func NewSpillVector[T any](dir string, n int) (v []T, release func() error, err error) {
+	return nil, nil, errMmapNotSupported
+}
@@ -0,0 +1,66 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// MapFile maps the content of the file at path in memory, read only. The
+// returned unmap function must be called once the data is not used anymore.
+func MapFile(path string) (data []byte, unmap func() error, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() == 0 {
+		return nil, nil, fmt.Errorf("map %s: empty file", path)
+	}
+
+	data, err = syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, fmt.Errorf("map %s: %w", path, err)
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
+
+// NewSpillVector returns a vector of n zero values backed by a temporary file
+// created in dir (the default temporary directory if empty), so that the
+// operating system can page it out to disk under memory pressure. T must not
+// contain pointers. The returned release function must be called once the
+// vector is not used anymore.
+func NewSpillVector[T any](dir string, n int) (v []T, release func() error, err error) {
+	if n == 0 {
+		return nil, func() error { return nil }, nil
+	}
+	var zero T
+	size := n * int(unsafe.Sizeof(zero))
+
+	f, err := os.CreateTemp(dir, "gnark-spill-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	// the file is unlinked right away, its content stays reachable until unmapped
+	defer f.Close()
+	defer os.Remove(f.Name())
+
+	if err = f.Truncate(int64(size)); err != nil {
+		return nil, nil, err
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, fmt.Errorf("map spill file: %w", err)
+	}
+
+	v = unsafe.Slice((*T)(unsafe.Pointer(&data[0])), n)
+	return v, func() error { return syscall.Munmap(data) }, nil
+}
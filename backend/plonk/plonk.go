@@ -20,6 +20,7 @@
 package plonk
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -216,6 +217,95 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 	}
 }
 
+// BatchVerify verifies a batch of proofs with a single final pairing check. The
+// i-th proof is verified against the i-th verifying key and public witness; the
+// verifying keys may come from different circuits but must be defined over the
+// same curve and share the same KZG SRS. An error is returned if any of the
+// proofs is invalid.
+func BatchVerify(proofs []Proof, vks []VerifyingKey, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) error {
+	if len(proofs) != len(vks) || len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("invalid batch, got %d proofs, %d verifying keys and %d public witnesses", len(proofs), len(vks), len(publicWitnesses))
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	switch vks[0].(type) {
+	case *plonk_bn254.VerifyingKey:
+		_proofs, _vks, _witnesses, err := concreteBatch[*plonk_bn254.Proof, *plonk_bn254.VerifyingKey, fr_bn254.Vector](proofs, vks, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bn254.BatchVerify(_proofs, _vks, _witnesses, opts...)
+
+	case *plonk_bls12381.VerifyingKey:
+		_proofs, _vks, _witnesses, err := concreteBatch[*plonk_bls12381.Proof, *plonk_bls12381.VerifyingKey, fr_bls12381.Vector](proofs, vks, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls12381.BatchVerify(_proofs, _vks, _witnesses, opts...)
+
+	case *plonk_bls12377.VerifyingKey:
+		_proofs, _vks, _witnesses, err := concreteBatch[*plonk_bls12377.Proof, *plonk_bls12377.VerifyingKey, fr_bls12377.Vector](proofs, vks, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls12377.BatchVerify(_proofs, _vks, _witnesses, opts...)
+
+	case *plonk_bw6761.VerifyingKey:
+		_proofs, _vks, _witnesses, err := concreteBatch[*plonk_bw6761.Proof, *plonk_bw6761.VerifyingKey, fr_bw6761.Vector](proofs, vks, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bw6761.BatchVerify(_proofs, _vks, _witnesses, opts...)
+
+	case *plonk_bw6633.VerifyingKey:
+		_proofs, _vks, _witnesses, err := concreteBatch[*plonk_bw6633.Proof, *plonk_bw6633.VerifyingKey, fr_bw6633.Vector](proofs, vks, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bw6633.BatchVerify(_proofs, _vks, _witnesses, opts...)
+
+	case *plonk_bls24317.VerifyingKey:
+		_proofs, _vks, _witnesses, err := concreteBatch[*plonk_bls24317.Proof, *plonk_bls24317.VerifyingKey, fr_bls24317.Vector](proofs, vks, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls24317.BatchVerify(_proofs, _vks, _witnesses, opts...)
+
+	case *plonk_bls24315.VerifyingKey:
+		_proofs, _vks, _witnesses, err := concreteBatch[*plonk_bls24315.Proof, *plonk_bls24315.VerifyingKey, fr_bls24315.Vector](proofs, vks, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls24315.BatchVerify(_proofs, _vks, _witnesses, opts...)
+
+	default:
+		panic("unrecognized verifying key type")
+	}
+}
+
+// concreteBatch converts the proofs, the verifying keys and the public witnesses
+// of a batch to their curve-specific types.
+func concreteBatch[P Proof, K VerifyingKey, V any](proofs []Proof, vks []VerifyingKey, publicWitnesses []witness.Witness) ([]P, []K, []V, error) {
+	_proofs := make([]P, len(proofs))
+	_vks := make([]K, len(vks))
+	_witnesses := make([]V, len(publicWitnesses))
+	for i := range proofs {
+		var ok bool
+		if _proofs[i], ok = proofs[i].(P); !ok {
+			return nil, nil, nil, fmt.Errorf("invalid proof type %T at index %d", proofs[i], i)
+		}
+		if _vks[i], ok = vks[i].(K); !ok {
+			return nil, nil, nil, fmt.Errorf("invalid verifying key type %T at index %d", vks[i], i)
+		}
+		if _witnesses[i], ok = publicWitnesses[i].Vector().(V); !ok {
+			return nil, nil, nil, witness.ErrInvalidWitness
+		}
+	}
+	return _proofs, _vks, _witnesses, nil
+}
+
 // NewCS instantiate a concrete curved-typed SparseR1CS and return a ConstraintSystem interface
 // This method exists for (de)serialization purposes
 func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
@@ -11,6 +11,7 @@ import (
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/airchains-network/gnark/backend"
 	"github.com/airchains-network/gnark/backend/plonk"
+	"github.com/airchains-network/gnark/backend/witness"
 	"github.com/airchains-network/gnark/constraint"
 	"github.com/airchains-network/gnark/frontend"
 	"github.com/airchains-network/gnark/frontend/cs/scs"
@@ -194,6 +195,72 @@ func TestCustomKZGFoldingHash(t *testing.T) {
 	}
 }
 
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		curve := curve
+		assert.Run(func(assert *test.Assert) {
+			// two different circuits of the same size share the same SRS
+			type batchEntry struct {
+				proof         plonk.Proof
+				vk            plonk.VerifyingKey
+				publicWitness witness.Witness
+			}
+			var entries []batchEntry
+			for _, c := range []struct{ nbConstraints, x int }{{4, 2}, {4, 3}, {5, 2}} {
+				vk, proof, publicWitness := batchProof(assert, curve, c.nbConstraints, c.x)
+				entries = append(entries, batchEntry{proof, vk, publicWitness})
+			}
+			proofs := make([]plonk.Proof, len(entries))
+			vks := make([]plonk.VerifyingKey, len(entries))
+			publicWitnesses := make([]witness.Witness, len(entries))
+			for i := range entries {
+				proofs[i], vks[i], publicWitnesses[i] = entries[i].proof, entries[i].vk, entries[i].publicWitness
+			}
+
+			assert.NoError(plonk.BatchVerify(proofs, vks, publicWitnesses))
+
+			// a proof verified against the wrong public witness
+			swapped := []witness.Witness{publicWitnesses[1], publicWitnesses[0], publicWitnesses[2]}
+			assert.Error(plonk.BatchVerify(proofs, vks, swapped))
+
+			// a proof verified against the verifying key of another circuit
+			assert.Error(plonk.BatchVerify(proofs, []plonk.VerifyingKey{vks[0], vks[2], vks[2]}, publicWitnesses))
+
+			// mismatching batch sizes
+			assert.Error(plonk.BatchVerify(proofs, vks[:2], publicWitnesses))
+
+			// verifying key from a different SRS
+			otherVk, otherProof, otherWitness := batchProof(assert, curve, 20, 2)
+			assert.Error(plonk.BatchVerify(
+				append(proofs, otherProof),
+				append(vks, otherVk),
+				append(publicWitnesses, otherWitness),
+			))
+		}, curve.String())
+	}
+}
+
+// batchProof returns a proof of x^(2^nbConstraints) = Y
+func batchProof(assert *test.Assert, curve ecc.ID, nbConstraints, x int) (plonk.VerifyingKey, plonk.Proof, witness.Witness) {
+	ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+
+	exp := new(big.Int).Lsh(big.NewInt(1), uint(nbConstraints))
+	y := new(big.Int).Exp(big.NewInt(int64(x)), exp, curve.ScalarField())
+	fullWitness, err := frontend.NewWitness(&refCircuit{X: x, Y: y}, curve.ScalarField())
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+	return vk, proof, publicWitness
+}
+
 func BenchmarkSetup(b *testing.B) {
 	for _, curve := range getCurves() {
 		b.Run(curve.String(), func(b *testing.B) {
@@ -17,11 +17,14 @@
 package plonkfri
 
 import (
-	"crypto/sha256"
+	"fmt"
+	"hash"
+
+	"github.com/airchains-network/gnark/backend"
+	cs "github.com/airchains-network/gnark/constraint/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fri"
-	cs "github.com/airchains-network/gnark/constraint/bls24-315"
 )
 
 // ProvingKey stores the data needed to generate a proof:
@@ -89,7 +92,12 @@ type VerifyingKey struct {
 	// In particular Qk is not complete.
 	Qpp [5]fri.ProofOfProximity // Ql, Qr, Qm, Qo, Qk
 
-	// Iopp scheme (currently one for each size of polynomial)
+	// Iopp scheme (currently one for each size of polynomial). SetupWithOptions
+	// uses the IOPP hash function of its options, and ReadFrom rebuilds the
+	// scheme with the default SHA2-256.
+	//
+	// Deprecated: Prove and Verify don't use it, they instantiate the scheme
+	// with the IOPP hash function of their own options.
 	Iopp fri.Iopp
 
 	// generator of the group on which the Iopp works. If i is the opening position,
@@ -97,8 +105,20 @@ type VerifyingKey struct {
 	GenOpening fr.Element
 }
 
-// Setup sets proving and verifying keys
+// Setup sets proving and verifying keys, using the default SHA2-256 for the
+// Iopp.
 func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
+	return SetupWithOptions(spr)
+}
+
+// SetupWithOptions sets proving and verifying keys. Among the prover options,
+// only the hash function used by the Iopp is relevant at setup, and the same
+// option must be given to Prove and Verify.
+func SetupWithOptions(spr *cs.SparseR1CS, opts ...backend.ProverOption) (*ProvingKey, *VerifyingKey, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create backend config: %w", err)
+	}
 
 	var pk ProvingKey
 	var vk VerifyingKey
@@ -128,9 +148,8 @@ func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
 	vk.NbPublicVariables = uint64(len(spr.Public))
 
 	// IOP schemess
-	// The +2 is to handle the blinding.
-	sizeIopp := pk.Domain[0].Cardinality + 2
-	vk.Iopp = fri.RADIX_2_FRI.New(sizeIopp, sha256.New())
+	vk.Iopp = vk.newIopp(opt.IOPPHash)
+	iopp := vk.Iopp
 	// only there to access the group used in FRI...
 	rho := uint64(fri.GetRho())
 	// we multiply by 2 because the IOP is created with size pk.Domain[0].Cardinality + 2 (because
@@ -190,24 +209,23 @@ func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
 	copy(pk.CQr, pk.EvaluationQrDomainBigBitReversed)
 	copy(pk.CQm, pk.EvaluationQmDomainBigBitReversed)
 	copy(pk.CQo, pk.EvaluationQoDomainBigBitReversed)
-	var err error
-	vk.Qpp[0], err = vk.Iopp.BuildProofOfProximity(pk.CQl)
+	vk.Qpp[0], err = iopp.BuildProofOfProximity(pk.CQl)
 	if err != nil {
 		return &pk, &vk, err
 	}
-	vk.Qpp[1], err = vk.Iopp.BuildProofOfProximity(pk.CQr)
+	vk.Qpp[1], err = iopp.BuildProofOfProximity(pk.CQr)
 	if err != nil {
 		return &pk, &vk, err
 	}
-	vk.Qpp[2], err = vk.Iopp.BuildProofOfProximity(pk.CQm)
+	vk.Qpp[2], err = iopp.BuildProofOfProximity(pk.CQm)
 	if err != nil {
 		return &pk, &vk, err
 	}
-	vk.Qpp[3], err = vk.Iopp.BuildProofOfProximity(pk.CQo)
+	vk.Qpp[3], err = iopp.BuildProofOfProximity(pk.CQo)
 	if err != nil {
 		return &pk, &vk, err
 	}
-	vk.Qpp[4], err = vk.Iopp.BuildProofOfProximity(pk.CQkIncomplete)
+	vk.Qpp[4], err = iopp.BuildProofOfProximity(pk.CQkIncomplete)
 	if err != nil {
 		return &pk, &vk, err
 	}
@@ -221,7 +239,7 @@ func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
 	buildPermutation(spr, &pk)
 
 	// set s1, s2, s3
-	err = computePermutationPolynomials(&pk, &vk)
+	err = computePermutationPolynomials(&pk, &vk, iopp)
 	if err != nil {
 		return &pk, &vk, err
 	}
@@ -306,7 +324,7 @@ func buildPermutation(spr *cs.SparseR1CS, pk *ProvingKey) {
 // \---------------/       \--------------------/        \------------------------/
 //
 //	s1 (LDE)                s2 (LDE)                          s3 (LDE)
-func computePermutationPolynomials(pk *ProvingKey, vk *VerifyingKey) error {
+func computePermutationPolynomials(pk *ProvingKey, vk *VerifyingKey, iopp fri.Iopp) error {
 
 	nbElmt := int(pk.Domain[0].Cardinality)
 
@@ -344,15 +362,15 @@ func computePermutationPolynomials(pk *ProvingKey, vk *VerifyingKey) error {
 	copy(vk.IdCanonical[2], pk.EvaluationId3BigDomain)
 
 	var err error
-	vk.Idpp[0], err = vk.Iopp.BuildProofOfProximity(pk.EvaluationId1BigDomain)
+	vk.Idpp[0], err = iopp.BuildProofOfProximity(pk.EvaluationId1BigDomain)
 	if err != nil {
 		return err
 	}
-	vk.Idpp[1], err = vk.Iopp.BuildProofOfProximity(pk.EvaluationId2BigDomain)
+	vk.Idpp[1], err = iopp.BuildProofOfProximity(pk.EvaluationId2BigDomain)
 	if err != nil {
 		return err
 	}
-	vk.Idpp[2], err = vk.Iopp.BuildProofOfProximity(pk.EvaluationId3BigDomain)
+	vk.Idpp[2], err = iopp.BuildProofOfProximity(pk.EvaluationId3BigDomain)
 	if err != nil {
 		return err
 	}
@@ -374,15 +392,15 @@ func computePermutationPolynomials(pk *ProvingKey, vk *VerifyingKey) error {
 	copy(vk.SCanonical[0], pk.EvaluationS1BigDomain[:pk.Domain[0].Cardinality])
 	copy(vk.SCanonical[1], pk.EvaluationS2BigDomain[:pk.Domain[0].Cardinality])
 	copy(vk.SCanonical[2], pk.EvaluationS3BigDomain[:pk.Domain[0].Cardinality])
-	vk.Spp[0], err = vk.Iopp.BuildProofOfProximity(vk.SCanonical[0])
+	vk.Spp[0], err = iopp.BuildProofOfProximity(vk.SCanonical[0])
 	if err != nil {
 		return err
 	}
-	vk.Spp[1], err = vk.Iopp.BuildProofOfProximity(vk.SCanonical[1])
+	vk.Spp[1], err = iopp.BuildProofOfProximity(vk.SCanonical[1])
 	if err != nil {
 		return err
 	}
-	vk.Spp[2], err = vk.Iopp.BuildProofOfProximity(vk.SCanonical[2])
+	vk.Spp[2], err = iopp.BuildProofOfProximity(vk.SCanonical[2])
 	if err != nil {
 		return err
 	}
@@ -412,6 +430,12 @@ func getIDSmallDomain(domain *fft.Domain) []fr.Element {
 	return res
 }
 
+// newIopp returns a new Iopp scheme on the domain of the verifying key, using
+// h for the Merkle trees and Fiat-Shamir. The +2 is to handle the blinding.
+func (vk *VerifyingKey) newIopp(h hash.Hash) fri.Iopp {
+	return fri.RADIX_2_FRI.New(vk.Size+2, h)
+}
+
 // NbPublicWitness returns the expected public witness size (number of field elements)
 func (vk *VerifyingKey) NbPublicWitness() int {
 	return int(vk.NbPublicVariables)
@@ -0,0 +1,498 @@
+package plonkfri
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"text/template"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fri"
+)
+
+const tmplSolidityVerifier = `// SPDX-License-Identifier: Apache-2.0
+
+// Copyright 2023 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+pragma solidity ^0.8.19;
+
+contract PlonkFriVerifier {
+
+  uint256 private constant R_MOD = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
+  uint256 private constant TWO_INV = {{ frstr .TwoInv }};
+
+  // ----------------------- vk ---------------------
+  uint256 private constant VK_NB_PUBLIC_INPUTS = {{ .NbPublicVariables }};
+  uint256 private constant VK_DOMAIN_SIZE = {{ .Size }};
+  uint256 private constant VK_INV_DOMAIN_SIZE = {{ frstr .SizeInv }};
+  uint256 private constant VK_OMEGA = {{ frstr .Generator }};
+  uint256 private constant VK_GEN_OPENING = {{ frstr .GenOpening }};
+
+  // Merkle roots of the evaluations of the preprocessed polynomials. Their
+  // proofs of proximity are checked when the contract is generated.
+  bytes32 private constant VK_QL_ROOT = {{ hex (index .Roots 0) }};
+  bytes32 private constant VK_QR_ROOT = {{ hex (index .Roots 1) }};
+  bytes32 private constant VK_QM_ROOT = {{ hex (index .Roots 2) }};
+  bytes32 private constant VK_QO_ROOT = {{ hex (index .Roots 3) }};
+  bytes32 private constant VK_QK_ROOT = {{ hex (index .Roots 4) }};
+  bytes32 private constant VK_S1_ROOT = {{ hex (index .Roots 5) }};
+  bytes32 private constant VK_S2_ROOT = {{ hex (index .Roots 6) }};
+  bytes32 private constant VK_S3_ROOT = {{ hex (index .Roots 7) }};
+  bytes32 private constant VK_ID1_ROOT = {{ hex (index .Roots 8) }};
+  bytes32 private constant VK_ID2_ROOT = {{ hex (index .Roots 9) }};
+  bytes32 private constant VK_ID3_ROOT = {{ hex (index .Roots 10) }};
+
+  // ----------------------- fri ---------------------
+  uint256 private constant FRI_DOMAIN_SIZE = {{ .FriDomainSize }};
+  uint256 private constant FRI_GEN_INV = {{ frstr .FriGenInv }};
+  uint256 private constant FRI_NB_STEPS = {{ .FriNbSteps }};
+  uint256 private constant FRI_DEPTH = {{ .FriDepth }};
+  uint256 private constant FRI_SHIFT = {{ .FriShift }};
+  bytes32 private constant FRI_NAME_S0 = "s0";
+
+  // ----------------------- proof ---------------------
+  // the proof contains the proofs of proximity of l, r, o, z, h1, h2, h3,
+  // followed by the openings of ql, qr, qm, qo, qk, l, r, o, h1, h2, h3, s1,
+  // s2, s3, id1, id2, id3 at ζ, z at ζ and z at ωζ.
+  uint256 private constant NB_COMMITTED = 7;
+  uint256 private constant NB_OPENINGS = 19;
+  uint256 private constant PROOF_SIZE = {{ .ProofSize }};
+
+  struct Challenges {
+    uint256 beta;
+    uint256 gamma;
+    uint256 alpha;
+    uint256 position;
+    uint256 zeta;
+  }
+
+  /// Verify a PlonkFRI proof.
+  /// Reverts if the proof or the public inputs are malformed.
+  /// @param proof serialised PlonkFRI proof (using gnark's MarshalSolidity)
+  /// @param public_inputs (must be reduced)
+  /// @return success true if the proof passes false otherwise
+  function Verify(bytes calldata proof, uint256[] calldata public_inputs)
+  public view returns(bool success) {
+    require(public_inputs.length == VK_NB_PUBLIC_INPUTS, "wrong number of public inputs");
+    for (uint256 i = 0; i < public_inputs.length; i++) {
+      require(public_inputs[i] < R_MOD, "public input not reduced");
+    }
+    require(proof.length == PROOF_SIZE, "wrong proof size");
+
+    // 1 - the committed polynomials are of low degree. The first Merkle root
+    // of each proof of proximity is the commitment to the polynomial.
+    bytes32[NB_COMMITTED] memory roots;
+    uint256 offset = 0;
+    for (uint256 i = 0; i < NB_COMMITTED; i++) {
+      roots[i] = bytes32(load(proof, offset));
+      offset = verifyProofOfProximity(proof, offset);
+    }
+
+    // 2 - the claimed values are the evaluations of the committed polynomials
+    Challenges memory c = deriveChallenges(public_inputs, roots);
+    uint256[NB_OPENINGS] memory openings = verifyOpenings(proof, offset, roots, c.position);
+
+    // 3 - the algebraic relation holds at ζ
+    success = checkRelation(openings, public_inputs, c);
+  }
+
+  /// Derives the challenges of the PLONK protocol. As in gnark, the first one
+  /// is derived under the name "gamma" and is used as β. Each challenge binds
+  /// the previous one and the roots of the commitments it depends on: l, r, o
+  /// for β, z for α and h1, h2, h3 for ζ.
+  function deriveChallenges(uint256[] memory public_inputs, bytes32[NB_COMMITTED] memory roots)
+  internal view returns(Challenges memory c) {
+    bytes32 h = sha256(abi.encodePacked("gamma", public_inputs, roots[0], roots[1], roots[2]));
+    c.beta = uint256(h) % R_MOD;
+    h = sha256(abi.encodePacked("beta", h));
+    c.gamma = uint256(h) % R_MOD;
+    h = sha256(abi.encodePacked("alpha", h, roots[3]));
+    c.alpha = uint256(h) % R_MOD;
+    h = sha256(abi.encodePacked("zeta", h, roots[4], roots[5], roots[6]));
+    c.position = (uint256(h) % R_MOD) % FRI_DOMAIN_SIZE;
+    c.zeta = expmod(VK_GEN_OPENING, c.position);
+  }
+
+  /// Verifies a FRI proof of proximity starting at offset.
+  /// @return the offset following the proof of proximity
+  function verifyProofOfProximity(bytes calldata proof, uint256 offset)
+  internal view returns(uint256) {
+    (uint256[] memory x, uint256 position, uint256 end) = deriveFoldingChallenges(proof, offset);
+    uint256 size = FRI_DOMAIN_SIZE;
+    uint256 gInv = FRI_GEN_INV;
+    for (uint256 i = 0; i < FRI_NB_STEPS; i++) {
+      uint256 folded = verifyFoldingStep(proof, offset, i, position, x[i], gInv);
+      offset += foldingStepSize(i);
+      if (i + 1 < FRI_NB_STEPS) {
+        // the folded value is one of the two values opened at the next step
+        position = sortedPosition(position >> 1, size >> 1);
+        require(folded == load(proof, offset + 0x20 * (1 + (position & 1))), "wrong folding");
+        gInv = mulmod(gInv, gInv, R_MOD);
+        size >>= 1;
+      } else {
+        require(folded == load(proof, end), "wrong folding");
+      }
+    }
+    return end + 0x20;
+  }
+
+  /// Derives the folding challenges and the queried position of a proof of
+  /// proximity starting at offset.
+  /// @return x the folding challenges
+  /// @return position the queried position in the first layer
+  /// @return end the offset of the final evaluation
+  function deriveFoldingChallenges(bytes calldata proof, uint256 offset)
+  internal pure returns(uint256[] memory x, uint256 position, uint256 end) {
+    x = new uint256[](FRI_NB_STEPS);
+    // the first challenge is bound to the salt, which is zero.
+    bytes32 h = sha256(abi.encodePacked(foldingChallengeName(0), bytes32(0), load(proof, offset)));
+    x[0] = uint256(h) % R_MOD;
+    end = offset + foldingStepSize(0);
+    for (uint256 i = 1; i < FRI_NB_STEPS; i++) {
+      h = sha256(abi.encodePacked(foldingChallengeName(i), h, load(proof, end)));
+      x[i] = uint256(h) % R_MOD;
+      end += foldingStepSize(i);
+    }
+    h = sha256(abi.encodePacked(FRI_NAME_S0, h, load(proof, end)));
+    position = uint256(h) % FRI_DOMAIN_SIZE;
+  }
+
+  /// Checks the Merkle proof of the pair of values opened at the i-th folding
+  /// step, and folds them with the challenge x.
+  /// @return the folded value
+  function verifyFoldingStep(bytes calldata proof, uint256 offset, uint256 i, uint256 position, uint256 x, uint256 gInv)
+  internal view returns(uint256) {
+    uint256 l = load(proof, offset + 0x20);
+    uint256 r = load(proof, offset + 0x40);
+    require(l < R_MOD && r < R_MOD, "folded value not reduced");
+    bytes32 node = sha256(abi.encodePacked(sha256(abi.encodePacked(l)), sha256(abi.encodePacked(r))));
+    node = computeMerkleRoot(proof, offset + 0x60, node, position >> 1, FRI_DEPTH - 1 - i);
+    require(node == bytes32(load(proof, offset)), "wrong merkle proof");
+
+    // ((l+r) + x·(l-r)·g⁻ʲ)/2 where j is the index of the pair
+    uint256 res = mulmod(mulmod(sub(l, r), expmod(gInv, position >> 1), R_MOD), x, R_MOD);
+    res = addmod(res, addmod(l, r, R_MOD), R_MOD);
+    return mulmod(res, TWO_INV, R_MOD);
+  }
+
+  /// Checks the openings of the polynomials at the queried position, and at
+  /// the shifted position for z.
+  /// @return openings the claimed values
+  function verifyOpenings(bytes calldata proof, uint256 offset, bytes32[NB_COMMITTED] memory roots, uint256 position)
+  internal pure returns(uint256[NB_OPENINGS] memory openings) {
+    bytes32[NB_OPENINGS] memory openingRoots = [
+      VK_QL_ROOT, VK_QR_ROOT, VK_QM_ROOT, VK_QO_ROOT, VK_QK_ROOT,
+      roots[0], roots[1], roots[2],
+      roots[4], roots[5], roots[6],
+      VK_S1_ROOT, VK_S2_ROOT, VK_S3_ROOT,
+      VK_ID1_ROOT, VK_ID2_ROOT, VK_ID3_ROOT,
+      roots[3], roots[3]
+    ];
+    for (uint256 i = 0; i < NB_OPENINGS; i++) {
+      if (i == NB_OPENINGS - 1) {
+        position = (position + FRI_SHIFT) % FRI_DOMAIN_SIZE;
+      }
+      openings[i] = load(proof, offset);
+      require(openings[i] < R_MOD, "opening not reduced");
+      bytes32 root = computeMerkleRoot(
+        proof, offset + 0x20, sha256(abi.encodePacked(openings[i])), sortedPosition(position, FRI_DOMAIN_SIZE), FRI_DEPTH);
+      require(root == openingRoots[i], "wrong opening");
+      offset += 0x20 * (1 + FRI_DEPTH);
+    }
+  }
+
+  /// Checks that
+  /// (ql·l+qr·r+qm·l·r+qo·o+qk) + α·(z(ωζ)·∏(l+β·sᵢ+γ) - z(ζ)·∏(l+β·idᵢ+γ)) + α²·(z(ζ)-1)·L₀(ζ)
+  /// equals (h1 + ζⁿ⁺²·h2 + ζ²⁽ⁿ⁺²⁾·h3)·(ζⁿ-1).
+  function checkRelation(uint256[NB_OPENINGS] memory o, uint256[] memory public_inputs, Challenges memory c)
+  internal view returns(bool) {
+    // gate constraint
+    uint256 gate = mulmod(o[5], o[0], R_MOD);
+    gate = addmod(gate, mulmod(o[6], o[1], R_MOD), R_MOD);
+    gate = addmod(gate, mulmod(mulmod(o[2], o[5], R_MOD), o[6], R_MOD), R_MOD);
+    gate = addmod(gate, mulmod(o[7], o[3], R_MOD), R_MOD);
+    gate = addmod(gate, addmod(o[4], sumPublicInputs(public_inputs, c.zeta), R_MOD), R_MOD);
+
+    // boundary constraint (z(ζ)-1)·L₀(ζ)
+    uint256 zhZeta = sub(expmod(c.zeta, VK_DOMAIN_SIZE), 1);
+    uint256 boundary = mulmod(zhZeta, inverse(sub(c.zeta, 1)), R_MOD);
+    boundary = mulmod(mulmod(boundary, VK_INV_DOMAIN_SIZE, R_MOD), sub(o[17], 1), R_MOD);
+
+    uint256 lhs = addmod(mulmod(boundary, c.alpha, R_MOD), permutationConstraint(o, c), R_MOD);
+    lhs = addmod(mulmod(lhs, c.alpha, R_MOD), gate, R_MOD);
+
+    uint256 zetaPowerNPlusTwo = expmod(c.zeta, VK_DOMAIN_SIZE + 2);
+    uint256 rhs = addmod(mulmod(o[10], zetaPowerNPlusTwo, R_MOD), o[9], R_MOD);
+    rhs = addmod(mulmod(rhs, zetaPowerNPlusTwo, R_MOD), o[8], R_MOD);
+    rhs = mulmod(rhs, zhZeta, R_MOD);
+
+    return lhs == rhs;
+  }
+
+  /// @return z(ωζ)·∏(l+β·sᵢ+γ) - z(ζ)·∏(l+β·idᵢ+γ)
+  function permutationConstraint(uint256[NB_OPENINGS] memory o, Challenges memory c)
+  internal pure returns(uint256) {
+    uint256 num = o[18];
+    uint256 den = o[17];
+    for (uint256 k = 0; k < 3; k++) {
+      num = mulmod(num, addmod(addmod(mulmod(c.beta, o[11 + k], R_MOD), o[5 + k], R_MOD), c.gamma, R_MOD), R_MOD);
+      den = mulmod(den, addmod(addmod(mulmod(c.beta, o[14 + k], R_MOD), o[5 + k], R_MOD), c.gamma, R_MOD), R_MOD);
+    }
+    return sub(num, den);
+  }
+
+  /// @return res ∑ wᵢ·Lᵢ(ζ), using Lᵢ₊₁(ζ) = ω·Lᵢ(ζ)·(ζ-ωⁱ)/(ζ-ωⁱ⁺¹)
+  function sumPublicInputs(uint256[] memory public_inputs, uint256 zeta)
+  internal view returns(uint256 res) {
+    uint256 l = sub(zeta, 1);
+    if (l == 0) {
+      l = 1;
+    } else {
+      l = mulmod(mulmod(inverse(l), VK_INV_DOMAIN_SIZE, R_MOD), sub(expmod(zeta, VK_DOMAIN_SIZE), 1), R_MOD);
+    }
+    uint256 acc = 1;
+    for (uint256 i = 0; i < public_inputs.length; i++) {
+      res = addmod(res, mulmod(l, public_inputs[i], R_MOD), R_MOD);
+      l = mulmod(mulmod(l, sub(zeta, acc), R_MOD), VK_OMEGA, R_MOD);
+      acc = mulmod(acc, VK_OMEGA, R_MOD);
+      uint256 d = sub(zeta, acc);
+      if (d == 0) {
+        l = 1;
+      } else {
+        l = mulmod(l, inverse(d), R_MOD);
+      }
+    }
+  }
+
+  /// Hashes node with the siblings starting at offset, up to the root.
+  function computeMerkleRoot(bytes calldata proof, uint256 offset, bytes32 node, uint256 index, uint256 depth)
+  internal pure returns(bytes32) {
+    for (uint256 i = 0; i < depth; i++) {
+      bytes32 sibling = bytes32(load(proof, offset + 0x20 * i));
+      if (index & 1 == 0) {
+        node = sha256(abi.encodePacked(node, sibling));
+      } else {
+        node = sha256(abi.encodePacked(sibling, node));
+      }
+      index >>= 1;
+    }
+    return node;
+  }
+
+  /// @return the position of the i-th evaluation in the Merkle tree, where
+  /// the evaluations at x and -x are stored next to each other.
+  function sortedPosition(uint256 i, uint256 n) internal pure returns(uint256) {
+    if (i < n / 2) {
+      return 2 * i;
+    }
+    return 2 * i + 1 - n;
+  }
+
+  /// @return the size in bytes of the i-th folding step of a proof of
+  /// proximity: the Merkle root, the two opened values and the siblings.
+  function foldingStepSize(uint256 i) internal pure returns(uint256) {
+    return 0x20 * (FRI_DEPTH + 2 - i);
+  }
+
+  /// @return the name of the i-th folding challenge, "x{i}" padded with zeroes
+  function foldingChallengeName(uint256 i) internal pure returns(bytes32) {
+    if (i < 10) {
+      return bytes32(abi.encodePacked("x", bytes1(uint8(48 + i))));
+    }
+    return bytes32(abi.encodePacked("x", bytes1(uint8(48 + i / 10)), bytes1(uint8(48 + i % 10))));
+  }
+
+  function load(bytes calldata proof, uint256 offset) internal pure returns(uint256 res) {
+    assembly {
+      res := calldataload(add(proof.offset, offset))
+    }
+  }
+
+  function sub(uint256 a, uint256 b) internal pure returns(uint256) {
+    return addmod(a, R_MOD - b, R_MOD);
+  }
+
+  function inverse(uint256 x) internal view returns(uint256) {
+    return expmod(x, R_MOD - 2);
+  }
+
+  /// @return res x^e mod R_MOD, computed with the modexp precompile
+  function expmod(uint256 x, uint256 e) internal view returns(uint256 res) {
+    assembly {
+      let mPtr := mload(0x40)
+      mstore(mPtr, 0x20)
+      mstore(add(mPtr, 0x20), 0x20)
+      mstore(add(mPtr, 0x40), 0x20)
+      mstore(add(mPtr, 0x60), x)
+      mstore(add(mPtr, 0x80), e)
+      mstore(add(mPtr, 0xa0), R_MOD)
+      if iszero(staticcall(gas(), 0x05, mPtr, 0xc0, mPtr, 0x20)) {
+        revert(0, 0)
+      }
+      res := mload(mPtr)
+    }
+  }
+}
+`
+
+// solidityVerifyingKey holds the data of the verifying key, and the derived
+// FRI parameters, used to generate the Solidity verifier.
+type solidityVerifyingKey struct {
+	*VerifyingKey
+	TwoInv        fr.Element
+	FriDomainSize uint64
+	FriGenInv     fr.Element
+	FriNbSteps    int
+	FriDepth      int
+	FriShift      uint64
+	ProofSize     int
+
+	// Roots of ql, qr, qm, qo, qk, s1, s2, s3, id1, id2, id3
+	Roots [11][]byte
+}
+
+// ExportSolidity exports the verifying key to a solidity smart contract.
+//
+// The contract uses sha256 for both the challenges and the Merkle trees,
+// which are the default hash functions of the backend.
+//
+// Code has not been audited and is provided as-is, we make no guarantees or warranties to its safety and reliability.
+func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
+	data, err := vk.solidityData()
+	if err != nil {
+		return err
+	}
+
+	funcMap := template.FuncMap{
+		"hex": func(b []byte) string {
+			return fmt.Sprintf("0x%x", b)
+		},
+		"frstr": func(x fr.Element) string {
+			// we use big.Int to always get a positive string.
+			// not the most efficient hack, but it works better for .sol generation.
+			bv := new(big.Int)
+			x.BigInt(bv)
+			return bv.String()
+		},
+	}
+
+	t, err := template.New("t").Funcs(funcMap).Parse(tmplSolidityVerifier)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, data)
+}
+
+func (vk *VerifyingKey) solidityData() (solidityVerifyingKey, error) {
+	res := solidityVerifyingKey{VerifyingKey: vk}
+
+	// the iopp works on the next power of 2 after vk.Size+2, while the opening
+	// position is sampled in [0, 2*rho*vk.Size).
+	rho := uint64(fri.GetRho())
+	if ecc.NextPowerOfTwo(vk.Size+2) != 2*vk.Size {
+		return res, fmt.Errorf("domain size %d not supported", vk.Size)
+	}
+	res.FriDomainSize = 2 * rho * vk.Size
+	res.FriNbSteps = bits.TrailingZeros64(2 * vk.Size)
+	res.FriDepth = bits.TrailingZeros64(res.FriDomainSize)
+	res.FriShift = 2 * rho
+	res.FriGenInv.Set(&fft.NewDomain(res.FriDomainSize).GeneratorInv)
+	res.TwoInv.SetUint64(2).Inverse(&res.TwoInv)
+
+	// the preprocessed polynomials do not depend on the proof, so their
+	// proofs of proximity are checked once here instead of in the contract.
+	iopp := vk.newIopp(sha256.New())
+	pps := []*fri.ProofOfProximity{
+		&vk.Qpp[0], &vk.Qpp[1], &vk.Qpp[2], &vk.Qpp[3], &vk.Qpp[4],
+		&vk.Spp[0], &vk.Spp[1], &vk.Spp[2],
+		&vk.Idpp[0], &vk.Idpp[1], &vk.Idpp[2],
+	}
+	for i, pp := range pps {
+		if err := iopp.VerifyProofOfProximity(*pp); err != nil {
+			return res, fmt.Errorf("proof of proximity %d of the verifying key: %w", i, err)
+		}
+		res.Roots[i] = pp.Rounds[0].Interactions[0][0].MerkleRoot
+	}
+
+	// 7 proofs of proximity, each step containing the root, the two opened
+	// values and the siblings, followed by the final evaluation; then 19
+	// openings containing the value and the full Merkle path.
+	for i := 0; i < res.FriNbSteps; i++ {
+		res.ProofSize += fr.Bytes * (res.FriDepth + 2 - i)
+	}
+	res.ProofSize = 7*(res.ProofSize+fr.Bytes) + 19*fr.Bytes*(1+res.FriDepth)
+
+	return res, nil
+}
+
+// MarshalSolidity converts a proof to a byte array that can be used in a
+// Solidity contract. The proof must have been computed with sha256 as
+// hash function for the challenges and the Merkle trees.
+func (proof *Proof) MarshalSolidity() []byte {
+
+	res := make([]byte, 0, 1024)
+
+	// proofs of proximity of l, r, o, z, h1, h2, h3. For each folding step:
+	// the Merkle root, the values at the two positions of the queried pair,
+	// and the Merkle path of the pair.
+	pps := []*fri.ProofOfProximity{
+		&proof.LROpp[0], &proof.LROpp[1], &proof.LROpp[2],
+		&proof.Zpp,
+		&proof.Hpp[0], &proof.Hpp[1], &proof.Hpp[2],
+	}
+	for _, pp := range pps {
+		round := &pp.Rounds[0]
+		for _, interaction := range round.Interactions {
+			full := interaction[0]
+			if len(interaction[1].ProofSet) > len(full.ProofSet) {
+				full = interaction[1]
+			}
+			res = append(res, interaction[0].MerkleRoot...)
+			res = append(res, interaction[0].ProofSet[0]...)
+			res = append(res, interaction[1].ProofSet[0]...)
+			for _, sibling := range full.ProofSet[2:] {
+				res = append(res, sibling...)
+			}
+		}
+		res = append(res, round.Evaluation.Marshal()...)
+	}
+
+	// openings, the claimed value is the first element of the proof set.
+	openings := []*fri.OpeningProof{
+		&proof.OpeningsQlQrQmQoQkincompletemp[0],
+		&proof.OpeningsQlQrQmQoQkincompletemp[1],
+		&proof.OpeningsQlQrQmQoQkincompletemp[2],
+		&proof.OpeningsQlQrQmQoQkincompletemp[3],
+		&proof.OpeningsQlQrQmQoQkincompletemp[4],
+		&proof.OpeningsLROmp[0], &proof.OpeningsLROmp[1], &proof.OpeningsLROmp[2],
+		&proof.OpeningsHmp[0], &proof.OpeningsHmp[1], &proof.OpeningsHmp[2],
+		&proof.OpeningsS1S2S3mp[0], &proof.OpeningsS1S2S3mp[1], &proof.OpeningsS1S2S3mp[2],
+		&proof.OpeningsId1Id2Id3mp[0], &proof.OpeningsId1Id2Id3mp[1], &proof.OpeningsId1Id2Id3mp[2],
+		&proof.OpeningsZmp[0], &proof.OpeningsZmp[1],
+	}
+	for _, o := range openings {
+		for _, b := range o.ProofSet {
+			res = append(res, b...)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,26 @@
+// Package plonkfri implements PLONK over the Goldilocks field p = 2⁶⁴-2³²+1,
+// with FRI as polynomial commitment scheme.
+//
+// Contrary to the curve-specific PlonkFRI backends, the field elements fit in
+// a machine word, which makes the prover considerably faster. As 64 bits are
+// not enough for the soundness of the protocol, all the verifier challenges
+// are sampled in the quadratic extension 𝔽p[u]/(u²-7) (see [E2]).
+//
+// The protocol is the following:
+//   - the polynomials (selectors and permutation at setup, then l, r, o, z and
+//     the quotient h1, h2, h3) are committed with Merkle trees of their
+//     evaluations on a coset of size 8·n, n being the size of the circuit;
+//   - the PLONK relation is checked at a random out-of-domain point ζ, using
+//     the claimed evaluations of the polynomials;
+//   - the claimed evaluations are checked with a single FRI proof of proximity
+//     of the DEEP composition polynomial Σ λᵏ·(pₖ(X)-pₖ(ζ))/(X-ζ).
+//
+// With the blow-up factor 8 and 32 queries, the conjectured security is about
+// 96 bits. The polynomials are not blinded: the proofs are sound but NOT
+// zero-knowledge, they must not be used when the secret witness needs to stay
+// private. Prove returns an error unless this is acknowledged with the option
+// backend.WithNonZKProving.
+//
+// Circuits are compiled over Goldilocks by passing goldilocks.Modulus() to
+// frontend.Compile with the scs builder.
+package plonkfri
@@ -0,0 +1,160 @@
+package plonkfri
+
+import (
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+// nonResidue is the quadratic non-residue defining the extension field
+// 𝔽p[u]/(u²-7).
+const nonResidue = 7
+
+// E2 is an element of the quadratic extension 𝔽p[u]/(u²-7) of the Goldilocks
+// field. The verifier challenges are sampled in E2, as 64 bits of the base
+// field alone would not give enough soundness.
+type E2 struct {
+	A0, A1 goldilocks.Element
+}
+
+// E2Bytes is the size of a marshalled E2 element.
+const E2Bytes = 2 * goldilocks.Bytes
+
+// SetZero sets z to 0 and returns z.
+func (z *E2) SetZero() *E2 {
+	z.A0.SetZero()
+	z.A1.SetZero()
+	return z
+}
+
+// SetOne sets z to 1 and returns z.
+func (z *E2) SetOne() *E2 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	return z
+}
+
+// Set sets z to x and returns z.
+func (z *E2) Set(x *E2) *E2 {
+	z.A0.Set(&x.A0)
+	z.A1.Set(&x.A1)
+	return z
+}
+
+// SetBase sets z to the base field element x and returns z.
+func (z *E2) SetBase(x *goldilocks.Element) *E2 {
+	z.A0.Set(x)
+	z.A1.SetZero()
+	return z
+}
+
+// IsZero returns true if z == 0.
+func (z *E2) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero()
+}
+
+// Equal returns true if z == x.
+func (z *E2) Equal(x *E2) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1)
+}
+
+// Add sets z = x + y and returns z.
+func (z *E2) Add(x, y *E2) *E2 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *E2) Sub(x, y *E2) *E2 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *E2) Neg(x *E2) *E2 {
+	z.A0.Neg(&x.A0)
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *E2) Mul(x, y *E2) *E2 {
+	var a0, a1, t, nr goldilocks.Element
+	nr.SetUint64(nonResidue)
+	a0.Mul(&x.A0, &y.A0)
+	t.Mul(&x.A1, &y.A1).Mul(&t, &nr)
+	a0.Add(&a0, &t)
+	a1.Mul(&x.A0, &y.A1)
+	t.Mul(&x.A1, &y.A0)
+	a1.Add(&a1, &t)
+	z.A0, z.A1 = a0, a1
+	return z
+}
+
+// MulByElement sets z = x * y where y is in the base field and returns z.
+func (z *E2) MulByElement(x *E2, y *goldilocks.Element) *E2 {
+	z.A0.Mul(&x.A0, y)
+	z.A1.Mul(&x.A1, y)
+	return z
+}
+
+// AddElement sets z = x + y where y is in the base field and returns z.
+func (z *E2) AddElement(x *E2, y *goldilocks.Element) *E2 {
+	z.A0.Add(&x.A0, y)
+	z.A1.Set(&x.A1)
+	return z
+}
+
+// Square sets z = x² and returns z.
+func (z *E2) Square(x *E2) *E2 {
+	return z.Mul(x, x)
+}
+
+// Inverse sets z = 1/x and returns z. The inverse of 0 is 0.
+func (z *E2) Inverse(x *E2) *E2 {
+	// (a0 + a1·u)⁻¹ = (a0 - a1·u) / (a0² - 7·a1²)
+	var norm, t, nr goldilocks.Element
+	nr.SetUint64(nonResidue)
+	norm.Square(&x.A0)
+	t.Square(&x.A1).Mul(&t, &nr)
+	norm.Sub(&norm, &t).Inverse(&norm)
+	z.A0.Mul(&x.A0, &norm)
+	z.A1.Mul(&x.A1, &norm).Neg(&z.A1)
+	return z
+}
+
+// Exp sets z = xᵏ and returns z.
+func (z *E2) Exp(x E2, k uint64) *E2 {
+	var res E2
+	res.SetOne()
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			res.Mul(&res, &x)
+		}
+		x.Square(&x)
+	}
+	*z = res
+	return z
+}
+
+// Bytes returns the big-endian encoding of A0 followed by A1.
+func (z *E2) Bytes() (res [E2Bytes]byte) {
+	a0, a1 := z.A0.Bytes(), z.A1.Bytes()
+	copy(res[:goldilocks.Bytes], a0[:])
+	copy(res[goldilocks.Bytes:], a1[:])
+	return
+}
+
+// SetBytesCanonical sets z from the encoding returned by [E2.Bytes]. It
+// returns an error if one of the coordinates is not canonical.
+func (z *E2) SetBytesCanonical(b []byte) error {
+	if err := z.A0.SetBytesCanonical(b[:goldilocks.Bytes]); err != nil {
+		return err
+	}
+	return z.A1.SetBytesCanonical(b[goldilocks.Bytes:E2Bytes])
+}
+
+// String returns the decimal representation of z.
+func (z *E2) String() string {
+	return z.A0.String() + "+" + z.A1.String() + "*u"
+}
@@ -0,0 +1,129 @@
+package plonkfri
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+// multiplicativeGenerator generates the multiplicative group of the Goldilocks
+// field. It is used as coset shift, as it doesn't belong to any subgroup of
+// 2-power order.
+const multiplicativeGenerator = 7
+
+// maxOrderRoot is the 2-adicity of the Goldilocks field: p-1 = 2³²·(2³²-1).
+const maxOrderRoot = 32
+
+// domain is a multiplicative subgroup of order a power of 2.
+type domain struct {
+	Cardinality    uint64
+	CardinalityInv goldilocks.Element
+	Generator      goldilocks.Element
+	GeneratorInv   goldilocks.Element
+}
+
+// newDomain returns the subgroup of order the smallest power of 2 greater or
+// equal to m.
+func newDomain(m uint64) *domain {
+	n := uint64(1) << bits.Len64(m-1)
+	if bits.TrailingZeros64(n) > maxOrderRoot {
+		panic("goldilocks: domain too large")
+	}
+	var d domain
+	d.Cardinality = n
+	d.CardinalityInv.SetUint64(n).Inverse(&d.CardinalityInv)
+
+	// generator = g^((p-1)/n)
+	var g goldilocks.Element
+	g.SetUint64(multiplicativeGenerator)
+	e := new(big.Int).Sub(goldilocks.Modulus(), big.NewInt(1))
+	e.Rsh(e, uint(bits.TrailingZeros64(n)))
+	d.Generator.Exp(g, e)
+	d.GeneratorInv.Inverse(&d.Generator)
+	return &d
+}
+
+// fft replaces the coefficients of a by its evaluations on the domain, in
+// natural order. len(a) must be the cardinality of the domain.
+func (d *domain) fft(a []goldilocks.Element) {
+	ntt(a, d.Generator)
+}
+
+// fftInverse replaces the evaluations of a on the domain, in natural order, by
+// the coefficients of the interpolating polynomial.
+func (d *domain) fftInverse(a []goldilocks.Element) {
+	ntt(a, d.GeneratorInv)
+	for i := range a {
+		a[i].Mul(&a[i], &d.CardinalityInv)
+	}
+}
+
+// fftCoset replaces the coefficients of a by its evaluations on the coset
+// shift·<generator>, in natural order.
+func (d *domain) fftCoset(a []goldilocks.Element, shift goldilocks.Element) {
+	var acc goldilocks.Element
+	acc.SetOne()
+	for i := range a {
+		a[i].Mul(&a[i], &acc)
+		acc.Mul(&acc, &shift)
+	}
+	d.fft(a)
+}
+
+// fftInverseCoset replaces the evaluations of a on the coset
+// shift·<generator>, in natural order, by the coefficients of the
+// interpolating polynomial.
+func (d *domain) fftInverseCoset(a []goldilocks.Element, shift goldilocks.Element) {
+	d.fftInverse(a)
+	var shiftInv, acc goldilocks.Element
+	shiftInv.Inverse(&shift)
+	acc.SetOne()
+	for i := range a {
+		a[i].Mul(&a[i], &acc)
+		acc.Mul(&acc, &shiftInv)
+	}
+}
+
+// ntt evaluates in place the polynomial of coefficients a on the powers of w,
+// which must be a primitive len(a)-th root of unity.
+func ntt(a []goldilocks.Element, w goldilocks.Element) {
+	n := len(a)
+	if n <= 1 {
+		return
+	}
+	bitReverse(a)
+
+	// twiddles[i] = w^i for i < n/2
+	twiddles := make([]goldilocks.Element, n/2)
+	twiddles[0].SetOne()
+	for i := 1; i < len(twiddles); i++ {
+		twiddles[i].Mul(&twiddles[i-1], &w)
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		half := size >> 1
+		stride := n / size
+		for start := 0; start < n; start += size {
+			for j := 0; j < half; j++ {
+				var u, v goldilocks.Element
+				u = a[start+j]
+				v.Mul(&a[start+j+half], &twiddles[j*stride])
+				a[start+j].Add(&u, &v)
+				a[start+j+half].Sub(&u, &v)
+			}
+		}
+	}
+}
+
+// bitReverse permutes a, whose length is a power of 2, in bit-reversed order.
+func bitReverse(a []goldilocks.Element) {
+	n := uint64(len(a))
+	shift := 64 - uint64(bits.TrailingZeros64(n))
+	for i := uint64(0); i < n; i++ {
+		j := bits.Reverse64(i) >> shift
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+}
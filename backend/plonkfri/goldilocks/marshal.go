@@ -0,0 +1,389 @@
+package plonkfri
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+// encoder writes the binary encoding of the supported types to an io.Writer.
+// Slices are prefixed with their length as a big-endian uint32.
+type encoder struct {
+	w io.Writer
+	n int64
+}
+
+func (enc *encoder) write(b []byte) error {
+	n, err := enc.w.Write(b)
+	enc.n += int64(n)
+	return err
+}
+
+func (enc *encoder) writeLen(l int) error {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(l))
+	return enc.write(buf[:])
+}
+
+func (enc *encoder) encode(v interface{}) error {
+	switch t := v.(type) {
+	case uint64:
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], t)
+		return enc.write(buf[:])
+	case []byte:
+		if err := enc.writeLen(len(t)); err != nil {
+			return err
+		}
+		return enc.write(t)
+	case [][]byte:
+		if err := enc.writeLen(len(t)); err != nil {
+			return err
+		}
+		for i := range t {
+			if err := enc.encode(t[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *E2:
+		b := t.Bytes()
+		return enc.write(b[:])
+	case []E2:
+		if err := enc.writeLen(len(t)); err != nil {
+			return err
+		}
+		for i := range t {
+			if err := enc.encode(&t[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []goldilocks.Element:
+		if err := enc.writeLen(len(t)); err != nil {
+			return err
+		}
+		for i := range t {
+			b := t[i].Bytes()
+			if err := enc.write(b[:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []int64:
+		if err := enc.writeLen(len(t)); err != nil {
+			return err
+		}
+		for i := range t {
+			if err := enc.encode(uint64(t[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+// decoder reads the encoding written by encoder.
+type decoder struct {
+	r io.Reader
+	n int64
+}
+
+func (dec *decoder) read(b []byte) error {
+	n, err := io.ReadFull(dec.r, b)
+	dec.n += int64(n)
+	return err
+}
+
+func (dec *decoder) readLen() (int, error) {
+	var buf [4]byte
+	if err := dec.read(buf[:]); err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(buf[:])), nil
+}
+
+func (dec *decoder) decode(v interface{}) error {
+	switch t := v.(type) {
+	case *uint64:
+		var buf [8]byte
+		if err := dec.read(buf[:]); err != nil {
+			return err
+		}
+		*t = binary.BigEndian.Uint64(buf[:])
+		return nil
+	case *[]byte:
+		l, err := dec.readLen()
+		if err != nil {
+			return err
+		}
+		*t = make([]byte, l)
+		return dec.read(*t)
+	case *[][]byte:
+		l, err := dec.readLen()
+		if err != nil {
+			return err
+		}
+		*t = make([][]byte, l)
+		for i := range *t {
+			if err := dec.decode(&(*t)[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *E2:
+		var buf [E2Bytes]byte
+		if err := dec.read(buf[:]); err != nil {
+			return err
+		}
+		return t.SetBytesCanonical(buf[:])
+	case *[]E2:
+		l, err := dec.readLen()
+		if err != nil {
+			return err
+		}
+		*t = make([]E2, l)
+		for i := range *t {
+			if err := dec.decode(&(*t)[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *[]goldilocks.Element:
+		l, err := dec.readLen()
+		if err != nil {
+			return err
+		}
+		*t = make([]goldilocks.Element, l)
+		var buf [goldilocks.Bytes]byte
+		for i := range *t {
+			if err := dec.read(buf[:]); err != nil {
+				return err
+			}
+			if err := (*t)[i].SetBytesCanonical(buf[:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *[]int64:
+		l, err := dec.readLen()
+		if err != nil {
+			return err
+		}
+		*t = make([]int64, l)
+		for i := range *t {
+			var u uint64
+			if err := dec.decode(&u); err != nil {
+				return err
+			}
+			(*t)[i] = int64(u)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+// WriteTo writes binary encoding of Proof to w
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	enc := encoder{w: w}
+	for _, v := range proof.toEncode() {
+		if err := enc.encode(v); err != nil {
+			return enc.n, err
+		}
+	}
+	if err := enc.writeLen(len(proof.Queries)); err != nil {
+		return enc.n, err
+	}
+	for i := range proof.Queries {
+		qp := &proof.Queries[i]
+		if err := enc.writeLen(len(qp.Folds)); err != nil {
+			return enc.n, err
+		}
+		for _, v := range qp.toEncode() {
+			if err := enc.encode(v); err != nil {
+				return enc.n, err
+			}
+		}
+	}
+	return enc.n, nil
+}
+
+// WriteRawTo writes binary encoding of Proof to w. As the proof does not
+// contain curve points, it is the same as WriteTo.
+func (proof *Proof) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.WriteTo(w)
+}
+
+// ReadFrom reads binary representation of Proof from r
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	dec := decoder{r: r}
+	for _, v := range proof.toDecode() {
+		if err := dec.decode(v); err != nil {
+			return dec.n, err
+		}
+	}
+	nbQueries, err := dec.readLen()
+	if err != nil {
+		return dec.n, err
+	}
+	proof.Queries = make([]QueryProof, nbQueries)
+	for i := range proof.Queries {
+		qp := &proof.Queries[i]
+		nbFolds, err := dec.readLen()
+		if err != nil {
+			return dec.n, err
+		}
+		qp.Folds = make([]ExtOpening, nbFolds)
+		for _, v := range qp.toDecode() {
+			if err := dec.decode(v); err != nil {
+				return dec.n, err
+			}
+		}
+	}
+	return dec.n, nil
+}
+
+// UnsafeReadFrom reads binary representation of Proof from r. As the proof
+// does not contain curve points, it is the same as ReadFrom.
+func (proof *Proof) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return proof.ReadFrom(r)
+}
+
+func (proof *Proof) toEncode() []interface{} {
+	res := []interface{}{proof.LRO, proof.Z, proof.H}
+	for i := range proof.Evaluations {
+		res = append(res, &proof.Evaluations[i])
+	}
+	return append(res, &proof.ZShifted, proof.FoldRoots, &proof.FinalFold)
+}
+
+func (proof *Proof) toDecode() []interface{} {
+	res := []interface{}{&proof.LRO, &proof.Z, &proof.H}
+	for i := range proof.Evaluations {
+		res = append(res, &proof.Evaluations[i])
+	}
+	return append(res, &proof.ZShifted, &proof.FoldRoots, &proof.FinalFold)
+}
+
+func (qp *QueryProof) toEncode() []interface{} {
+	res := []interface{}{
+		qp.Preprocessed.Values[0], qp.Preprocessed.Values[1], qp.Preprocessed.Path,
+		qp.LRO.Values[0], qp.LRO.Values[1], qp.LRO.Path,
+		qp.Z.Values[0], qp.Z.Values[1], qp.Z.Path,
+		qp.H.Values[0], qp.H.Values[1], qp.H.Path,
+	}
+	for i := range qp.Folds {
+		res = append(res, qp.Folds[i].Values[0], qp.Folds[i].Values[1], qp.Folds[i].Path)
+	}
+	return res
+}
+
+func (qp *QueryProof) toDecode() []interface{} {
+	res := []interface{}{
+		&qp.Preprocessed.Values[0], &qp.Preprocessed.Values[1], &qp.Preprocessed.Path,
+		&qp.LRO.Values[0], &qp.LRO.Values[1], &qp.LRO.Path,
+		&qp.Z.Values[0], &qp.Z.Values[1], &qp.Z.Path,
+		&qp.H.Values[0], &qp.H.Values[1], &qp.H.Path,
+	}
+	for i := range qp.Folds {
+		res = append(res, &qp.Folds[i].Values[0], &qp.Folds[i].Values[1], &qp.Folds[i].Path)
+	}
+	return res
+}
+
+// WriteTo writes binary encoding of ProvingKey to w
+func (pk *ProvingKey) WriteTo(w io.Writer) (int64, error) {
+	n, err := pk.Vk.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	enc := encoder{w: w}
+	for i := range pk.Preprocessed {
+		if err := enc.encode(pk.Preprocessed[i]); err != nil {
+			return n + enc.n, err
+		}
+	}
+	err = enc.encode(pk.Permutation)
+	return n + enc.n, err
+}
+
+// WriteRawTo writes binary encoding of ProvingKey to w. As the proving key
+// does not contain curve points, it is the same as WriteTo.
+func (pk *ProvingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return pk.WriteTo(w)
+}
+
+// ReadFrom reads from binary representation in r into ProvingKey
+func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
+	pk.Vk = &VerifyingKey{}
+	n, err := pk.Vk.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	dec := decoder{r: r}
+	for i := range pk.Preprocessed {
+		if err := dec.decode(&pk.Preprocessed[i]); err != nil {
+			return n + dec.n, err
+		}
+		if len(pk.Preprocessed[i]) != int(pk.Vk.Size) {
+			return n + dec.n, errors.New("invalid polynomial size, expected domain cardinality")
+		}
+	}
+	if err := dec.decode(&pk.Permutation); err != nil {
+		return n + dec.n, err
+	}
+	if len(pk.Permutation) != 3*int(pk.Vk.Size) {
+		return n + dec.n, errors.New("invalid permutation size, expected 3*domain cardinality")
+	}
+	return n + dec.n, nil
+}
+
+// UnsafeReadFrom reads from binary representation in r into ProvingKey. As
+// the proving key does not contain curve points, it is the same as ReadFrom.
+func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return pk.ReadFrom(r)
+}
+
+// WriteTo writes binary encoding of VerifyingKey to w
+func (vk *VerifyingKey) WriteTo(w io.Writer) (int64, error) {
+	enc := encoder{w: w}
+	for _, v := range []interface{}{vk.Size, vk.NbPublicVariables, vk.PreprocessedRoot} {
+		if err := enc.encode(v); err != nil {
+			return enc.n, err
+		}
+	}
+	return enc.n, nil
+}
+
+// WriteRawTo writes binary encoding of VerifyingKey to w. As the verifying
+// key does not contain curve points, it is the same as WriteTo.
+func (vk *VerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return vk.WriteTo(w)
+}
+
+// ReadFrom reads from binary representation in r into VerifyingKey
+func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	dec := decoder{r: r}
+	for _, v := range []interface{}{&vk.Size, &vk.NbPublicVariables, &vk.PreprocessedRoot} {
+		if err := dec.decode(v); err != nil {
+			return dec.n, err
+		}
+	}
+	if vk.Size == 0 || vk.Size&(vk.Size-1) != 0 || vk.Size*rho > 1<<maxOrderRoot {
+		return dec.n, errors.New("invalid domain size")
+	}
+	return dec.n, nil
+}
+
+// UnsafeReadFrom reads from binary representation in r into VerifyingKey. As
+// the verifying key does not contain curve points, it is the same as
+// ReadFrom.
+func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return vk.ReadFrom(r)
+}
@@ -0,0 +1,17 @@
+package plonkfri
+
+import (
+	"testing"
+
+	"github.com/airchains-network/gnark/io"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerialization(t *testing.T) {
+	assert := require.New(t)
+	pk, proof, _ := prove(assert)
+
+	assert.NoError(io.RoundTripCheck(proof, func() interface{} { return new(Proof) }))
+	assert.NoError(io.RoundTripCheck(pk, func() interface{} { return new(ProvingKey) }))
+	assert.NoError(io.RoundTripCheck(pk.Vk, func() interface{} { return new(VerifyingKey) }))
+}
@@ -0,0 +1,85 @@
+package plonkfri
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+)
+
+var errInvalidMerklePath = errors.New("invalid Merkle path")
+
+// merkleTree is a binary Merkle tree whose leaves are arbitrary byte strings.
+// Leaves and internal nodes are domain separated to prevent second-preimage
+// attacks.
+type merkleTree struct {
+	// layers[0] contains the hashed leaves, layers[len(layers)-1] the root
+	layers [][][]byte
+}
+
+func hashLeaf(h hash.Hash, leaf []byte) []byte {
+	h.Reset()
+	h.Write([]byte{0})
+	h.Write(leaf)
+	return h.Sum(nil)
+}
+
+func hashNode(h hash.Hash, left, right []byte) []byte {
+	h.Reset()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// newMerkleTree builds the tree of the given leaves. The number of leaves must
+// be a power of 2.
+func newMerkleTree(h hash.Hash, leaves [][]byte) *merkleTree {
+	layer := make([][]byte, len(leaves))
+	for i := range leaves {
+		layer[i] = hashLeaf(h, leaves[i])
+	}
+	t := &merkleTree{layers: [][][]byte{layer}}
+	for len(layer) > 1 {
+		next := make([][]byte, len(layer)/2)
+		for i := range next {
+			next[i] = hashNode(h, layer[2*i], layer[2*i+1])
+		}
+		t.layers = append(t.layers, next)
+		layer = next
+	}
+	return t
+}
+
+// root returns the root of the tree.
+func (t *merkleTree) root() []byte {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// open returns the authentication path of the leaf at position index, from
+// the leaf to the root.
+func (t *merkleTree) open(index uint64) [][]byte {
+	path := make([][]byte, len(t.layers)-1)
+	for i := range path {
+		path[i] = t.layers[i][index^1]
+		index >>= 1
+	}
+	return path
+}
+
+// verifyMerklePath checks that leaf is at position index in the tree of the
+// given root.
+func verifyMerklePath(h hash.Hash, root, leaf []byte, index uint64, path [][]byte) error {
+	cur := hashLeaf(h, leaf)
+	for i := range path {
+		if index&1 == 0 {
+			cur = hashNode(h, cur, path[i])
+		} else {
+			cur = hashNode(h, path[i], cur)
+		}
+		index >>= 1
+	}
+	if index != 0 || !bytes.Equal(cur, root) {
+		return errInvalidMerklePath
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package plonkfri
+
+import (
+	"hash"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+// BaseOpening is the opening of a batch of base field polynomials at a pair of
+// opposite points x, -x of the evaluation domain.
+type BaseOpening struct {
+	// Values[0][k] (resp. Values[1][k]) is the evaluation of the k-th
+	// polynomial at x (resp. -x)
+	Values [2][]goldilocks.Element
+
+	// Path is the Merkle authentication path of the leaf
+	Path [][]byte
+}
+
+// ExtOpening is the opening of a batch of extension field polynomials at a pair
+// of opposite points x, -x of the evaluation domain.
+type ExtOpening struct {
+	// Values[0][k] (resp. Values[1][k]) is the evaluation of the k-th
+	// polynomial at x (resp. -x)
+	Values [2][]E2
+
+	// Path is the Merkle authentication path of the leaf
+	Path [][]byte
+}
+
+// baseOracle is a Merkle commitment to the evaluations of a batch of base field
+// polynomials on a domain of size m. Leaf i, for i < m/2, contains the
+// evaluations at the opposite points x_i and x_{i+m/2} = -x_i, so that a
+// single path opens the pair needed by a FRI folding step.
+type baseOracle struct {
+	evaluations [][]goldilocks.Element
+	tree        *merkleTree
+}
+
+func baseLeaf(values [2][]goldilocks.Element) []byte {
+	res := make([]byte, 0, 2*len(values[0])*goldilocks.Bytes)
+	for _, side := range values {
+		for i := range side {
+			b := side[i].Bytes()
+			res = append(res, b[:]...)
+		}
+	}
+	return res
+}
+
+func newBaseOracle(h hash.Hash, evaluations [][]goldilocks.Element) *baseOracle {
+	half := len(evaluations[0]) / 2
+	leaves := make([][]byte, half)
+	for i := range leaves {
+		leaves[i] = baseLeaf(baseValues(evaluations, i, half))
+	}
+	return &baseOracle{evaluations: evaluations, tree: newMerkleTree(h, leaves)}
+}
+
+func baseValues(evaluations [][]goldilocks.Element, i, half int) (res [2][]goldilocks.Element) {
+	res[0] = make([]goldilocks.Element, len(evaluations))
+	res[1] = make([]goldilocks.Element, len(evaluations))
+	for k := range evaluations {
+		res[0][k] = evaluations[k][i]
+		res[1][k] = evaluations[k][i+half]
+	}
+	return
+}
+
+func (o *baseOracle) open(i uint64) BaseOpening {
+	return BaseOpening{
+		Values: baseValues(o.evaluations, int(i), len(o.evaluations[0])/2),
+		Path:   o.tree.open(i),
+	}
+}
+
+// extOracle is the extension field counterpart of baseOracle.
+type extOracle struct {
+	evaluations [][]E2
+	tree        *merkleTree
+}
+
+func extLeaf(values [2][]E2) []byte {
+	res := make([]byte, 0, 2*len(values[0])*E2Bytes)
+	for _, side := range values {
+		for i := range side {
+			b := side[i].Bytes()
+			res = append(res, b[:]...)
+		}
+	}
+	return res
+}
+
+func newExtOracle(h hash.Hash, evaluations [][]E2) *extOracle {
+	half := len(evaluations[0]) / 2
+	leaves := make([][]byte, half)
+	for i := range leaves {
+		leaves[i] = extLeaf(extValues(evaluations, i, half))
+	}
+	return &extOracle{evaluations: evaluations, tree: newMerkleTree(h, leaves)}
+}
+
+func extValues(evaluations [][]E2, i, half int) (res [2][]E2) {
+	res[0] = make([]E2, len(evaluations))
+	res[1] = make([]E2, len(evaluations))
+	for k := range evaluations {
+		res[0][k] = evaluations[k][i]
+		res[1][k] = evaluations[k][i+half]
+	}
+	return
+}
+
+func (o *extOracle) open(i uint64) ExtOpening {
+	return ExtOpening{
+		Values: extValues(o.evaluations, int(i), len(o.evaluations[0])/2),
+		Path:   o.tree.open(i),
+	}
+}
+
+// lde returns the evaluations on the coset shift·<d.Generator> of the
+// polynomial of coefficients p, which must not be larger than the domain.
+func (d *domain) lde(p []goldilocks.Element, shift goldilocks.Element) []goldilocks.Element {
+	res := make([]goldilocks.Element, d.Cardinality)
+	copy(res, p)
+	d.fftCoset(res, shift)
+	return res
+}
+
+// ldeExt is the extension field counterpart of lde.
+func (d *domain) ldeExt(p []E2, shift goldilocks.Element) []E2 {
+	a0, a1 := splitExt(p)
+	return joinExt(d.lde(a0, shift), d.lde(a1, shift))
+}
+
+// interpolateExt returns the coefficients of the polynomial whose evaluations
+// on the domain are p.
+func (d *domain) interpolateExt(p []E2) []E2 {
+	a0, a1 := splitExt(p)
+	d.fftInverse(a0)
+	d.fftInverse(a1)
+	return joinExt(a0, a1)
+}
+
+func splitExt(p []E2) (a0, a1 []goldilocks.Element) {
+	a0 = make([]goldilocks.Element, len(p))
+	a1 = make([]goldilocks.Element, len(p))
+	for i := range p {
+		a0[i], a1[i] = p[i].A0, p[i].A1
+	}
+	return
+}
+
+func joinExt(a0, a1 []goldilocks.Element) []E2 {
+	res := make([]E2, len(a0))
+	for i := range res {
+		res[i].A0, res[i].A1 = a0[i], a1[i]
+	}
+	return res
+}
+
+// evalBase evaluates the base field polynomial of coefficients p at x.
+func evalBase(p []goldilocks.Element, x *E2) E2 {
+	var res E2
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, x).AddElement(&res, &p[i])
+	}
+	return res
+}
+
+// evalExt evaluates the extension field polynomial of coefficients p at x.
+func evalExt(p []E2, x *E2) E2 {
+	var res E2
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, x).Add(&res, &p[i])
+	}
+	return res
+}
@@ -0,0 +1,102 @@
+package plonkfri
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/backend"
+	cs "github.com/airchains-network/gnark/constraint/goldilocks"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/stretchr/testify/require"
+)
+
+type circuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *circuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(api.Add(x3, c.Y, 5), c.Z)
+	api.AssertIsDifferent(c.X, c.Y)
+	return nil
+}
+
+func prove(assert *require.Assertions) (*ProvingKey, *Proof, goldilocks.Vector) {
+	ccs, err := frontend.Compile(goldilocks.Modulus(), scs.NewBuilder, &circuit{})
+	assert.NoError(err)
+	spr := ccs.(*cs.SparseR1CS)
+
+	pk, _, err := Setup(spr)
+	assert.NoError(err)
+
+	w, err := frontend.NewWitness(&circuit{X: 3, Y: 4, Z: 36}, goldilocks.Modulus())
+	assert.NoError(err)
+	_, err = Prove(spr, pk, w)
+	assert.ErrorIs(err, ErrNotZeroKnowledge)
+	proof, err := Prove(spr, pk, w, backend.WithNonZKProving())
+	assert.NoError(err)
+
+	pw, err := w.Public()
+	assert.NoError(err)
+	return pk, proof, pw.Vector().(goldilocks.Vector)
+}
+
+func TestProveVerify(t *testing.T) {
+	assert := require.New(t)
+	pk, proof, public := prove(assert)
+	vk := pk.Vk
+	assert.NoError(Verify(proof, vk, public))
+
+	// wrong public input
+	var wrong goldilocks.Vector = []goldilocks.Element{goldilocks.NewElement(37)}
+	assert.ErrorIs(Verify(proof, vk, wrong), ErrInvalidAlgebraicRelation)
+
+	// wrong claimed evaluation
+	proof.Evaluations[8].A1.SetOne()
+	assert.Error(Verify(proof, vk, public))
+}
+
+func TestTamperedQuery(t *testing.T) {
+	assert := require.New(t)
+	pk, proof, public := prove(assert)
+	vk := pk.Vk
+	proof.Queries[3].LRO.Values[0][1].SetUint64(1)
+	assert.Error(Verify(proof, vk, public))
+}
+
+func TestE2Inverse(t *testing.T) {
+	assert := require.New(t)
+	var nr goldilocks.Element
+	nr.SetUint64(nonResidue)
+	assert.Equal(-1, nr.Legendre(), "7 must be a non-residue")
+
+	var a, b, c E2
+	a.A0.SetRandom()
+	a.A1.SetRandom()
+	b.Inverse(&a)
+	c.Mul(&a, &b)
+	assert.True(c.Equal(new(E2).SetOne()))
+}
+
+func TestFFT(t *testing.T) {
+	assert := require.New(t)
+	d := newDomain(16)
+	p := make([]goldilocks.Element, 16)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	shift := cosetShift()
+	evaluations := d.lde(p, shift)
+
+	// compare with a direct evaluation
+	var x E2
+	x.A0.Exp(d.Generator, big.NewInt(5)).Mul(&x.A0, &shift)
+	expected := evalBase(p, &x)
+	assert.True(expected.Equal(new(E2).SetBase(&evaluations[5])))
+
+	d.fftInverseCoset(evaluations, shift)
+	assert.Equal(p, evaluations)
+}
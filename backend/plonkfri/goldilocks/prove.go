@@ -0,0 +1,425 @@
+package plonkfri
+
+import (
+	"hash"
+	"math/big"
+	"math/bits"
+
+	"github.com/airchains-network/gnark/backend"
+	"github.com/airchains-network/gnark/backend/witness"
+	cs "github.com/airchains-network/gnark/constraint/goldilocks"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+type Proof struct {
+	// Merkle roots of the commitments to l, r, o, to z (permutation
+	// polynomial) and to h1, h2, h3 such that h = h1 + X**n*h2 + X**2n*h3 is
+	// the quotient polynomial
+	LRO, Z, H []byte
+
+	// evaluations at zeta of ql, qr, qm, qo, qk, s1, s2, s3, l, r, o, z, h1,
+	// h2, h3
+	Evaluations [nbOpened]E2
+
+	// evaluation of z at zeta*omega
+	ZShifted E2
+
+	// Merkle roots of the folded FRI layers. The first layer is not
+	// committed, the verifier computes it from the openings of the other
+	// commitments.
+	FoldRoots [][]byte
+
+	// value of the last FRI layer, which is a constant polynomial
+	FinalFold E2
+
+	// openings at the positions queried by the verifier
+	Queries []QueryProof
+}
+
+// QueryProof contains the openings of all the commitments at one FRI query.
+type QueryProof struct {
+	// openings of ql, qr, qm, qo, qk, s1, s2, s3 and l, r, o
+	Preprocessed, LRO BaseOpening
+
+	// openings of z and h1, h2, h3
+	Z, H ExtOpening
+
+	// openings of the folded FRI layers
+	Folds []ExtOpening
+}
+
+// Prove generates PLONK proof from a circuit, associated preprocessed public
+// data, and the witness.
+//
+// The proof is not zero-knowledge, so Prove returns [ErrNotZeroKnowledge]
+// unless the option [backend.WithNonZKProving] is given.
+func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !opt.NonZK {
+		return nil, ErrNotZeroKnowledge
+	}
+
+	var proof Proof
+	vk := pk.Vk
+	smallDomain, bigDomain := vk.domains()
+	shift := cosetShift()
+	nbFolds := bits.TrailingZeros64(vk.Size)
+
+	// 0 - Fiat Shamir
+	fs := fiatshamir.NewTranscript(opt.ChallengeHash, challengeNames(nbFolds)...)
+
+	// 1 - solve the system
+	_solution, err := spr.Solve(fullWitness, opt.SolverOpts...)
+	if err != nil {
+		return nil, err
+	}
+	solution := _solution.(*cs.SparseR1CSSolution)
+	lro := [3][]goldilocks.Element{solution.L, solution.R, solution.O}
+
+	// 2 - commit to l, r, o
+	var lroCanonical [3][]goldilocks.Element
+	lroEvaluations := make([][]goldilocks.Element, 3)
+	for k := range lro {
+		lroCanonical[k] = make([]goldilocks.Element, vk.Size)
+		copy(lroCanonical[k], lro[k])
+		smallDomain.fftInverse(lroCanonical[k])
+		lroEvaluations[k] = bigDomain.lde(lroCanonical[k], shift)
+	}
+	lroOracle := newBaseOracle(opt.IOPPHash, lroEvaluations)
+	proof.LRO = lroOracle.tree.root()
+
+	// 3 - compute and commit to z, challenges are derived using l, r, o +
+	// public inputs
+	fw, ok := fullWitness.Vector().(goldilocks.Vector)
+	if !ok {
+		return nil, witness.ErrInvalidWitness
+	}
+	public := fw[:len(spr.Public)]
+	beta, err := deriveRandomness(&fs, "beta", publicInputsBytes(public), proof.LRO)
+	if err != nil {
+		return nil, err
+	}
+	gamma, err := deriveRandomness(&fs, "gamma")
+	if err != nil {
+		return nil, err
+	}
+	zCanonical := smallDomain.interpolateExt(computeZ(lro, pk, smallDomain, beta, gamma))
+	zOracle := newExtOracle(opt.IOPPHash, [][]E2{bigDomain.ldeExt(zCanonical, shift)})
+	proof.Z = zOracle.tree.root()
+
+	// 4 - compute and commit to h
+	alpha, err := deriveRandomness(&fs, "alpha", proof.Z)
+	if err != nil {
+		return nil, err
+	}
+	preprocessedOracle := pk.commitPreprocessed(opt.IOPPHash)
+	hCanonical := computeH(pk, public, preprocessedOracle, lroOracle, zOracle, alpha, beta, gamma)
+	hEvaluations := make([][]E2, 3)
+	for k := range hCanonical {
+		hEvaluations[k] = bigDomain.ldeExt(hCanonical[k], shift)
+	}
+	hOracle := newExtOracle(opt.IOPPHash, hEvaluations)
+	proof.H = hOracle.tree.root()
+
+	// 5 - evaluate all the polynomials at zeta, and z at zeta*omega
+	zeta, err := deriveRandomness(&fs, "zeta", proof.H)
+	if err != nil {
+		return nil, err
+	}
+	for k := range pk.Preprocessed {
+		proof.Evaluations[k] = evalBase(pk.Preprocessed[k], &zeta)
+	}
+	for k := range lroCanonical {
+		proof.Evaluations[nbPreprocessed+k] = evalBase(lroCanonical[k], &zeta)
+	}
+	proof.Evaluations[nbPreprocessed+3] = evalExt(zCanonical, &zeta)
+	for k := range hCanonical {
+		proof.Evaluations[nbPreprocessed+4+k] = evalExt(hCanonical[k], &zeta)
+	}
+	var zetaShifted E2
+	zetaShifted.MulByElement(&zeta, &smallDomain.Generator)
+	proof.ZShifted = evalExt(zCanonical, &zetaShifted)
+
+	// 6 - FRI on the DEEP composition polynomial
+	lambda, err := deriveRandomness(&fs, "lambda", proof.evaluationsBytes())
+	if err != nil {
+		return nil, err
+	}
+	layer := computeDeep(&proof, bigDomain, preprocessedOracle, lroOracle, zOracle, hOracle, zeta, zetaShifted, lambda)
+	layerShift, layerGen := shift, bigDomain.Generator
+	foldOracles := make([]*extOracle, 0, nbFolds)
+	for r := 0; r < nbFolds; r++ {
+		var data [][]byte
+		if r > 0 {
+			o := newExtOracle(opt.IOPPHash, [][]E2{layer})
+			foldOracles = append(foldOracles, o)
+			proof.FoldRoots = append(proof.FoldRoots, o.tree.root())
+			data = append(data, o.tree.root())
+		}
+		foldChallenge, err := deriveRandomness(&fs, foldName(r), data...)
+		if err != nil {
+			return nil, err
+		}
+		layer = foldLayer(layer, layerShift, layerGen, foldChallenge)
+		layerShift.Square(&layerShift)
+		layerGen.Square(&layerGen)
+	}
+	proof.FinalFold = layer[0]
+
+	// 7 - open the commitments at the queried positions
+	half := bigDomain.Cardinality / 2
+	proof.Queries = make([]QueryProof, nbQueries)
+	for q := range proof.Queries {
+		var data [][]byte
+		if q == 0 {
+			b := proof.FinalFold.Bytes()
+			data = append(data, b[:])
+		}
+		idx, err := deriveQuery(&fs, queryName(q), half, data...)
+		if err != nil {
+			return nil, err
+		}
+		qp := &proof.Queries[q]
+		qp.Preprocessed = preprocessedOracle.open(idx)
+		qp.LRO = lroOracle.open(idx)
+		qp.Z = zOracle.open(idx)
+		qp.H = hOracle.open(idx)
+		qp.Folds = make([]ExtOpening, len(foldOracles))
+		for r := range foldOracles {
+			idx %= half >> (r + 1)
+			qp.Folds[r] = foldOracles[r].open(idx)
+		}
+	}
+
+	return &proof, nil
+}
+
+// commitPreprocessed commits to ql, qr, qm, qo, qk, s1, s2, s3.
+func (pk *ProvingKey) commitPreprocessed(h hash.Hash) *baseOracle {
+	_, bigDomain := pk.Vk.domains()
+	evaluations := make([][]goldilocks.Element, nbPreprocessed)
+	for k := range pk.Preprocessed {
+		evaluations[k] = bigDomain.lde(pk.Preprocessed[k], cosetShift())
+	}
+	return newBaseOracle(h, evaluations)
+}
+
+// computeZ computes Z, in Lagrange basis. Z is the accumulation of the
+// partial ratios of the permutation argument:
+//
+//	Z(ω^{i+1}) = Z(ω^i) · Π_k (w_k(ω^i)+β·id_k(ω^i)+γ) / (w_k(ω^i)+β·σ_k(ω^i)+γ)
+//
+// with Z(1) = 1.
+func computeZ(lro [3][]goldilocks.Element, pk *ProvingKey, d *domain, beta, gamma E2) []E2 {
+	n := int(d.Cardinality)
+	id := getIDSmallDomain(d)
+	z := make([]E2, n)
+	z[0].SetOne()
+	for i := 0; i < n-1; i++ {
+		var num, den, t E2
+		num.SetOne()
+		den.SetOne()
+		for k := 0; k < 3; k++ {
+			t.MulByElement(&beta, &id[k*n+i]).Add(&t, &gamma).AddElement(&t, &lro[k][i])
+			num.Mul(&num, &t)
+			t.MulByElement(&beta, &id[pk.Permutation[k*n+i]]).Add(&t, &gamma).AddElement(&t, &lro[k][i])
+			den.Mul(&den, &t)
+		}
+		den.Inverse(&den)
+		z[i+1].Mul(&z[i], &num).Mul(&z[i+1], &den)
+	}
+	return z
+}
+
+// computeH computes the canonical forms of h1, h2, h3 such that
+//
+//	h1 + X^n·h2 + X^{2n}·h3 = (gate + α·permutation + α²·(z-1)·L₀) / (X^n-1)
+//
+// where the numerator is computed on the evaluation domain.
+func computeH(pk *ProvingKey, public []goldilocks.Element, preprocessed, lro *baseOracle, z *extOracle, alpha, beta, gamma E2) [3][]E2 {
+	smallDomain, bigDomain := pk.Vk.domains()
+	n, m := int(smallDomain.Cardinality), int(bigDomain.Cardinality)
+	shift := cosetShift()
+
+	// public inputs polynomial, pi(ω^i) = w_i
+	pi := make([]goldilocks.Element, n)
+	copy(pi, public)
+	smallDomain.fftInverse(pi)
+	pi = bigDomain.lde(pi, shift)
+
+	// x_i, 1/(x_i-1) and 1/(x_i^n-1). x_i^n takes only rho different values.
+	x := make([]goldilocks.Element, m)
+	xMinusOne := make([]goldilocks.Element, m)
+	var one goldilocks.Element
+	one.SetOne()
+	x[0].Set(&shift)
+	for i := 0; i < m; i++ {
+		if i > 0 {
+			x[i].Mul(&x[i-1], &bigDomain.Generator)
+		}
+		xMinusOne[i].Sub(&x[i], &one)
+	}
+	xMinusOneInv := goldilocks.BatchInvert(xMinusOne)
+	zh := make([]goldilocks.Element, rho)
+	nBig := new(big.Int).SetUint64(uint64(n))
+	for i := range zh {
+		zh[i].Exp(x[i], nBig).Sub(&zh[i], &one)
+	}
+	zhInv := goldilocks.BatchInvert(zh)
+
+	var nInv goldilocks.Element
+	nInv.Set(&smallDomain.CardinalityInv)
+	var k1, k2 goldilocks.Element
+	k1.Set(&shift)
+	k2.Square(&shift)
+
+	var alphaSquare E2
+	alphaSquare.Square(&alpha)
+
+	ql, qr, qm, qo, qk := preprocessed.evaluations[0], preprocessed.evaluations[1], preprocessed.evaluations[2], preprocessed.evaluations[3], preprocessed.evaluations[4]
+	s1, s2, s3 := preprocessed.evaluations[5], preprocessed.evaluations[6], preprocessed.evaluations[7]
+	l, r, o := lro.evaluations[0], lro.evaluations[1], lro.evaluations[2]
+	zEval := z.evaluations[0]
+
+	t := make([]E2, m)
+	for i := 0; i < m; i++ {
+		// gate constraint, in the base field
+		var gate, tmp goldilocks.Element
+		gate.Mul(&ql[i], &l[i])
+		tmp.Mul(&qr[i], &r[i])
+		gate.Add(&gate, &tmp)
+		tmp.Mul(&qm[i], &l[i]).Mul(&tmp, &r[i])
+		gate.Add(&gate, &tmp)
+		tmp.Mul(&qo[i], &o[i])
+		gate.Add(&gate, &tmp)
+		gate.Add(&gate, &qk[i]).Add(&gate, &pi[i])
+
+		// permutation constraint
+		var num, den, u E2
+		num.Set(&zEval[i])
+		den.Set(&zEval[(i+rho)%m])
+		ids := [3]goldilocks.Element{x[i], x[i], x[i]}
+		ids[1].Mul(&ids[1], &k1)
+		ids[2].Mul(&ids[2], &k2)
+		sigmas := [3]*goldilocks.Element{&s1[i], &s2[i], &s3[i]}
+		wires := [3]*goldilocks.Element{&l[i], &r[i], &o[i]}
+		for k := 0; k < 3; k++ {
+			u.MulByElement(&beta, &ids[k]).Add(&u, &gamma).AddElement(&u, wires[k])
+			num.Mul(&num, &u)
+			u.MulByElement(&beta, sigmas[k]).Add(&u, &gamma).AddElement(&u, wires[k])
+			den.Mul(&den, &u)
+		}
+		var perm E2
+		perm.Sub(&den, &num)
+
+		// boundary constraint z(1) = 1
+		var boundary, lagrangeZero E2
+		lagrangeZero.A0.Mul(&zh[i%rho], &xMinusOneInv[i]).Mul(&lagrangeZero.A0, &nInv)
+		boundary.Sub(&zEval[i], new(E2).SetOne()).Mul(&boundary, &lagrangeZero)
+
+		t[i].Mul(&boundary, &alpha).Add(&t[i], &perm).Mul(&t[i], &alpha).AddElement(&t[i], &gate)
+		t[i].MulByElement(&t[i], &zhInv[i%rho])
+	}
+
+	// back to canonical form, deg(t) < 3n
+	a0, a1 := splitExt(t)
+	bigDomain.fftInverseCoset(a0, shift)
+	bigDomain.fftInverseCoset(a1, shift)
+	t = joinExt(a0, a1)
+
+	return [3][]E2{t[:n], t[n : 2*n], t[2*n : 3*n]}
+}
+
+// computeDeep returns the evaluations on the evaluation domain of the DEEP
+// composition polynomial
+//
+//	Σ_k λ^k·(p_k(X)-p_k(ζ))/(X-ζ) + λ^K·(z(X)-z(ζω))/(X-ζω)
+//
+// where p_k ranges over the opened polynomials. It is of degree < n if all the
+// claimed evaluations are correct.
+func computeDeep(proof *Proof, d *domain, preprocessed, lro *baseOracle, z, h *extOracle, zeta, zetaShifted, lambda E2) []E2 {
+	m := int(d.Cardinality)
+	x := make([]goldilocks.Element, m)
+	x[0] = cosetShift()
+	for i := 1; i < m; i++ {
+		x[i].Mul(&x[i-1], &d.Generator)
+	}
+
+	res := make([]E2, m)
+	base := make([]goldilocks.Element, 0, nbPreprocessed+3)
+	ext := make([]E2, 0, 4)
+	for i := 0; i < m; i++ {
+		base = base[:0]
+		for k := range preprocessed.evaluations {
+			base = append(base, preprocessed.evaluations[k][i])
+		}
+		for k := range lro.evaluations {
+			base = append(base, lro.evaluations[k][i])
+		}
+		ext = append(ext[:0], z.evaluations[0][i])
+		for k := range h.evaluations {
+			ext = append(ext, h.evaluations[k][i])
+		}
+		res[i] = deepValue(proof, &x[i], base, ext, &zeta, &zetaShifted, &lambda)
+	}
+	return res
+}
+
+// deepValue returns the value at x of the DEEP composition polynomial, from
+// the evaluations at x of the base field polynomials (ql, qr, qm, qo, qk, s1,
+// s2, s3, l, r, o) and of the extension field ones (z, h1, h2, h3).
+func deepValue(proof *Proof, x *goldilocks.Element, base []goldilocks.Element, ext []E2, zeta, zetaShifted, lambda *E2) E2 {
+	var res, t, lambdaPow E2
+	lambdaPow.SetOne()
+	for k := range base {
+		t.Neg(&proof.Evaluations[k]).AddElement(&t, &base[k]).Mul(&t, &lambdaPow)
+		res.Add(&res, &t)
+		lambdaPow.Mul(&lambdaPow, lambda)
+	}
+	for k := range ext {
+		t.Sub(&ext[k], &proof.Evaluations[len(base)+k]).Mul(&t, &lambdaPow)
+		res.Add(&res, &t)
+		lambdaPow.Mul(&lambdaPow, lambda)
+	}
+	var den E2
+	den.Neg(zeta).AddElement(&den, x).Inverse(&den)
+	res.Mul(&res, &den)
+
+	t.Sub(&ext[0], &proof.ZShifted).Mul(&t, &lambdaPow)
+	den.Neg(zetaShifted).AddElement(&den, x).Inverse(&den)
+	t.Mul(&t, &den)
+	return *res.Add(&res, &t)
+}
+
+// foldLayer folds the evaluations of f on the coset shift·<gen> into the
+// evaluations of f_e + β·f_o on the squared coset, where f(X) = f_e(X²) +
+// X·f_o(X²).
+func foldLayer(evaluations []E2, shift, gen goldilocks.Element, beta E2) []E2 {
+	half := len(evaluations) / 2
+	xInv := make([]goldilocks.Element, half)
+	xInv[0].Set(&shift)
+	for i := 1; i < half; i++ {
+		xInv[i].Mul(&xInv[i-1], &gen)
+	}
+	xInv = goldilocks.BatchInvert(xInv)
+	res := make([]E2, half)
+	for i := range res {
+		res[i] = foldPair(&evaluations[i], &evaluations[i+half], &xInv[i], &beta)
+	}
+	return res
+}
+
+// foldPair returns (a+b)/2 + β·(a-b)/(2x), where a = f(x) and b = f(-x).
+func foldPair(a, b *E2, xInv *goldilocks.Element, beta *E2) E2 {
+	var even, odd E2
+	even.Add(a, b)
+	odd.Sub(a, b).MulByElement(&odd, xInv).Mul(&odd, beta)
+	even.Add(&even, &odd)
+	even.A0.Halve()
+	even.A1.Halve()
+	return even
+}
@@ -0,0 +1,232 @@
+package plonkfri
+
+import (
+	"fmt"
+
+	"github.com/airchains-network/gnark/backend"
+	cs "github.com/airchains-network/gnark/constraint/goldilocks"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+const (
+	// rho is the blow-up factor of the evaluation domain used by FRI.
+	rho = 8
+
+	// nbQueries is the number of FRI queries. With rho = 8, each query adds
+	// about log2(rho) = 3 bits of (conjectured) security.
+	nbQueries = 32
+
+	// nbPreprocessed is the number of polynomials committed at setup: ql, qr,
+	// qm, qo, qk, s1, s2, s3.
+	nbPreprocessed = 8
+
+	// nbOpened is the number of polynomials opened at zeta: the preprocessed
+	// polynomials, l, r, o, z, h1, h2, h3.
+	nbOpened = nbPreprocessed + 3 + 1 + 3
+)
+
+// ProvingKey stores the data needed to generate a proof:
+// * the verifying key
+// * the canonical forms of ql, qr, qm, qo, qk (incomplete), s1, s2, s3, where
+// ql is prepended with as many minus ones as there are public inputs, and the
+// other selectors with as many zeroes
+// * the copy constraint permutation
+type ProvingKey struct {
+	// Verifying Key is embedded into the proving key (needed by Prove)
+	Vk *VerifyingKey
+
+	// canonical forms of ql, qr, qm, qo, qk, s1, s2, s3
+	Preprocessed [nbPreprocessed][]goldilocks.Element
+
+	// position -> permuted position (position in [0,3*sizeSystem-1])
+	Permutation []int64
+}
+
+// VerifyingKey stores the data needed to verify a proof:
+// * the size of the circuit and the number of public inputs
+// * the Merkle root of the evaluations of ql, qr, qm, qo, qk, s1, s2, s3 on
+// the evaluation domain
+type VerifyingKey struct {
+	// Size circuit, that is the closest power of 2 bounding above
+	// number of constraints+number of public inputs
+	Size              uint64
+	NbPublicVariables uint64
+
+	// PreprocessedRoot is the Merkle root of the commitment to ql, qr, qm, qo,
+	// qk, s1, s2, s3
+	PreprocessedRoot []byte
+}
+
+// Setup sets proving and verifying keys. Among the prover options, only the
+// hash function used for the Merkle commitments (see
+// [backend.WithProverIOPPHashFunction]) is relevant at setup, and the same
+// option must be given to Prove and Verify.
+//
+// The setup is transparent, it doesn't involve any secret.
+func Setup(spr *cs.SparseR1CS, opts ...backend.ProverOption) (*ProvingKey, *VerifyingKey, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create backend config: %w", err)
+	}
+
+	var pk ProvingKey
+	var vk VerifyingKey
+	pk.Vk = &vk
+
+	nbConstraints := spr.GetNbConstraints()
+	sizeSystem := uint64(nbConstraints + len(spr.Public)) // len(spr.Public) is for the placeholder constraints
+	vk.Size = ecc.NextPowerOfTwo(sizeSystem)
+	vk.NbPublicVariables = uint64(len(spr.Public))
+	if vk.Size*rho > 1<<maxOrderRoot {
+		return nil, nil, fmt.Errorf("circuit too large: %d constraints", nbConstraints)
+	}
+	smallDomain, _ := vk.domains()
+
+	// public polynomials corresponding to constraints: [ placholders | constraints | assertions ]
+	for k := range pk.Preprocessed {
+		pk.Preprocessed[k] = make([]goldilocks.Element, vk.Size)
+	}
+	ql, qr, qm, qo, qk := pk.Preprocessed[0], pk.Preprocessed[1], pk.Preprocessed[2], pk.Preprocessed[3], pk.Preprocessed[4]
+	for i := 0; i < len(spr.Public); i++ { // placeholders (-PUB_INPUT_i + qk_i = 0)
+		ql[i].SetOne().Neg(&ql[i])
+	}
+	offset := len(spr.Public)
+	j := 0
+	it := spr.GetSparseR1CIterator()
+	for c := it.Next(); c != nil; c = it.Next() {
+		ql[offset+j].Set(&spr.Coefficients[c.QL])
+		qr[offset+j].Set(&spr.Coefficients[c.QR])
+		qm[offset+j].Set(&spr.Coefficients[c.QM])
+		qo[offset+j].Set(&spr.Coefficients[c.QO])
+		qk[offset+j].Set(&spr.Coefficients[c.QC])
+		j++
+	}
+
+	// permutation polynomials
+	buildPermutation(spr, &pk)
+	id := getIDSmallDomain(smallDomain)
+	for i := 0; i < int(vk.Size); i++ {
+		pk.Preprocessed[5][i].Set(&id[pk.Permutation[i]])
+		pk.Preprocessed[6][i].Set(&id[pk.Permutation[int(vk.Size)+i]])
+		pk.Preprocessed[7][i].Set(&id[pk.Permutation[2*int(vk.Size)+i]])
+	}
+
+	for k := range pk.Preprocessed {
+		smallDomain.fftInverse(pk.Preprocessed[k])
+	}
+
+	// commit to the preprocessed polynomials
+	vk.PreprocessedRoot = pk.commitPreprocessed(opt.IOPPHash).tree.root()
+
+	return &pk, &vk, nil
+}
+
+// domains returns the domain on which the constraints are enforced and the
+// (larger) evaluation domain used for the commitments.
+func (vk *VerifyingKey) domains() (small, big *domain) {
+	return newDomain(vk.Size), newDomain(rho * vk.Size)
+}
+
+// cosetShift returns the shift of the evaluation domain, which is also the
+// shift defining the second copy of the small domain in the permutation
+// argument.
+func cosetShift() goldilocks.Element {
+	var res goldilocks.Element
+	res.SetUint64(multiplicativeGenerator)
+	return res
+}
+
+// buildPermutation builds the Permutation associated with a circuit.
+//
+// The permutation s is composed of cycles of maximum length such that
+//
+//	s. (l||r||o) = (l||r||o)
+//
+// , where l||r||o is the concatenation of the indices of l, r, o in
+// ql.l+qr.r+qm.l.r+qo.O+k = 0.
+//
+// The permutation is encoded as a slice s of size 3*size(l), where the
+// i-th entry of l||r||o is sent to the s[i]-th entry, so it acts on a tab
+// like this: for i in tab: tab[i] = tab[permutation[i]]
+func buildPermutation(spr *cs.SparseR1CS, pk *ProvingKey) {
+
+	nbVariables := spr.NbInternalVariables + len(spr.Public) + len(spr.Secret)
+	sizeSolution := int(pk.Vk.Size)
+
+	// init permutation
+	pk.Permutation = make([]int64, 3*sizeSolution)
+	for i := 0; i < len(pk.Permutation); i++ {
+		pk.Permutation[i] = -1
+	}
+
+	// init LRO position -> variable_ID
+	lro := make([]int, 3*sizeSolution) // position -> variable_ID
+	for i := 0; i < len(spr.Public); i++ {
+		lro[i] = i // IDs of LRO associated to placeholders (only L needs to be taken care of)
+	}
+
+	offset := len(spr.Public)
+
+	j := 0
+	it := spr.GetSparseR1CIterator()
+	for c := it.Next(); c != nil; c = it.Next() {
+		lro[offset+j] = int(c.XA)
+		lro[sizeSolution+offset+j] = int(c.XB)
+		lro[2*sizeSolution+offset+j] = int(c.XC)
+		j++
+	}
+
+	// init cycle:
+	// map ID -> last position the ID was seen
+	cycle := make([]int64, nbVariables)
+	for i := 0; i < len(cycle); i++ {
+		cycle[i] = -1
+	}
+
+	for i := 0; i < len(lro); i++ {
+		if cycle[lro[i]] != -1 {
+			// if != -1, it means we already encountered this value
+			// so we need to set the corresponding permutation index.
+			pk.Permutation[i] = cycle[lro[i]]
+		}
+		cycle[lro[i]] = int64(i)
+	}
+
+	// complete the Permutation by filling the first IDs encountered
+	for i := 0; i < len(pk.Permutation); i++ {
+		if pk.Permutation[i] == -1 {
+			pk.Permutation[i] = cycle[lro[i]]
+		}
+	}
+}
+
+// getIDSmallDomain returns the Lagrange form of ID on the small domain:
+//
+//	[1,..,g^{n-1},s,..,s*g^{n-1},s^2,..,s^2*g^{n-1}]
+func getIDSmallDomain(d *domain) []goldilocks.Element {
+	res := make([]goldilocks.Element, 3*d.Cardinality)
+	shift := cosetShift()
+
+	res[0].SetOne()
+	res[d.Cardinality].Set(&shift)
+	res[2*d.Cardinality].Square(&shift)
+
+	for i := uint64(1); i < d.Cardinality; i++ {
+		res[i].Mul(&res[i-1], &d.Generator)
+		res[d.Cardinality+i].Mul(&res[d.Cardinality+i-1], &d.Generator)
+		res[2*d.Cardinality+i].Mul(&res[2*d.Cardinality+i-1], &d.Generator)
+	}
+
+	return res
+}
+
+// VerifyingKey returns pk.Vk
+func (pk *ProvingKey) VerifyingKey() interface{} {
+	return pk.Vk
+}
+
+// NbPublicWitness returns the expected public witness size (number of field elements)
+func (vk *VerifyingKey) NbPublicWitness() int {
+	return int(vk.NbPublicVariables)
+}
@@ -0,0 +1,88 @@
+package plonkfri
+
+import (
+	"math/big"
+	"strconv"
+
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+// challengeNames returns the names of the challenges of the transcript, in the
+// order in which they are derived.
+func challengeNames(nbFolds int) []string {
+	res := []string{"beta", "gamma", "alpha", "zeta", "lambda"}
+	for i := 0; i < nbFolds; i++ {
+		res = append(res, foldName(i))
+	}
+	for i := 0; i < nbQueries; i++ {
+		res = append(res, queryName(i))
+	}
+	return res
+}
+
+func foldName(i int) string {
+	return "fold" + strconv.Itoa(i)
+}
+
+func queryName(i int) string {
+	return "query" + strconv.Itoa(i)
+}
+
+// deriveRandomness binds data to the challenge and returns it as an element of
+// the extension field. Each coordinate is derived from one half of the
+// challenge bytes.
+func deriveRandomness(fs *fiatshamir.Transcript, challenge string, data ...[]byte) (E2, error) {
+	var r E2
+	b, err := computeChallenge(fs, challenge, data...)
+	if err != nil {
+		return r, err
+	}
+	half := len(b) / 2
+	r.A0.SetBigInt(new(big.Int).SetBytes(b[:half]))
+	r.A1.SetBigInt(new(big.Int).SetBytes(b[half:]))
+	return r, nil
+}
+
+// deriveQuery binds data to the challenge and returns it as an integer in
+// [0, bound).
+func deriveQuery(fs *fiatshamir.Transcript, challenge string, bound uint64, data ...[]byte) (uint64, error) {
+	b, err := computeChallenge(fs, challenge, data...)
+	if err != nil {
+		return 0, err
+	}
+	r := new(big.Int).SetBytes(b)
+	return r.Mod(r, new(big.Int).SetUint64(bound)).Uint64(), nil
+}
+
+func computeChallenge(fs *fiatshamir.Transcript, challenge string, data ...[]byte) ([]byte, error) {
+	for _, d := range data {
+		if err := fs.Bind(challenge, d); err != nil {
+			return nil, err
+		}
+	}
+	return fs.ComputeChallenge(challenge)
+}
+
+// publicInputsBytes returns the encoding of the public inputs bound to the
+// first challenge.
+func publicInputsBytes(public []goldilocks.Element) []byte {
+	res := make([]byte, 0, len(public)*goldilocks.Bytes)
+	for i := range public {
+		b := public[i].Bytes()
+		res = append(res, b[:]...)
+	}
+	return res
+}
+
+// evaluationsBytes returns the encoding of the claimed evaluations bound to
+// the DEEP challenge.
+func (proof *Proof) evaluationsBytes() []byte {
+	res := make([]byte, 0, (nbOpened+1)*E2Bytes)
+	for i := range proof.Evaluations {
+		b := proof.Evaluations[i].Bytes()
+		res = append(res, b[:]...)
+	}
+	b := proof.ZShifted.Bytes()
+	return append(res, b[:]...)
+}
@@ -0,0 +1,287 @@
+package plonkfri
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/airchains-network/gnark/backend"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+var (
+	ErrInvalidAlgebraicRelation = errors.New("algebraic relation does not hold")
+	ErrInvalidProofShape        = errors.New("proof does not match the verifying key")
+	ErrInvalidFold              = errors.New("FRI folding is inconsistent")
+	ErrNotZeroKnowledge         = errors.New("the Goldilocks prover is not zero-knowledge, use backend.WithNonZKProving to acknowledge it")
+)
+
+// Verify verifies a PLONK proof, from the proof, preprocessed public data, and public witness.
+func Verify(proof *Proof, vk *VerifyingKey, publicWitness goldilocks.Vector, opts ...backend.VerifierOption) error {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("create backend config: %w", err)
+	}
+	if len(publicWitness) != int(vk.NbPublicVariables) {
+		return fmt.Errorf("invalid witness size, got %d, expected %d", len(publicWitness), vk.NbPublicVariables)
+	}
+
+	smallDomain, bigDomain := vk.domains()
+	nbFolds := bits.TrailingZeros64(vk.Size)
+	if err := proof.checkShape(nbFolds); err != nil {
+		return err
+	}
+
+	// 0 - derive the challenges
+	fs := fiatshamir.NewTranscript(cfg.ChallengeHash, challengeNames(nbFolds)...)
+	beta, err := deriveRandomness(&fs, "beta", publicInputsBytes(publicWitness), proof.LRO)
+	if err != nil {
+		return err
+	}
+	gamma, err := deriveRandomness(&fs, "gamma")
+	if err != nil {
+		return err
+	}
+	alpha, err := deriveRandomness(&fs, "alpha", proof.Z)
+	if err != nil {
+		return err
+	}
+	zeta, err := deriveRandomness(&fs, "zeta", proof.H)
+	if err != nil {
+		return err
+	}
+	lambda, err := deriveRandomness(&fs, "lambda", proof.evaluationsBytes())
+	if err != nil {
+		return err
+	}
+	foldChallenges := make([]E2, nbFolds)
+	for r := range foldChallenges {
+		var data [][]byte
+		if r > 0 {
+			data = append(data, proof.FoldRoots[r-1])
+		}
+		if foldChallenges[r], err = deriveRandomness(&fs, foldName(r), data...); err != nil {
+			return err
+		}
+	}
+
+	// 1 - check the algebraic relation at zeta
+	if err := checkRelation(proof, vk, smallDomain, publicWitness, alpha, beta, gamma, zeta); err != nil {
+		return err
+	}
+
+	// 2 - check the FRI queries. The first layer is the DEEP composition
+	// polynomial, computed from the openings of the committed polynomials.
+	var zetaShifted E2
+	zetaShifted.MulByElement(&zeta, &smallDomain.Generator)
+	half := bigDomain.Cardinality / 2
+	depth := bits.TrailingZeros64(half)
+	for q := range proof.Queries {
+		var data [][]byte
+		if q == 0 {
+			b := proof.FinalFold.Bytes()
+			data = append(data, b[:])
+		}
+		idx, err := deriveQuery(&fs, queryName(q), half, data...)
+		if err != nil {
+			return err
+		}
+		qp := &proof.Queries[q]
+
+		if err := verifyBaseOpening(cfg.IOPPHash, vk.PreprocessedRoot, idx, depth, &qp.Preprocessed); err != nil {
+			return fmt.Errorf("preprocessed opening: %w", err)
+		}
+		if err := verifyBaseOpening(cfg.IOPPHash, proof.LRO, idx, depth, &qp.LRO); err != nil {
+			return fmt.Errorf("l, r, o opening: %w", err)
+		}
+		if err := verifyExtOpening(cfg.IOPPHash, proof.Z, idx, depth, &qp.Z); err != nil {
+			return fmt.Errorf("z opening: %w", err)
+		}
+		if err := verifyExtOpening(cfg.IOPPHash, proof.H, idx, depth, &qp.H); err != nil {
+			return fmt.Errorf("h opening: %w", err)
+		}
+
+		// x and -x, the points of the evaluation domain of the opened leaf
+		var x [2]goldilocks.Element
+		x[0].Exp(bigDomain.Generator, new(big.Int).SetUint64(idx))
+		shift := cosetShift()
+		x[0].Mul(&x[0], &shift)
+		x[1].Neg(&x[0])
+		var deep [2]E2
+		for s := range deep {
+			base := append(append([]goldilocks.Element{}, qp.Preprocessed.Values[s]...), qp.LRO.Values[s]...)
+			ext := append(append([]E2{}, qp.Z.Values[s]...), qp.H.Values[s]...)
+			deep[s] = deepValue(proof, &x[s], base, ext, &zeta, &zetaShifted, &lambda)
+		}
+		if nbFolds == 0 {
+			if !deep[0].Equal(&proof.FinalFold) || !deep[1].Equal(&proof.FinalFold) {
+				return ErrInvalidFold
+			}
+			continue
+		}
+
+		// fold the layers down to the constant one
+		var xInv goldilocks.Element
+		xInv.Inverse(&x[0])
+		v := foldPair(&deep[0], &deep[1], &xInv, &foldChallenges[0])
+		pos, layerShift := idx, shift
+		for r := 1; r < nbFolds; r++ {
+			layerShift.Square(&layerShift)
+			layerHalf := half >> r
+			leaf, side := pos%layerHalf, pos/layerHalf
+			opening := &qp.Folds[r-1]
+			if err := verifyExtOpening(cfg.IOPPHash, proof.FoldRoots[r-1], leaf, depth-r, opening); err != nil {
+				return fmt.Errorf("fold %d opening: %w", r, err)
+			}
+			if !opening.Values[side][0].Equal(&v) {
+				return ErrInvalidFold
+			}
+			// y = shift^(2^r) * g^(2^r * leaf)
+			var yInv goldilocks.Element
+			yInv.Exp(bigDomain.Generator, new(big.Int).SetUint64(leaf<<r))
+			yInv.Mul(&yInv, &layerShift).Inverse(&yInv)
+			v = foldPair(&opening.Values[0][0], &opening.Values[1][0], &yInv, &foldChallenges[r])
+			pos = leaf
+		}
+		if !v.Equal(&proof.FinalFold) {
+			return ErrInvalidFold
+		}
+	}
+
+	return nil
+}
+
+// checkShape checks that the proof contains as many elements as expected.
+func (proof *Proof) checkShape(nbFolds int) error {
+	nbFoldRoots := 0
+	if nbFolds > 0 {
+		nbFoldRoots = nbFolds - 1
+	}
+	if len(proof.FoldRoots) != nbFoldRoots || len(proof.Queries) != nbQueries {
+		return ErrInvalidProofShape
+	}
+	for i := range proof.Queries {
+		qp := &proof.Queries[i]
+		if len(qp.Folds) != nbFoldRoots {
+			return ErrInvalidProofShape
+		}
+		if !qp.Preprocessed.hasShape(nbPreprocessed) || !qp.LRO.hasShape(3) || !qp.Z.hasShape(1) || !qp.H.hasShape(3) {
+			return ErrInvalidProofShape
+		}
+		for j := range qp.Folds {
+			if !qp.Folds[j].hasShape(1) {
+				return ErrInvalidProofShape
+			}
+		}
+	}
+	return nil
+}
+
+func (o *BaseOpening) hasShape(nbPolynomials int) bool {
+	return len(o.Values[0]) == nbPolynomials && len(o.Values[1]) == nbPolynomials
+}
+
+func (o *ExtOpening) hasShape(nbPolynomials int) bool {
+	return len(o.Values[0]) == nbPolynomials && len(o.Values[1]) == nbPolynomials
+}
+
+func verifyBaseOpening(h hash.Hash, root []byte, index uint64, depth int, o *BaseOpening) error {
+	if len(o.Path) != depth {
+		return errInvalidMerklePath
+	}
+	return verifyMerklePath(h, root, baseLeaf(o.Values), index, o.Path)
+}
+
+func verifyExtOpening(h hash.Hash, root []byte, index uint64, depth int, o *ExtOpening) error {
+	if len(o.Path) != depth {
+		return errInvalidMerklePath
+	}
+	return verifyMerklePath(h, root, extLeaf(o.Values), index, o.Path)
+}
+
+// checkRelation checks that the claimed evaluations at zeta satisfy
+//
+//	gate(ζ) + α·permutation(ζ) + α²·(z(ζ)-1)·L₀(ζ) = (h1(ζ) + ζ^n·h2(ζ) + ζ^{2n}·h3(ζ))·(ζ^n-1)
+func checkRelation(proof *Proof, vk *VerifyingKey, d *domain, public []goldilocks.Element, alpha, beta, gamma, zeta E2) error {
+	ql, qr, qm, qo, qk := &proof.Evaluations[0], &proof.Evaluations[1], &proof.Evaluations[2], &proof.Evaluations[3], &proof.Evaluations[4]
+	s := proof.Evaluations[5:8]
+	lro := proof.Evaluations[8:11]
+	z := &proof.Evaluations[11]
+	h := proof.Evaluations[12:15]
+
+	var one, zetaPowerN, zh E2
+	one.SetOne()
+	zetaPowerN.Exp(zeta, vk.Size)
+	zh.Sub(&zetaPowerN, &one)
+
+	// L_i(ζ) = ω^i·(ζ^n-1) / (n·(ζ-ω^i)), pi(ζ) = Σ_i w_i·L_i(ζ)
+	var pi, lagrangeZero, t, omegaI E2
+	omegaI.SetOne()
+	var factor E2
+	factor.MulByElement(&zh, &d.CardinalityInv)
+	for i := 0; i < len(public) || i == 0; i++ {
+		var lagrange E2
+		lagrange.Sub(&zeta, &omegaI).Inverse(&lagrange).Mul(&lagrange, &omegaI).Mul(&lagrange, &factor)
+		if i == 0 {
+			lagrangeZero = lagrange
+		}
+		if i < len(public) {
+			t.MulByElement(&lagrange, &public[i])
+			pi.Add(&pi, &t)
+		}
+		omegaI.MulByElement(&omegaI, &d.Generator)
+	}
+
+	// gate constraint
+	var gate E2
+	gate.Mul(ql, &lro[0])
+	t.Mul(qr, &lro[1])
+	gate.Add(&gate, &t)
+	t.Mul(qm, &lro[0]).Mul(&t, &lro[1])
+	gate.Add(&gate, &t)
+	t.Mul(qo, &lro[2])
+	gate.Add(&gate, &t)
+	gate.Add(&gate, qk).Add(&gate, &pi)
+
+	// permutation constraint
+	shift := cosetShift()
+	var ids [3]E2
+	ids[0].Set(&zeta)
+	ids[1].MulByElement(&zeta, &shift)
+	ids[2].MulByElement(&ids[1], &shift)
+	var num, den E2
+	num.Set(z)
+	den.Set(&proof.ZShifted)
+	for k := 0; k < 3; k++ {
+		t.Mul(&beta, &ids[k]).Add(&t, &gamma).Add(&t, &lro[k])
+		num.Mul(&num, &t)
+		t.Mul(&beta, &s[k]).Add(&t, &gamma).Add(&t, &lro[k])
+		den.Mul(&den, &t)
+	}
+	var perm E2
+	perm.Sub(&den, &num)
+
+	// boundary constraint
+	var boundary E2
+	boundary.Sub(z, &one).Mul(&boundary, &lagrangeZero)
+
+	var lhs E2
+	lhs.Mul(&boundary, &alpha).Add(&lhs, &perm).Mul(&lhs, &alpha).Add(&lhs, &gate)
+
+	var rhs E2
+	rhs.Mul(&h[2], &zetaPowerN).Add(&rhs, &h[1]).Mul(&rhs, &zetaPowerN).Add(&rhs, &h[0]).Mul(&rhs, &zh)
+
+	if !lhs.Equal(&rhs) {
+		return ErrInvalidAlgebraicRelation
+	}
+	return nil
+}
+
+// ExportSolidity not implemented for Goldilocks
+func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
+	return errors.New("not implemented")
+}
@@ -13,10 +13,22 @@
 // limitations under the License.
 
 // Package plonkfri implements PLONK Zero Knowledge Proof system, with FRI as commitment scheme.
+//
+// Besides the scalar fields of the supported curves, circuits compiled over the
+// Goldilocks field (p = 2⁶⁴-2³²+1) are supported, see
+// [github.com/airchains-network/gnark/backend/plonkfri/goldilocks]. The
+// Goldilocks proofs are not zero-knowledge and Prove requires the option
+// [backend.WithNonZKProving] for them.
 
 package plonkfri
 
 import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
 	"github.com/airchains-network/gnark/backend"
 	"github.com/airchains-network/gnark/constraint"
 
@@ -28,6 +40,7 @@ import (
 	cs_bn254 "github.com/airchains-network/gnark/constraint/bn254"
 	cs_bw6633 "github.com/airchains-network/gnark/constraint/bw6-633"
 	cs_bw6761 "github.com/airchains-network/gnark/constraint/bw6-761"
+	cs_goldilocks "github.com/airchains-network/gnark/constraint/goldilocks"
 
 	plonk_bls12377 "github.com/airchains-network/gnark/backend/plonkfri/bls12-377"
 	plonk_bls12381 "github.com/airchains-network/gnark/backend/plonkfri/bls12-381"
@@ -36,6 +49,7 @@ import (
 	plonk_bn254 "github.com/airchains-network/gnark/backend/plonkfri/bn254"
 	plonk_bw6633 "github.com/airchains-network/gnark/backend/plonkfri/bw6-633"
 	plonk_bw6761 "github.com/airchains-network/gnark/backend/plonkfri/bw6-761"
+	plonk_goldilocks "github.com/airchains-network/gnark/backend/plonkfri/goldilocks"
 
 	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -44,22 +58,30 @@ import (
 	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
 	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	fr_goldilocks "github.com/consensys/gnark-crypto/field/goldilocks"
+
+	"github.com/airchains-network/gnark/internal/utils"
+	gnarkio "github.com/airchains-network/gnark/io"
 )
 
 // Proof represents a Plonk proof generated by plonk.Prove
 //
 // it's underlying implementation is curve specific (see gnark/internal/backend)
 type Proof interface {
-	// io.WriterTo
-	// io.ReaderFrom
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
 }
 
 // ProvingKey represents a plonk ProvingKey
 //
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
 type ProvingKey interface {
-	// io.WriterTo
-	// io.ReaderFrom
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
 	VerifyingKey() interface{}
 }
 
@@ -67,30 +89,43 @@ type ProvingKey interface {
 //
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
 type VerifyingKey interface {
-	// io.WriterTo
-	// io.ReaderFrom
-	// InitKZG(srs kzg.SRS) error
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
 	NbPublicWitness() int // number of elements expected in the public witness
+	ExportSolidity(w io.Writer) error
 }
 
-// Setup prepares the public data associated to a circuit + public inputs.
+// Setup prepares the public data associated to a circuit + public inputs,
+// with the default hash function for the Iopp.
 func Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
+	return SetupWithOptions(ccs)
+}
+
+// SetupWithOptions prepares the public data associated to a circuit + public
+// inputs. The hash function of the Iopp can be set with
+// [backend.WithProverIOPPHashFunction], in which case the same hash function
+// must be used when proving and verifying.
+func SetupWithOptions(ccs constraint.ConstraintSystem, opts ...backend.ProverOption) (ProvingKey, VerifyingKey, error) {
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Setup(tccs)
+		return plonk_bn254.SetupWithOptions(tccs, opts...)
 	case *cs_bls12381.SparseR1CS:
-		return plonk_bls12381.Setup(tccs)
+		return plonk_bls12381.SetupWithOptions(tccs, opts...)
 	case *cs_bls12377.SparseR1CS:
-		return plonk_bls12377.Setup(tccs)
+		return plonk_bls12377.SetupWithOptions(tccs, opts...)
 	case *cs_bw6761.SparseR1CS:
-		return plonk_bw6761.Setup(tccs)
+		return plonk_bw6761.SetupWithOptions(tccs, opts...)
 	case *cs_bls24315.SparseR1CS:
-		return plonk_bls24315.Setup(tccs)
+		return plonk_bls24315.SetupWithOptions(tccs, opts...)
 	case *cs_bw6633.SparseR1CS:
-		return plonk_bw6633.Setup(tccs)
+		return plonk_bw6633.SetupWithOptions(tccs, opts...)
 	case *cs_bls24317.SparseR1CS:
-		return plonk_bls24317.Setup(tccs)
+		return plonk_bls24317.SetupWithOptions(tccs, opts...)
+	case *cs_goldilocks.SparseR1CS:
+		return plonk_goldilocks.Setup(tccs, opts...)
 	default:
 		panic("unrecognized SparseR1CS curve type")
 	}
@@ -127,6 +162,9 @@ func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.W
 	case *cs_bls24317.SparseR1CS:
 		return plonk_bls24317.Prove(tccs, pk.(*plonk_bls24317.ProvingKey), fullWitness, opts...)
 
+	case *cs_goldilocks.SparseR1CS:
+		return plonk_goldilocks.Prove(tccs, pk.(*plonk_goldilocks.ProvingKey), fullWitness, opts...)
+
 	default:
 		panic("unrecognized SparseR1CS curve type")
 	}
@@ -185,7 +223,192 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 		}
 		return plonk_bls24317.Verify(_proof, vk.(*plonk_bls24317.VerifyingKey), w, opts...)
 
+	case *plonk_goldilocks.Proof:
+		w, ok := publicWitness.Vector().(fr_goldilocks.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		return plonk_goldilocks.Verify(_proof, vk.(*plonk_goldilocks.VerifyingKey), w, opts...)
+
 	default:
 		panic("unrecognized proof type")
 	}
 }
+
+// NewCS instantiate a concrete curved-typed SparseR1CS and return a ConstraintSystem interface
+// This method exists for (de)serialization purposes
+func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
+	var r1cs constraint.ConstraintSystem
+	switch curveID {
+	case ecc.BN254:
+		r1cs = &cs_bn254.SparseR1CS{}
+	case ecc.BLS12_377:
+		r1cs = &cs_bls12377.SparseR1CS{}
+	case ecc.BLS12_381:
+		r1cs = &cs_bls12381.SparseR1CS{}
+	case ecc.BW6_761:
+		r1cs = &cs_bw6761.SparseR1CS{}
+	case ecc.BLS24_317:
+		r1cs = &cs_bls24317.SparseR1CS{}
+	case ecc.BLS24_315:
+		r1cs = &cs_bls24315.SparseR1CS{}
+	case ecc.BW6_633:
+		r1cs = &cs_bw6633.SparseR1CS{}
+	default:
+		panic("not implemented")
+	}
+	return r1cs
+}
+
+// NewProvingKey instantiates a curve-typed ProvingKey and returns an interface
+// This function exists for serialization purposes
+func NewProvingKey(curveID ecc.ID) ProvingKey {
+	var pk ProvingKey
+	switch curveID {
+	case ecc.BN254:
+		pk = &plonk_bn254.ProvingKey{}
+	case ecc.BLS12_377:
+		pk = &plonk_bls12377.ProvingKey{}
+	case ecc.BLS12_381:
+		pk = &plonk_bls12381.ProvingKey{}
+	case ecc.BW6_761:
+		pk = &plonk_bw6761.ProvingKey{}
+	case ecc.BLS24_317:
+		pk = &plonk_bls24317.ProvingKey{}
+	case ecc.BLS24_315:
+		pk = &plonk_bls24315.ProvingKey{}
+	case ecc.BW6_633:
+		pk = &plonk_bw6633.ProvingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return pk
+}
+
+// NewProof instantiates a curve-typed Proof and returns an interface
+// This function exists for serialization purposes
+func NewProof(curveID ecc.ID) Proof {
+	var proof Proof
+	switch curveID {
+	case ecc.BN254:
+		proof = &plonk_bn254.Proof{}
+	case ecc.BLS12_377:
+		proof = &plonk_bls12377.Proof{}
+	case ecc.BLS12_381:
+		proof = &plonk_bls12381.Proof{}
+	case ecc.BW6_761:
+		proof = &plonk_bw6761.Proof{}
+	case ecc.BLS24_317:
+		proof = &plonk_bls24317.Proof{}
+	case ecc.BLS24_315:
+		proof = &plonk_bls24315.Proof{}
+	case ecc.BW6_633:
+		proof = &plonk_bw6633.Proof{}
+	default:
+		panic("not implemented")
+	}
+
+	return proof
+}
+
+// NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
+// This function exists for serialization purposes
+func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
+	var vk VerifyingKey
+	switch curveID {
+	case ecc.BN254:
+		vk = &plonk_bn254.VerifyingKey{}
+	case ecc.BLS12_377:
+		vk = &plonk_bls12377.VerifyingKey{}
+	case ecc.BLS12_381:
+		vk = &plonk_bls12381.VerifyingKey{}
+	case ecc.BW6_761:
+		vk = &plonk_bw6761.VerifyingKey{}
+	case ecc.BLS24_317:
+		vk = &plonk_bls24317.VerifyingKey{}
+	case ecc.BLS24_315:
+		vk = &plonk_bls24315.VerifyingKey{}
+	case ecc.BW6_633:
+		vk = &plonk_bw6633.VerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return vk
+}
+
+// errUnsupportedField is returned by the *FromField constructors when the
+// field modulus is neither Goldilocks nor the scalar field of a supported curve.
+var errUnsupportedField = errors.New("unsupported field")
+
+// fieldToCurve reports whether field is the Goldilocks modulus, and otherwise
+// returns the curve whose scalar field is field. The Goldilocks field isn't the scalar
+// field of a curve, so the curve-typed constructors can't instantiate it.
+func fieldToCurve(field *big.Int) (goldilocks bool, curveID ecc.ID, err error) {
+	if field.Cmp(fr_goldilocks.Modulus()) == 0 {
+		return true, ecc.UNKNOWN, nil
+	}
+	switch curveID = utils.FieldToCurve(field); curveID {
+	case ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BLS24_317, ecc.BLS24_315, ecc.BW6_633:
+		return false, curveID, nil
+	default:
+		return false, ecc.UNKNOWN, errUnsupportedField
+	}
+}
+
+// NewCSFromField instantiates a concrete field-typed SparseR1CS from the field
+// modulus, including the Goldilocks field. It returns an error if the field
+// isn't supported.
+func NewCSFromField(field *big.Int) (constraint.ConstraintSystem, error) {
+	goldilocks, curveID, err := fieldToCurve(field)
+	if err != nil {
+		return nil, err
+	}
+	if goldilocks {
+		return &cs_goldilocks.SparseR1CS{}, nil
+	}
+	return NewCS(curveID), nil
+}
+
+// NewProvingKeyFromField instantiates a field-typed ProvingKey from the field
+// modulus, including the Goldilocks field. It returns an error if the field
+// isn't supported.
+func NewProvingKeyFromField(field *big.Int) (ProvingKey, error) {
+	goldilocks, curveID, err := fieldToCurve(field)
+	if err != nil {
+		return nil, err
+	}
+	if goldilocks {
+		return &plonk_goldilocks.ProvingKey{}, nil
+	}
+	return NewProvingKey(curveID), nil
+}
+
+// NewProofFromField instantiates a field-typed Proof from the field modulus,
+// including the Goldilocks field. It returns an error if the field isn't
+// supported.
+func NewProofFromField(field *big.Int) (Proof, error) {
+	goldilocks, curveID, err := fieldToCurve(field)
+	if err != nil {
+		return nil, err
+	}
+	if goldilocks {
+		return &plonk_goldilocks.Proof{}, nil
+	}
+	return NewProof(curveID), nil
+}
+
+// NewVerifyingKeyFromField instantiates a field-typed VerifyingKey from the
+// field modulus, including the Goldilocks field. It returns an error if the
+// field isn't supported.
+func NewVerifyingKeyFromField(field *big.Int) (VerifyingKey, error) {
+	goldilocks, curveID, err := fieldToCurve(field)
+	if err != nil {
+		return nil, err
+	}
+	if goldilocks {
+		return &plonk_goldilocks.VerifyingKey{}, nil
+	}
+	return NewVerifyingKey(curveID), nil
+}
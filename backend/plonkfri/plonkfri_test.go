@@ -0,0 +1,220 @@
+package plonkfri_test
+
+import (
+	"bytes"
+	"math/big"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/airchains-network/gnark/backend"
+	"github.com/airchains-network/gnark/backend/plonkfri"
+	plonkfri_bn254 "github.com/airchains-network/gnark/backend/plonkfri/bn254"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestSerializationRoundTrip(t *testing.T) {
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BW6_761} {
+		t.Run(curve.String(), func(t *testing.T) {
+			assert := require.New(t)
+
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &cubicCircuit{})
+			assert.NoError(err)
+			fullWitness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, curve.ScalarField())
+			assert.NoError(err)
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+
+			pk, vk, err := plonkfri.Setup(ccs)
+			assert.NoError(err)
+
+			// proving with the deserialized proving key
+			var buf bytes.Buffer
+			_, err = pk.WriteTo(&buf)
+			assert.NoError(err)
+			pk2 := plonkfri.NewProvingKey(curve)
+			_, err = pk2.ReadFrom(&buf)
+			assert.NoError(err)
+			proof, err := plonkfri.Prove(ccs, pk2, fullWitness)
+			assert.NoError(err)
+
+			// verifying the deserialized proof with the deserialized verifying key
+			buf.Reset()
+			_, err = vk.WriteTo(&buf)
+			assert.NoError(err)
+			vk2 := plonkfri.NewVerifyingKey(curve)
+			_, err = vk2.ReadFrom(&buf)
+			assert.NoError(err)
+
+			buf.Reset()
+			_, err = proof.WriteTo(&buf)
+			assert.NoError(err)
+			proof2 := plonkfri.NewProof(curve)
+			_, err = proof2.ReadFrom(&buf)
+			assert.NoError(err)
+
+			assert.NoError(plonkfri.Verify(proof2, vk2, publicWitness))
+		})
+	}
+}
+
+func TestTamperedCommitment(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+
+	pk, vk, err := plonkfri.Setup(ccs)
+	assert.NoError(err)
+	proof, err := plonkfri.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+	assert.NoError(plonkfri.Verify(proof, vk, publicWitness))
+
+	// replacing the commitment to L by the one of another proof of the same
+	// statement changes the challenges, so the proof must be rejected.
+	other, err := plonkfri.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+	p := proof.(*plonkfri_bn254.Proof)
+	p.LROpp[0] = other.(*plonkfri_bn254.Proof).LROpp[0]
+	assert.Error(plonkfri.Verify(p, vk, publicWitness))
+}
+
+func TestGoldilocks(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(goldilocks.Modulus(), scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, goldilocks.Modulus())
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+
+	pk, vk, err := plonkfri.Setup(ccs)
+	assert.NoError(err)
+
+	// the Goldilocks prover is not zero-knowledge and must be explicitly allowed
+	_, err = plonkfri.Prove(ccs, pk, fullWitness)
+	assert.Error(err)
+	proof, err := plonkfri.Prove(ccs, pk, fullWitness, backend.WithNonZKProving())
+	assert.NoError(err)
+	assert.NoError(plonkfri.Verify(proof, vk, publicWitness))
+
+	// the keys and proofs over Goldilocks are instantiated from the field modulus
+	var buf bytes.Buffer
+	_, err = pk.WriteTo(&buf)
+	assert.NoError(err)
+	pk2, err := plonkfri.NewProvingKeyFromField(goldilocks.Modulus())
+	assert.NoError(err)
+	_, err = pk2.ReadFrom(&buf)
+	assert.NoError(err)
+	proof, err = plonkfri.Prove(ccs, pk2, fullWitness, backend.WithNonZKProving())
+	assert.NoError(err)
+
+	buf.Reset()
+	_, err = vk.WriteTo(&buf)
+	assert.NoError(err)
+	vk2, err := plonkfri.NewVerifyingKeyFromField(goldilocks.Modulus())
+	assert.NoError(err)
+	_, err = vk2.ReadFrom(&buf)
+	assert.NoError(err)
+
+	buf.Reset()
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(err)
+	proof2, err := plonkfri.NewProofFromField(goldilocks.Modulus())
+	assert.NoError(err)
+	_, err = proof2.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.NoError(plonkfri.Verify(proof2, vk2, publicWitness))
+
+	wrongWitness, err := frontend.NewWitness(&cubicCircuit{Y: 36}, goldilocks.Modulus(), frontend.PublicOnly())
+	assert.NoError(err)
+	assert.Error(plonkfri.Verify(proof, vk, wrongWitness))
+}
+
+func TestNewFromField(t *testing.T) {
+	assert := require.New(t)
+
+	pk, err := plonkfri.NewProvingKeyFromField(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.IsType(plonkfri.NewProvingKey(ecc.BN254), pk)
+
+	// an unsupported field isn't silently mapped to Goldilocks
+	unsupported := big.NewInt(101)
+	_, err = plonkfri.NewCSFromField(unsupported)
+	assert.Error(err)
+	_, err = plonkfri.NewProvingKeyFromField(unsupported)
+	assert.Error(err)
+	_, err = plonkfri.NewProofFromField(unsupported)
+	assert.Error(err)
+	_, err = plonkfri.NewVerifyingKeyFromField(unsupported)
+	assert.Error(err)
+	assert.Panics(func() { plonkfri.NewProvingKey(ecc.UNKNOWN) })
+}
+
+func TestExportSolidity(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	pk, vk, err := plonkfri.Setup(ccs)
+	assert.NoError(err)
+	proof, err := plonkfri.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(vk.ExportSolidity(&buf))
+
+	// the serialized proof has the size expected by the contract
+	m := regexp.MustCompile(`PROOF_SIZE = (\d+);`).FindSubmatch(buf.Bytes())
+	assert.NotNil(m)
+	proofSize, err := strconv.Atoi(string(m[1]))
+	assert.NoError(err)
+	assert.Len(proof.(*plonkfri_bn254.Proof).MarshalSolidity(), proofSize)
+
+	// other curves are not supported
+	ccs, err = frontend.Compile(ecc.BLS12_381.ScalarField(), scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	_, vk, err = plonkfri.Setup(ccs)
+	assert.NoError(err)
+	assert.Error(vk.ExportSolidity(&buf))
+}
+
+// TestSolidityVerifier checks the exported contract through test.Assert, which
+// compiles it with solc and runs its Verify function in the go-ethereum evm,
+// for a valid and an invalid public input. It requires the solccheck build tag
+// and solc and evm in the PATH.
+func TestSolidityVerifier(t *testing.T) {
+	if !test.SolcCheck {
+		t.Skip("solidity checks require the solccheck build tag")
+	}
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&cubicCircuit{},
+		test.WithValidAssignment(&cubicCircuit{X: 3, Y: 35}),
+		test.WithBackends(backend.PLONKFRI),
+		test.WithCurves(ecc.BN254),
+	)
+}
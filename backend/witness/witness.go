@@ -56,6 +56,7 @@ import (
 	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
 	"github.com/airchains-network/gnark/debug"
 	"github.com/airchains-network/gnark/frontend/schema"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
 	"github.com/airchains-network/gnark/internal/tinyfield"
 )
 
@@ -189,6 +190,8 @@ func (w *witness) WriteTo(wr io.Writer) (n int64, err error) {
 		m, err = t.WriteTo(wr)
 	case tinyfield.Vector:
 		m, err = t.WriteTo(wr)
+	case goldilocks.Vector:
+		m, err = t.WriteTo(wr)
 	default:
 		panic("invalid input")
 	}
@@ -235,6 +238,9 @@ func (w *witness) ReadFrom(r io.Reader) (n int64, err error) {
 	case tinyfield.Vector:
 		m, err = t.ReadFrom(r)
 		w.vector = t
+	case goldilocks.Vector:
+		m, err = t.ReadFrom(r)
+		w.vector = t
 	default:
 		panic("invalid input")
 	}
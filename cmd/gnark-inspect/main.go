@@ -0,0 +1,113 @@
+// Command gnark-inspect prints a serialized constraint system (as written by
+// constraint.ConstraintSystem.WriteTo) in a human-readable text or JSON format.
+//
+// Usage:
+//
+//	gnark-inspect [flags] circuit.r1cs
+//
+// The instructions can be filtered by variable, source location or blueprint:
+//
+//	gnark-inspect -curve bn254 -system scs -var v42 circuit.scs
+//	gnark-inspect -line circuit.go:42 -json circuit.r1cs
+//
+// Source locations are only available for the constraints which have debug
+// info; compile the circuit with the debug build tag to record them for all the
+// constraints and hints.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/airchains-network/gnark/backend/groth16"
+	"github.com/airchains-network/gnark/backend/plonk"
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+var (
+	fCurve     = flag.String("curve", "bn254", "curve of the constraint system")
+	fSystem    = flag.String("system", "r1cs", "type of the constraint system: r1cs (groth16) or scs (plonk)")
+	fJSON      = flag.Bool("json", false, "print the constraint system as JSON")
+	fVariable  = flag.String("var", "", "only print the instructions referring to this variable (e.g. X or v42)")
+	fLine      = flag.String("line", "", "only print the instructions whose stack trace contains this location (e.g. circuit.go:42)")
+	fBlueprint = flag.String("blueprint", "", "only print the instructions of this blueprint (e.g. constraint.BlueprintGenericHint)")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] file\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(path string) error {
+	curve, err := ecc.IDFromString(*fCurve)
+	if err != nil {
+		return err
+	}
+
+	var ccs constraint.ConstraintSystem
+	switch strings.ToLower(*fSystem) {
+	case "r1cs", "groth16":
+		ccs = groth16.NewCS(curve)
+	case "scs", "plonk":
+		ccs = plonk.NewCS(curve)
+	default:
+		return fmt.Errorf("unknown constraint system type %q", *fSystem)
+	}
+
+	f, err := os.Open(path) //#nosec G304 -- the path is given by the user
+	if err != nil {
+		return err
+	}
+	_, err = ccs.ReadFrom(bufio.NewReader(f))
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	d, err := constraint.NewDump(ccs)
+	if err != nil {
+		return err
+	}
+	d = d.Select(func(di *constraint.DumpInstruction) bool {
+		if *fVariable != "" && !di.HasWire(*fVariable) {
+			return false
+		}
+		if *fLine != "" && !di.HasLocation(*fLine) {
+			return false
+		}
+		if *fBlueprint != "" && di.Blueprint != *fBlueprint && !strings.HasSuffix(di.Blueprint, "."+*fBlueprint) {
+			return false
+		}
+		return true
+	})
+
+	w := bufio.NewWriter(os.Stdout)
+	if *fJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(d)
+	} else {
+		err = d.WriteText(w)
+	}
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
@@ -115,14 +115,43 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 
 	if s.Type == constraint.SystemR1CS {
 		n := ecc.NextPowerOfTwo(uint64(cs.GetNbConstraints()))
-		s.a = make(fr.Vector, cs.GetNbConstraints(), n)
-		s.b = make(fr.Vector, cs.GetNbConstraints(), n)
-		s.c = make(fr.Vector, cs.GetNbConstraints(), n)
+		if opt.R1CSVectorAllocator != nil {
+			if s.a, s.b, s.c, err = allocateR1CSVectors(opt.R1CSVectorAllocator, cs.GetNbConstraints(), int(n)); err != nil {
+				return nil, err
+			}
+		} else {
+			s.a = make(fr.Vector, cs.GetNbConstraints(), n)
+			s.b = make(fr.Vector, cs.GetNbConstraints(), n)
+			s.c = make(fr.Vector, cs.GetNbConstraints(), n)
+		}
 	}
 
 	return &s, nil
 }
 
+// allocateR1CSVectors allocates with alloc the vectors a, b and c of the
+// solution, of length nbConstraints and capacity n.
+func allocateR1CSVectors(alloc func(n int) (any, error), nbConstraints, n int) (a, b, c fr.Vector, err error) {
+	var res [3]fr.Vector
+	for i := range res {
+		v, err := alloc(n)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		switch v := v.(type) {
+		case []fr.Element:
+			res[i] = v
+		case fr.Vector:
+			res[i] = v
+		}
+		if len(res[i]) != n {
+			return nil, nil, nil, fmt.Errorf("the R1CS vector allocator must return %d elements of type fr.Element", n)
+		}
+		res[i] = res[i][:nbConstraints]
+	}
+	return res[0], res[1], res[2], nil
+}
+
 func (s *solver) set(id int, value fr.Element) {
 	if s.solved[id] {
 		panic("solving the same wire twice should never happen.")
@@ -631,6 +660,11 @@ func (r *UnsatisfiedConstraintError) Error() string {
 	return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, r.Err.Error())
 }
 
+// ConstraintID returns the ID of the unsatisfied constraint.
+func (r *UnsatisfiedConstraintError) ConstraintID() int {
+	return r.CID
+}
+
 func (solver *solver) wrapErrWithDebugInfo(cID uint32, err error) *UnsatisfiedConstraintError {
 	var debugInfo *string
 	if dID, ok := solver.MDebug[int(cID)]; ok {
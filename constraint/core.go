@@ -9,6 +9,7 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
 	"github.com/airchains-network/gnark/constraint/solver"
 	"github.com/airchains-network/gnark/debug"
 	"github.com/airchains-network/gnark/internal/tinyfield"
@@ -104,6 +105,8 @@ type System struct {
 	// maps constraint id to debugInfo id
 	// several constraints may point to the same debug info
 	MDebug map[int]int
+	// maps hint instruction id to debugInfo id
+	MHintsDebug map[int]int
 
 	// maps hintID to hint string identifier
 	MHintsDependencies map[solver.HintID]string
@@ -135,6 +138,7 @@ func NewSystem(scalarField *big.Int, capacity int, t SystemType) System {
 		Type:               t,
 		SymbolTable:        debug.NewSymbolTable(),
 		MDebug:             map[int]int{},
+		MHintsDebug:        map[int]int{},
 		GnarkVersion:       gnark.Version.String(),
 		ScalarField:        scalarField.Text(16),
 		MHintsDependencies: make(map[solver.HintID]string),
@@ -161,6 +165,11 @@ func (system *System) GetInstruction(id int) Instruction {
 	return system.Instructions[id].Unpack(system)
 }
 
+// GetInstructionBlueprint returns the blueprint of the instruction at index id
+func (system *System) GetInstructionBlueprint(id int) Blueprint {
+	return system.Blueprints[system.Instructions[id].BlueprintID]
+}
+
 // AddBlueprint adds a blueprint to the system and returns its ID
 func (system *System) AddBlueprint(b Blueprint) BlueprintID {
 	system.Blueprints = append(system.Blueprints, b)
@@ -202,7 +211,7 @@ func (system *System) CheckSerializationHeader() error {
 		return fmt.Errorf("when parsing serialized modulus: %s", system.ScalarField)
 	}
 	curveID := utils.FieldToCurve(scalarField)
-	if curveID == ecc.UNKNOWN && scalarField.Cmp(tinyfield.Modulus()) != 0 {
+	if curveID == ecc.UNKNOWN && scalarField.Cmp(tinyfield.Modulus()) != 0 && scalarField.Cmp(goldilocks.Modulus()) != 0 {
 		return fmt.Errorf("unsupported scalar field %s", scalarField.Text(16))
 	}
 	system.q = new(big.Int).Set(scalarField)
@@ -300,6 +309,11 @@ func (system *System) AddSolverHint(f solver.Hint, id solver.HintID, input []Lin
 	// return []uint32 to the pool
 	putBuffer(calldata)
 
+	if debug.Debug {
+		system.DebugInfo = append(system.DebugInfo, LogEntry(system.NewDebugInfo("hint", name)))
+		system.MHintsDebug[len(system.Instructions)-1] = len(system.DebugInfo) - 1
+	}
+
 	return
 }
 
@@ -426,11 +440,6 @@ func (cs *System) GetNbConstraints() int {
 	return cs.NbConstraints
 }
 
-func (cs *System) CheckUnconstrainedWires() error {
-	// TODO @gbotrel
-	return nil
-}
-
 func (cs *System) GetR1CIterator() R1CIterator {
 	return R1CIterator{cs: cs}
 }
@@ -3,6 +3,7 @@ package constraint
 import (
 	"strings"
 
+	"github.com/airchains-network/gnark/debug"
 	"github.com/airchains-network/gnark/internal/utils"
 )
 
@@ -40,3 +41,13 @@ func (system *System) NewDebugInfo(errName string, i ...interface{}) DebugInfo {
 
 	return DebugInfo(l)
 }
+
+// GetConstraintStack returns the stack trace of the debug info attached to the
+// constraint cID, innermost frame first, or nil if it has none.
+func (system *System) GetConstraintStack(cID int) []debug.Frame {
+	dID, ok := system.MDebug[cID]
+	if !ok {
+		return nil
+	}
+	return system.SymbolTable.Frames(system.DebugInfo[dID].Stack)
+}
@@ -0,0 +1,375 @@
+// Package diff compares compiled constraint systems.
+//
+// The constraints of the two systems are compared symbolically: internal wires
+// are anonymized, so that constraints added by the same code have the same shape
+// in both systems. The constraints are grouped by the source location which
+// added them, taken from a profiling session run during the compilation (see
+// Compile and New) or else from the debug info of the constraint system.
+//
+// This is experimental.
+package diff
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/airchains-network/gnark/debug"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/profile"
+)
+
+// System is a compiled constraint system, along with the stack traces of its
+// constraints.
+type System struct {
+	constraint.ConstraintSystem
+	stacks [][]debug.Frame
+}
+
+// New returns a System from a compiled constraint system. If p is not nil, it
+// must be a profiling session which spanned exactly the compilation of ccs, and
+// the constraints are located with its samples. Otherwise they are located
+// with the debug info of ccs; constraints without debug info are grouped under
+// an unknown location.
+func New(ccs constraint.ConstraintSystem, p *profile.Profile) (*System, error) {
+	s := &System{ConstraintSystem: ccs}
+	if p != nil {
+		s.stacks = p.Stacks()
+		if len(s.stacks) != ccs.GetNbConstraints() {
+			return nil, fmt.Errorf("profile has %d samples, but the constraint system has %d constraints", len(s.stacks), ccs.GetNbConstraints())
+		}
+	}
+	return s, nil
+}
+
+// Compile compiles the circuit with frontend.Compile within a profiling session
+// and returns the resulting System. Like the profile package, it must not be
+// called concurrently with other compilations.
+func Compile(field *big.Int, newBuilder frontend.NewBuilder, circuit frontend.Circuit, opts ...frontend.CompileOption) (*System, error) {
+	p := profile.Start(profile.WithNoOutput())
+	ccs, err := frontend.Compile(field, newBuilder, circuit, opts...)
+	p.Stop()
+	if err != nil {
+		return nil, err
+	}
+	return New(ccs, p)
+}
+
+// Option defines options for Diff.
+type Option func(*config)
+
+type config struct {
+	byLine bool
+}
+
+// WithLineGranularity groups the constraints by function and line, instead of
+// by function only.
+func WithLineGranularity() Option {
+	return func(c *config) {
+		c.byLine = true
+	}
+}
+
+// Stats summarizes the size of a constraint system.
+type Stats struct {
+	NbConstraints       int
+	NbInstructions      int
+	NbInternalVariables int
+	NbPublicVariables   int
+	NbSecretVariables   int
+}
+
+// Shape is a constraint with its internal wires anonymized, and the number of
+// times it occurs.
+type Shape struct {
+	Constraint string
+	Count      int
+}
+
+// LocationDiff lists the constraints added and removed at a source location.
+type LocationDiff struct {
+	Location       string
+	Before, After  int // number of constraints
+	Added, Removed []Shape
+}
+
+// BlueprintDiff is the change in the number of instructions using a blueprint.
+type BlueprintDiff struct {
+	Blueprint     string
+	Before, After int
+}
+
+// Report is the result of Diff.
+type Report struct {
+	Before, After Stats
+
+	// Locations with added or removed constraints, by decreasing absolute change.
+	Locations []LocationDiff
+
+	// Blueprints with a different number of instructions.
+	Blueprints []BlueprintDiff
+
+	// Commitments describes the commitments of each system, if they differ.
+	CommitmentsBefore, CommitmentsAfter []string
+}
+
+// Diff compares the constraint systems before and after.
+func Diff(before, after *System, opts ...Option) *Report {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	r := &Report{
+		Before: stats(before),
+		After:  stats(after),
+	}
+
+	// constraints per location
+	shapesBefore := before.shapes(&cfg)
+	shapesAfter := after.shapes(&cfg)
+	for location := range shapesAfter {
+		if _, ok := shapesBefore[location]; !ok {
+			shapesBefore[location] = nil
+		}
+	}
+	for location, b := range shapesBefore {
+		a := shapesAfter[location]
+		ld := LocationDiff{Location: location}
+		for shape, n := range b {
+			ld.Before += n
+			if d := n - a[shape]; d > 0 {
+				ld.Removed = append(ld.Removed, Shape{shape, d})
+			}
+		}
+		for shape, n := range a {
+			ld.After += n
+			if d := n - b[shape]; d > 0 {
+				ld.Added = append(ld.Added, Shape{shape, d})
+			}
+		}
+		if len(ld.Added) == 0 && len(ld.Removed) == 0 {
+			continue
+		}
+		sortShapes(ld.Added)
+		sortShapes(ld.Removed)
+		r.Locations = append(r.Locations, ld)
+	}
+	sort.Slice(r.Locations, func(i, j int) bool {
+		di, dj := abs(r.Locations[i].After-r.Locations[i].Before), abs(r.Locations[j].After-r.Locations[j].Before)
+		if di != dj {
+			return di > dj
+		}
+		return r.Locations[i].Location < r.Locations[j].Location
+	})
+
+	// instructions per blueprint
+	bBefore, bAfter := blueprints(before), blueprints(after)
+	for name := range bAfter {
+		if _, ok := bBefore[name]; !ok {
+			bBefore[name] = 0
+		}
+	}
+	for name, n := range bBefore {
+		if n != bAfter[name] {
+			r.Blueprints = append(r.Blueprints, BlueprintDiff{name, n, bAfter[name]})
+		}
+	}
+	sort.Slice(r.Blueprints, func(i, j int) bool { return r.Blueprints[i].Blueprint < r.Blueprints[j].Blueprint })
+
+	// commitments
+	cBefore, cAfter := commitments(before), commitments(after)
+	if strings.Join(cBefore, "\n") != strings.Join(cAfter, "\n") {
+		r.CommitmentsBefore, r.CommitmentsAfter = cBefore, cAfter
+	}
+
+	return r
+}
+
+// String formats the report.
+func (r *Report) String() string {
+	var sbb strings.Builder
+	writeDelta := func(name string, before, after int) {
+		if before == after {
+			fmt.Fprintf(&sbb, "%s: %d\n", name, before)
+			return
+		}
+		fmt.Fprintf(&sbb, "%s: %d -> %d (%+d)\n", name, before, after, after-before)
+	}
+	writeDelta("constraints", r.Before.NbConstraints, r.After.NbConstraints)
+	writeDelta("instructions", r.Before.NbInstructions, r.After.NbInstructions)
+	writeDelta("internal variables", r.Before.NbInternalVariables, r.After.NbInternalVariables)
+	writeDelta("public variables", r.Before.NbPublicVariables, r.After.NbPublicVariables)
+	writeDelta("secret variables", r.Before.NbSecretVariables, r.After.NbSecretVariables)
+
+	if len(r.Blueprints) != 0 {
+		sbb.WriteString("\nblueprints:\n")
+		for _, b := range r.Blueprints {
+			fmt.Fprintf(&sbb, "  %s: %d -> %d (%+d)\n", b.Blueprint, b.Before, b.After, b.After-b.Before)
+		}
+	}
+
+	if r.CommitmentsBefore != nil || r.CommitmentsAfter != nil {
+		sbb.WriteString("\ncommitments:\n")
+		for _, c := range r.CommitmentsBefore {
+			fmt.Fprintf(&sbb, "  - %s\n", c)
+		}
+		for _, c := range r.CommitmentsAfter {
+			fmt.Fprintf(&sbb, "  + %s\n", c)
+		}
+	}
+
+	if len(r.Locations) != 0 {
+		sbb.WriteString("\nconstraints by location:\n")
+		for _, l := range r.Locations {
+			fmt.Fprintf(&sbb, "  %s: %d -> %d (%+d)\n", l.Location, l.Before, l.After, l.After-l.Before)
+			for _, s := range l.Removed {
+				fmt.Fprintf(&sbb, "    - %d× %s\n", s.Count, s.Constraint)
+			}
+			for _, s := range l.Added {
+				fmt.Fprintf(&sbb, "    + %d× %s\n", s.Count, s.Constraint)
+			}
+		}
+	}
+	return sbb.String()
+}
+
+const unknownLocation = "<unknown>"
+
+// shapes returns the number of constraints of each shape, per location.
+func (s *System) shapes(cfg *config) map[string]map[string]int {
+	res := make(map[string]map[string]int)
+	add := func(cID int, shape string) {
+		location := s.location(cID, cfg)
+		if res[location] == nil {
+			res[location] = make(map[string]int)
+		}
+		res[location][shape]++
+	}
+
+	r := symbolicResolver{s.ConstraintSystem}
+	switch ccs := s.ConstraintSystem.(type) {
+	case constraint.R1CS:
+		it := ccs.GetR1CIterator()
+		for c, cID := it.Next(), 0; c != nil; c, cID = it.Next(), cID+1 {
+			add(cID, c.String(r))
+		}
+	case constraint.SparseR1CS:
+		it := ccs.GetSparseR1CIterator()
+		for c, cID := it.Next(), 0; c != nil; c, cID = it.Next(), cID+1 {
+			add(cID, c.String(r))
+		}
+	}
+	return res
+}
+
+// constraintStacker is implemented by the constraint systems which record the
+// stack traces of the constraints in their debug info.
+type constraintStacker interface {
+	GetConstraintStack(int) []debug.Frame
+}
+
+// instructionBlueprinter is implemented by the constraint systems which expose
+// the blueprints of their instructions.
+type instructionBlueprinter interface {
+	GetInstructionBlueprint(int) constraint.Blueprint
+}
+
+// location returns the innermost frame of the stack of the constraint which is
+// not in the frontend.
+func (s *System) location(cID int, cfg *config) string {
+	var stack []debug.Frame
+	if s.stacks != nil {
+		stack = s.stacks[cID]
+	} else if cs, ok := s.ConstraintSystem.(constraintStacker); ok {
+		stack = cs.GetConstraintStack(cID)
+	}
+	for _, frame := range stack {
+		if strings.HasPrefix(frame.Function, frontendPrefix) {
+			continue
+		}
+		fe := strings.Split(frame.Function, "/")
+		function := fe[len(fe)-1]
+		if cfg.byLine {
+			return fmt.Sprintf("%s %s:%d", function, filepath.Base(frame.File), frame.Line)
+		}
+		return function
+	}
+	return unknownLocation
+}
+
+const frontendPrefix = "github.com/airchains-network/gnark/frontend"
+
+// symbolicResolver resolves the internal wires to "v", so that constraints can
+// be compared across systems.
+type symbolicResolver struct {
+	ccs constraint.ConstraintSystem
+}
+
+func (r symbolicResolver) CoeffToString(coeffID int) string {
+	return r.ccs.CoeffToString(coeffID)
+}
+
+func (r symbolicResolver) VariableToString(variableID int) string {
+	if variableID < r.ccs.GetNbPublicVariables()+r.ccs.GetNbSecretVariables() {
+		return r.ccs.VariableToString(variableID)
+	}
+	return "v"
+}
+
+func stats(s *System) Stats {
+	return Stats{
+		NbConstraints:       s.GetNbConstraints(),
+		NbInstructions:      s.GetNbInstructions(),
+		NbInternalVariables: s.GetNbInternalVariables(),
+		NbPublicVariables:   s.GetNbPublicVariables(),
+		NbSecretVariables:   s.GetNbSecretVariables(),
+	}
+}
+
+func blueprints(s *System) map[string]int {
+	res := make(map[string]int)
+	cs, ok := s.ConstraintSystem.(instructionBlueprinter)
+	if !ok {
+		return res
+	}
+	for i := 0; i < s.GetNbInstructions(); i++ {
+		res[constraint.BlueprintName(cs.GetInstructionBlueprint(i))]++
+	}
+	return res
+}
+
+func commitments(s *System) []string {
+	var res []string
+	switch c := s.GetCommitments().(type) {
+	case constraint.Groth16Commitments:
+		for i := range c {
+			res = append(res, fmt.Sprintf("groth16 commitment #%d: %d public, %d private committed wires",
+				i, c[i].NbPublicCommitted, len(c[i].PublicAndCommitmentCommitted)-c[i].NbPublicCommitted+len(c[i].PrivateCommitted)))
+		}
+	case constraint.PlonkCommitments:
+		for i := range c {
+			res = append(res, fmt.Sprintf("plonk commitment #%d: %d committed values", i, len(c[i].Committed)))
+		}
+	}
+	return res
+}
+
+func sortShapes(shapes []Shape) {
+	sort.Slice(shapes, func(i, j int) bool {
+		if shapes[i].Count != shapes[j].Count {
+			return shapes[i].Count > shapes[j].Count
+		}
+		return shapes[i].Constraint < shapes[j].Constraint
+	})
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
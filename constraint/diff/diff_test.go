@@ -0,0 +1,57 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/airchains-network/gnark/constraint/diff"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/r1cs"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/require"
+)
+
+type powCircuit struct {
+	X, Y frontend.Variable
+	n    int
+}
+
+func (c *powCircuit) Define(api frontend.API) error {
+	acc := c.X
+	for i := 1; i < c.n; i++ {
+		acc = api.Mul(acc, c.X)
+	}
+	api.AssertIsEqual(acc, c.Y)
+	return nil
+}
+
+func TestDiff(t *testing.T) {
+	assert := require.New(t)
+
+	before, err := diff.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &powCircuit{n: 3})
+	assert.NoError(err)
+	after, err := diff.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &powCircuit{n: 2})
+	assert.NoError(err)
+
+	r := diff.Diff(before, after)
+	assert.Equal(3, r.Before.NbConstraints)
+	assert.Equal(2, r.After.NbConstraints)
+	assert.Len(r.Locations, 1)
+	assert.Contains(r.Locations[0].Location, "powCircuit")
+	assert.Empty(r.Locations[0].Added)
+	assert.Len(r.Locations[0].Removed, 1)
+	assert.Equal(1, r.Locations[0].Removed[0].Count)
+	assert.Contains(r.String(), "constraints: 3 -> 2 (-1)")
+
+	// same system
+	r = diff.Diff(after, after)
+	assert.Empty(r.Locations)
+	assert.Empty(r.Blueprints)
+
+	// different frontends
+	plonk, err := diff.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &powCircuit{n: 2})
+	assert.NoError(err)
+	r = diff.Diff(after, plonk)
+	assert.NotEmpty(r.Blueprints)
+	assert.NotEmpty(r.Locations)
+}
@@ -0,0 +1,292 @@
+package constraint
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Dump is a human-readable representation of a constraint system. It can be
+// serialized to JSON, or written as text with WriteText.
+//
+// This is experimental.
+type Dump struct {
+	Type         string `json:"type"`
+	Field        string `json:"field"`
+	GnarkVersion string `json:"gnarkVersion"`
+
+	NbConstraints       int `json:"nbConstraints"`
+	NbInternalVariables int `json:"nbInternalVariables"`
+
+	Public []string `json:"public"`
+	Secret []string `json:"secret"`
+
+	// Blueprints are the names of the blueprints, indexed by blueprint ID.
+	Blueprints []string `json:"blueprints"`
+
+	Instructions []DumpInstruction `json:"instructions"`
+
+	Commitments Commitments `json:"commitments,omitempty"`
+	Gkr         *GkrInfo    `json:"gkr,omitempty"`
+}
+
+// DumpInstruction is a human-readable representation of an instruction.
+// Variables are named with VariableToString.
+type DumpInstruction struct {
+	ID        int    `json:"id"`
+	Blueprint string `json:"blueprint"`
+
+	// Constraint is the ID of the first constraint of the instruction, or -1 if
+	// it doesn't create constraints.
+	Constraint int `json:"constraint"`
+
+	// Expression is the constraint, for R1C and SparseR1C instructions.
+	Expression string `json:"expression,omitempty"`
+
+	// Hint is the name of the hint, for hint instructions.
+	Hint   string   `json:"hint,omitempty"`
+	Inputs []string `json:"inputs,omitempty"` // inputs of the hint
+
+	Outputs []string `json:"outputs,omitempty"`
+
+	// Wires are the variables the instruction refers to.
+	Wires []string `json:"wires"`
+
+	// Stack is the stack trace of the debug info of the constraint, if any, one
+	// "function file:line" frame per entry.
+	Stack []string `json:"stack,omitempty"`
+}
+
+// NewDump returns the Dump of the constraint system.
+func NewDump(ccs ConstraintSystem) (*Dump, error) {
+	s, ok := ccs.(interface{ core() *System })
+	if !ok {
+		return nil, fmt.Errorf("unsupported constraint system type %T", ccs)
+	}
+	cs := s.core()
+
+	d := &Dump{
+		Type:                systemTypeName(cs.Type),
+		Field:               cs.ScalarField,
+		GnarkVersion:        cs.GnarkVersion,
+		NbConstraints:       cs.GetNbConstraints(),
+		NbInternalVariables: cs.GetNbInternalVariables(),
+		Public:              cs.Public,
+		Secret:              cs.Secret,
+		Blueprints:          make([]string, len(cs.Blueprints)),
+		Instructions:        make([]DumpInstruction, len(cs.Instructions)),
+		Commitments:         cs.CommitmentInfo,
+	}
+	if cs.GkrInfo.Is() {
+		d.Gkr = &cs.GkrInfo
+	}
+	for i, b := range cs.Blueprints {
+		d.Blueprints[i] = BlueprintName(b)
+	}
+
+	nbWires := cs.GetNbPublicVariables() + cs.GetNbSecretVariables() + cs.NbInternalVariables
+	var (
+		r1c  R1C
+		sr1c SparseR1C
+		hm   HintMapping
+	)
+	for iID, pi := range cs.Instructions {
+		blueprint := cs.Blueprints[pi.BlueprintID]
+		inst := pi.Unpack(cs)
+		di := DumpInstruction{
+			ID:         iID,
+			Blueprint:  d.Blueprints[pi.BlueprintID],
+			Constraint: -1,
+		}
+		if blueprint.NbConstraints() != 0 {
+			di.Constraint = int(pi.ConstraintOffset)
+		}
+
+		seen := make(map[uint32]struct{})
+		addWire := func(wire uint32) {
+			if int(wire) >= nbWires {
+				return // constant
+			}
+			if _, ok := seen[wire]; !ok {
+				seen[wire] = struct{}{}
+				di.Wires = append(di.Wires, ccs.VariableToString(int(wire)))
+			}
+		}
+		addLinearExpression := func(l LinearExpression) {
+			for _, t := range l {
+				addWire(t.VID)
+			}
+		}
+
+		switch b := blueprint.(type) {
+		case BlueprintR1C:
+			b.DecompressR1C(&r1c, inst)
+			di.Expression = r1c.String(ccs)
+			addLinearExpression(r1c.L)
+			addLinearExpression(r1c.R)
+			addLinearExpression(r1c.O)
+		case BlueprintSparseR1C:
+			b.DecompressSparseR1C(&sr1c, inst)
+			di.Expression = sr1c.String(ccs)
+			addWire(sr1c.XA)
+			addWire(sr1c.XB)
+			addWire(sr1c.XC)
+		case BlueprintHint:
+			b.DecompressHint(&hm, inst)
+			if name, ok := cs.MHintsDependencies[hm.HintID]; ok {
+				di.Hint = name
+			} else {
+				di.Hint = strconv.Itoa(int(hm.HintID))
+			}
+			for _, l := range hm.Inputs {
+				di.Inputs = append(di.Inputs, l.String(ccs))
+				addLinearExpression(l)
+			}
+			for w := hm.OutputRange.Start; w < hm.OutputRange.End; w++ {
+				di.Outputs = append(di.Outputs, ccs.VariableToString(int(w)))
+				addWire(w)
+			}
+		default:
+			walker := wireWalker{nbWires: nbWires}
+			blueprint.UpdateInstructionTree(inst, &walker)
+			for _, w := range walker.wires {
+				addWire(w)
+			}
+			for i := 0; i < blueprint.NbOutputs(inst); i++ {
+				w := inst.WireOffset + uint32(i)
+				di.Outputs = append(di.Outputs, ccs.VariableToString(int(w)))
+				addWire(w)
+			}
+		}
+
+		var stack []int
+		if dID, ok := cs.MDebug[di.Constraint]; ok && di.Constraint >= 0 {
+			stack = cs.DebugInfo[dID].Stack
+		} else if dID, ok := cs.MHintsDebug[iID]; ok {
+			stack = cs.DebugInfo[dID].Stack
+		}
+		for _, frame := range cs.SymbolTable.Frames(stack) {
+			fe := strings.Split(frame.Function, "/")
+			di.Stack = append(di.Stack, fmt.Sprintf("%s %s:%d", fe[len(fe)-1], frame.File, frame.Line))
+		}
+
+		d.Instructions[iID] = di
+	}
+
+	return d, nil
+}
+
+// Select returns a copy of the dump with only the instructions for which keep
+// returns true.
+func (d *Dump) Select(keep func(*DumpInstruction) bool) *Dump {
+	res := *d
+	res.Instructions = nil
+	for i := range d.Instructions {
+		if keep(&d.Instructions[i]) {
+			res.Instructions = append(res.Instructions, d.Instructions[i])
+		}
+	}
+	return &res
+}
+
+// HasWire returns true if the instruction refers to the variable.
+func (di *DumpInstruction) HasWire(name string) bool {
+	for _, w := range di.Wires {
+		if w == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLocation returns true if a frame of the stack trace of the instruction
+// contains location, for instance "circuit.go:42".
+func (di *DumpInstruction) HasLocation(location string) bool {
+	for _, frame := range di.Stack {
+		if strings.Contains(frame, location) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes the dump in a human-readable text format, one instruction
+// per line followed by its stack trace. The lines are written to w as they are
+// formatted, through a buffer.
+func (d *Dump) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s over 0x%s (gnark %s)\n", d.Type, d.Field, d.GnarkVersion)
+	fmt.Fprintf(bw, "%d constraints, %d instructions, %d public, %d secret and %d internal variables\n",
+		d.NbConstraints, len(d.Instructions), len(d.Public), len(d.Secret), d.NbInternalVariables)
+	fmt.Fprintf(bw, "public: %s\n", strings.Join(d.Public, ", "))
+	fmt.Fprintf(bw, "secret: %s\n", strings.Join(d.Secret, ", "))
+	fmt.Fprintf(bw, "blueprints: %s\n", strings.Join(d.Blueprints, ", "))
+	switch c := d.Commitments.(type) {
+	case Groth16Commitments:
+		for i := range c {
+			fmt.Fprintf(bw, "commitment #%d: wire %d, committed %v and %v\n", i, c[i].CommitmentIndex, c[i].PublicAndCommitmentCommitted, c[i].PrivateCommitted)
+		}
+	case PlonkCommitments:
+		for i := range c {
+			fmt.Fprintf(bw, "commitment #%d: constraint %d, committed %v\n", i, c[i].CommitmentIndex, c[i].Committed)
+		}
+	}
+	if d.Gkr != nil {
+		fmt.Fprintf(bw, "gkr: %d wires, %d instances, hash %s\n", len(d.Gkr.Circuit), d.Gkr.NbInstances, d.Gkr.HashName)
+	}
+	if err := bw.WriteByte('\n'); err != nil {
+		return err
+	}
+
+	for _, di := range d.Instructions {
+		fmt.Fprintf(bw, "#%d %s", di.ID, di.Blueprint)
+		if di.Constraint >= 0 {
+			fmt.Fprintf(bw, " c%d", di.Constraint)
+		}
+		switch {
+		case di.Expression != "":
+			bw.WriteString(": ")
+			bw.WriteString(di.Expression)
+		case di.Hint != "":
+			fmt.Fprintf(bw, ": %s = %s(%s)", strings.Join(di.Outputs, ", "), di.Hint, strings.Join(di.Inputs, ", "))
+		default:
+			fmt.Fprintf(bw, ": wires %s", strings.Join(di.Wires, ", "))
+		}
+		for _, frame := range di.Stack {
+			bw.WriteString("\n\t")
+			bw.WriteString(frame)
+		}
+		// the bufio.Writer keeps the first write error and fails all the
+		// following writes, so checking the last one of the line is enough
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// core returns the System; it allows retrieving the System from the curve
+// typed constraint systems which embed it.
+func (system *System) core() *System {
+	return system
+}
+
+// BlueprintName returns the name of the type of the blueprint, without the
+// package path.
+func BlueprintName(b Blueprint) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", b), "*")
+}
+
+func systemTypeName(t SystemType) string {
+	switch t {
+	case SystemR1CS:
+		return "R1CS"
+	case SystemSparseR1CS:
+		return "SparseR1CS"
+	default:
+		return "unknown"
+	}
+}
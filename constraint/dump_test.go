@@ -0,0 +1,81 @@
+package constraint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/airchains-network/gnark/backend/groth16"
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/r1cs"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	assert := require.New(t)
+
+	// the hint output is determined by the constraint
+	circuit := &unconstrainedCircuit{kind: 3}
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, circuit)
+		assert.NoError(err)
+
+		d, err := constraint.NewDump(ccs)
+		assert.NoError(err)
+		assert.Equal(ccs.GetNbConstraints(), d.NbConstraints)
+		assert.Len(d.Instructions, ccs.GetNbInstructions())
+
+		hints := d.Select(func(di *constraint.DumpInstruction) bool { return di.Hint != "" })
+		assert.Len(hints.Instructions, 1)
+		assert.Contains(hints.Instructions[0].Hint, "splitHint")
+		assert.True(hints.Instructions[0].HasWire("X"))
+
+		assert.NotEmpty(d.Select(func(di *constraint.DumpInstruction) bool { return di.HasWire("Y") }).Instructions)
+
+		_, err = json.Marshal(d)
+		assert.NoError(err)
+
+		var buf bytes.Buffer
+		assert.NoError(d.WriteText(&buf))
+		assert.Contains(buf.String(), "splitHint")
+
+		// write errors are returned
+		assert.ErrorIs(d.WriteText(&failingWriter{n: buf.Len() / 2}), errWrite)
+	}
+
+	// the dump of a deserialized system is the same
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit)
+	assert.NoError(err)
+	var buf bytes.Buffer
+	_, err = ccs.WriteTo(&buf)
+	assert.NoError(err)
+	read := groth16.NewCS(ecc.BN254)
+	_, err = read.ReadFrom(&buf)
+	assert.NoError(err)
+
+	expected, err := constraint.NewDump(ccs)
+	assert.NoError(err)
+	d, err := constraint.NewDump(read)
+	assert.NoError(err)
+	assert.Equal(expected.Instructions, d.Instructions)
+}
+
+var errWrite = errors.New("write error")
+
+// failingWriter fails once more than n bytes are written.
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return 0, errWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
@@ -52,84 +52,6 @@ func (it *SparseR1CIterator) Next() *SparseR1C {
 	return it.Next()
 }
 
-// func (system *SparseR1CSCore) CheckUnconstrainedWires() error {
-// 	// TODO @gbotrel add unit test for that.
-// 	return nil
-// inputConstrained := make([]bool, system.GetNbSecretVariables()+system.GetNbPublicVariables())
-// cptInputs := len(inputConstrained)
-// if cptInputs == 0 {
-// 	return errors.New("invalid constraint system: no input defined")
-// }
-
-// cptHints := len(system.MHints)
-// mHintsConstrained := make(map[int]bool)
-
-// // for each constraint, we check the terms and mark our inputs / hints as constrained
-// processTerm := func(t Term) {
-
-// 	// L and M[0] handles the same wire but with a different coeff
-// 	vID := t.WireID()
-// 	if t.CoeffID() != CoeffIdZero {
-// 		if vID < len(inputConstrained) {
-// 			if !inputConstrained[vID] {
-// 				inputConstrained[vID] = true
-// 				cptInputs--
-// 			}
-// 		} else {
-// 			// internal variable, let's check if it's a hint
-// 			if _, ok := system.MHints[vID]; ok {
-// 				vID -= (system.GetNbPublicVariables() + system.GetNbSecretVariables())
-// 				if !mHintsConstrained[vID] {
-// 					mHintsConstrained[vID] = true
-// 					cptHints--
-// 				}
-// 			}
-// 		}
-// 	}
-
-// }
-// for _, c := range system.Constraints {
-// 	processTerm(c.L)
-// 	processTerm(c.R)
-// 	processTerm(c.M[0])
-// 	processTerm(c.M[1])
-// 	processTerm(c.O)
-// 	if cptHints|cptInputs == 0 {
-// 		return nil // we can stop.
-// 	}
-
-// }
-
-// // something is a miss, we build the error string
-// var sbb strings.Builder
-// if cptInputs != 0 {
-// 	sbb.WriteString(strconv.Itoa(cptInputs))
-// 	sbb.WriteString(" unconstrained input(s):")
-// 	sbb.WriteByte('\n')
-// 	for i := 0; i < len(inputConstrained) && cptInputs != 0; i++ {
-// 		if !inputConstrained[i] {
-// 			if i < len(system.Public) {
-// 				sbb.WriteString(system.Public[i])
-// 			} else {
-// 				sbb.WriteString(system.Secret[i-len(system.Public)])
-// 			}
-// 			sbb.WriteByte('\n')
-// 			cptInputs--
-// 		}
-// 	}
-// 	sbb.WriteByte('\n')
-// }
-
-// if cptHints != 0 {
-// 	sbb.WriteString(strconv.Itoa(cptHints))
-// 	sbb.WriteString(" unconstrained hints")
-// 	sbb.WriteByte('\n')
-// 	// TODO we may add more debug info here → idea, in NewHint, take the debug stack, and store in the hint map some
-// 	// debugInfo to find where a hint was declared (and not constrained)
-// }
-// return errors.New(sbb.String())
-// }
-
 type CommitmentConstraint uint32
 
 const (
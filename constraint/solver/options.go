@@ -14,6 +14,10 @@ type Option func(*Config) error
 type Config struct {
 	HintFunctions map[HintID]Hint // defaults to all built-in hint functions
 	Logger        zerolog.Logger  // defaults to gnark.Logger
+
+	// R1CSVectorAllocator allocates the vectors a, b and c of the R1CS
+	// solutions, see WithR1CSVectorAllocator. Defaults to the heap if nil.
+	R1CSVectorAllocator func(n int) (any, error)
 }
 
 // WithHints is a solver option that specifies additional hint functions to be used
@@ -53,6 +57,19 @@ func WithLogger(l zerolog.Logger) Option {
 	}
 }
 
+// WithR1CSVectorAllocator is a solver option that specifies the function
+// allocating the vectors in which the solver of a R1CS stores the evaluations
+// of the linear expressions a, b and c of the constraints. alloc must return n
+// zero elements of the field of the constraint system (e.g. a []fr.Element of
+// gnark-crypto), n being the size of the FFT domain of the constraint system.
+// It is used by the out-of-core Groth16 prover to back the vectors by files.
+func WithR1CSVectorAllocator(alloc func(n int) (any, error)) Option {
+	return func(opt *Config) error {
+		opt.R1CSVectorAllocator = alloc
+		return nil
+	}
+}
+
 // NewConfig returns a default SolverConfig with given prover options opts applied.
 func NewConfig(opts ...Option) (Config, error) {
 	log := logger.Logger()
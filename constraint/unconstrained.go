@@ -0,0 +1,324 @@
+package constraint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnconstrainedKind qualifies why a wire is reported by FindUnconstrainedWires.
+type UnconstrainedKind uint8
+
+const (
+	// UnconstrainedInput is a public or secret input that appears in no constraint.
+	UnconstrainedInput UnconstrainedKind = iota
+
+	// UnconstrainedOutput is a wire computed by a hint (or by a blueprint which
+	// doesn't constrain its outputs) that appears in no constraint.
+	UnconstrainedOutput
+
+	// UnderconstrainedOutput is a wire computed by a hint (or by a blueprint which
+	// doesn't constrain its outputs) that appears in a single constraint, together
+	// with other such wires appearing only in this constraint. The constraint
+	// doesn't determine them uniquely: for instance, hint outputs only used in a
+	// linear combination.
+	UnderconstrainedOutput
+)
+
+func (k UnconstrainedKind) String() string {
+	switch k {
+	case UnconstrainedInput:
+		return "unconstrained input"
+	case UnconstrainedOutput:
+		return "unconstrained hint output"
+	case UnderconstrainedOutput:
+		return "underconstrained hint output"
+	default:
+		return "UnconstrainedKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// UnconstrainedWire describes a wire which is not uniquely determined by the
+// constraints of the system.
+type UnconstrainedWire struct {
+	Kind UnconstrainedKind
+	Wire int
+	Name string // name of the input, or of the internal wire
+
+	// Hint is the name of the hint which computes the wire, if any.
+	Hint string
+
+	// Constraint is the only constraint the wire appears in, or -1.
+	Constraint int
+
+	// Stack is the stack trace of the creation of the hint or of the constraint,
+	// when the system is compiled with the debug build tag.
+	Stack string
+}
+
+func (w UnconstrainedWire) String() string {
+	var sbb strings.Builder
+	sbb.WriteString(w.Kind.String())
+	sbb.WriteByte(' ')
+	sbb.WriteString(w.Name)
+	if w.Hint != "" {
+		sbb.WriteString(" (hint ")
+		sbb.WriteString(w.Hint)
+		sbb.WriteByte(')')
+	}
+	if w.Constraint >= 0 {
+		sbb.WriteString(" in constraint #")
+		sbb.WriteString(strconv.Itoa(w.Constraint))
+	}
+	if w.Stack != "" {
+		sbb.WriteByte('\n')
+		sbb.WriteString(w.Stack)
+	}
+	return sbb.String()
+}
+
+// UnconstrainedWiresError is returned by CheckUnconstrainedWires.
+type UnconstrainedWiresError struct {
+	Wires []UnconstrainedWire
+}
+
+func (e *UnconstrainedWiresError) Error() string {
+	var sbb strings.Builder
+	sbb.WriteString(strconv.Itoa(len(e.Wires)))
+	sbb.WriteString(" wire(s) not uniquely constrained:")
+	for _, w := range e.Wires {
+		sbb.WriteString("\n  ")
+		sbb.WriteString(strings.ReplaceAll(w.String(), "\n", "\n    "))
+	}
+	return sbb.String()
+}
+
+// CheckUnconstrainedWires returns an *UnconstrainedWiresError listing the wires
+// reported by FindUnconstrainedWires, if any.
+func (cs *System) CheckUnconstrainedWires() error {
+	if wires := cs.findUnconstrainedWires(); len(wires) != 0 {
+		return &UnconstrainedWiresError{Wires: wires}
+	}
+	return nil
+}
+
+// FindUnconstrainedWires analyzes the instructions of cs and returns the
+// inputs which appear in no constraint, and the outputs of hints (or of
+// blueprints which don't constrain their outputs) which appear in no
+// constraint or which are not uniquely determined by the only constraint they
+// appear in (see UnconstrainedKind). The wires are sorted by wire ID.
+//
+// The analysis is a heuristic: a reported wire is likely a bug in the circuit,
+// but a wire which isn't reported may still be underconstrained.
+func FindUnconstrainedWires(cs ConstraintSystem) []UnconstrainedWire {
+	if cs, ok := cs.(interface{ findUnconstrainedWires() []UnconstrainedWire }); ok {
+		return cs.findUnconstrainedWires()
+	}
+	return nil
+}
+
+func (cs *System) findUnconstrainedWires() []UnconstrainedWire {
+	nbInputs := cs.GetNbPublicVariables() + cs.GetNbSecretVariables()
+	nbWires := nbInputs + cs.NbInternalVariables
+
+	const (
+		notFree = iota
+		free    // output of a hint, or of a blueprint which doesn't constrain it
+	)
+	var (
+		status = make([]uint8, nbWires)
+		// number of constraints each wire appears in, and the last of them
+		nbConstraints  = make([]int, nbWires)
+		lastConstraint = make([]int, nbWires)
+		// instruction computing each free wire
+		producer = make(map[int]int)
+	)
+	for i := range lastConstraint {
+		lastConstraint[i] = -1
+	}
+
+	use := func(wire uint32, cID int) {
+		if int(wire) >= nbWires || lastConstraint[wire] == cID {
+			// constant, or already counted in this constraint
+			return
+		}
+		nbConstraints[wire]++
+		lastConstraint[wire] = cID
+	}
+
+	var (
+		r1c  R1C
+		sr1c SparseR1C
+		hm   HintMapping
+	)
+	for iID, pi := range cs.Instructions {
+		inst := pi.Unpack(cs)
+		cID := int(pi.ConstraintOffset)
+		switch blueprint := cs.Blueprints[pi.BlueprintID].(type) {
+		case BlueprintR1C:
+			blueprint.DecompressR1C(&r1c, inst)
+			for _, l := range []LinearExpression{r1c.L, r1c.R, r1c.O} {
+				for _, t := range l {
+					if t.CoeffID() != CoeffIdZero {
+						use(t.VID, cID)
+					}
+				}
+			}
+		case BlueprintSparseR1C:
+			blueprint.DecompressSparseR1C(&sr1c, inst)
+			if sr1c.QL != CoeffIdZero || sr1c.QM != CoeffIdZero {
+				use(sr1c.XA, cID)
+			}
+			if sr1c.QR != CoeffIdZero || sr1c.QM != CoeffIdZero {
+				use(sr1c.XB, cID)
+			}
+			if sr1c.QO != CoeffIdZero {
+				use(sr1c.XC, cID)
+			}
+		case BlueprintHint:
+			blueprint.DecompressHint(&hm, inst)
+			for w := hm.OutputRange.Start; w < hm.OutputRange.End; w++ {
+				status[w] = free
+				producer[int(w)] = iID
+			}
+		default:
+			// the wires of other blueprints are found through the instruction tree
+			walker := wireWalker{nbWires: nbWires}
+			blueprint.UpdateInstructionTree(inst, &walker)
+			nbOutputs := blueprint.NbOutputs(inst)
+			if blueprint.NbConstraints() == 0 {
+				// like a hint: the inputs are not constrained, nor the outputs
+				for i := 0; i < nbOutputs; i++ {
+					w := int(inst.WireOffset) + i
+					status[w] = free
+					producer[w] = iID
+				}
+				continue
+			}
+			// we don't know how the wires are constrained; we consider that all
+			// of them are
+			for _, w := range walker.wires {
+				use(w, cID)
+			}
+			for i := 0; i < nbOutputs; i++ {
+				use(inst.WireOffset+uint32(i), cID)
+			}
+		}
+	}
+
+	// committed wires are bound by the commitment
+	if commitments, ok := cs.CommitmentInfo.(Groth16Commitments); ok {
+		for i := range commitments {
+			for _, w := range commitments[i].PublicAndCommitmentCommitted {
+				use(uint32(w), -2-i)
+			}
+			for _, w := range commitments[i].PrivateCommitted {
+				use(uint32(w), -2-i)
+			}
+			use(uint32(commitments[i].CommitmentIndex), -2-i)
+		}
+	}
+
+	// free wires appearing in a single constraint, per constraint
+	single := make(map[int][]int)
+	for w := nbInputs; w < nbWires; w++ {
+		if status[w] == free && nbConstraints[w] == 1 {
+			single[lastConstraint[w]] = append(single[lastConstraint[w]], w)
+		}
+	}
+
+	var res []UnconstrainedWire
+	firstInput := 0
+	if cs.Type == SystemR1CS {
+		// the first public wire is the constant 1
+		firstInput = 1
+	}
+	for w := firstInput; w < nbInputs; w++ {
+		if nbConstraints[w] == 0 {
+			res = append(res, UnconstrainedWire{Kind: UnconstrainedInput, Wire: w, Name: cs.VariableToString(w), Constraint: -1})
+		}
+	}
+	for w := nbInputs; w < nbWires; w++ {
+		if status[w] != free {
+			continue
+		}
+		uw := UnconstrainedWire{Wire: w, Name: cs.VariableToString(w), Constraint: -1}
+		switch {
+		case nbConstraints[w] == 0:
+			uw.Kind = UnconstrainedOutput
+		case nbConstraints[w] == 1 && len(single[lastConstraint[w]]) > 1:
+			uw.Kind = UnderconstrainedOutput
+			uw.Constraint = lastConstraint[w]
+		default:
+			continue
+		}
+		uw.Hint, uw.Stack = cs.producerInfo(producer[w], uw.Constraint)
+		res = append(res, uw)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Wire < res[j].Wire })
+	return res
+}
+
+// producerInfo returns the name of the hint computed by the instruction (if it is
+// a hint), and the stack trace of the constraint cID if it has debug info, or
+// else of the instruction.
+func (cs *System) producerInfo(iID, cID int) (hint, stack string) {
+	pi := cs.Instructions[iID]
+	if b, ok := cs.Blueprints[pi.BlueprintID].(BlueprintHint); ok {
+		var hm HintMapping
+		b.DecompressHint(&hm, pi.Unpack(cs))
+		if name, ok := cs.MHintsDependencies[hm.HintID]; ok {
+			hint = name
+		} else {
+			hint = strconv.Itoa(int(hm.HintID))
+		}
+	} else {
+		hint = fmt.Sprintf("%T", cs.Blueprints[pi.BlueprintID])
+	}
+
+	if dID, ok := cs.MDebug[cID]; ok && cID >= 0 {
+		return hint, cs.stackString(cs.DebugInfo[dID].Stack)
+	}
+	if dID, ok := cs.MHintsDebug[iID]; ok {
+		return hint, cs.stackString(cs.DebugInfo[dID].Stack)
+	}
+	return hint, ""
+}
+
+// stackString formats a stack trace collected with SymbolTable.CollectStack.
+func (cs *System) stackString(stack []int) string {
+	var sbb strings.Builder
+	for _, lID := range stack {
+		location := cs.SymbolTable.Locations[lID]
+		function := cs.SymbolTable.Functions[location.FunctionID]
+
+		sbb.WriteString(function.Name)
+		sbb.WriteByte('\n')
+		sbb.WriteByte('\t')
+		sbb.WriteString(function.Filename)
+		sbb.WriteByte(':')
+		sbb.WriteString(strconv.Itoa(int(location.Line)))
+		sbb.WriteByte('\n')
+	}
+	return strings.TrimSuffix(sbb.String(), "\n")
+}
+
+// wireWalker is an InstructionTree recording the wires an instruction refers to.
+type wireWalker struct {
+	nbWires int
+	wires   []uint32
+}
+
+func (w *wireWalker) InsertWire(wire uint32, level Level) {}
+
+func (w *wireWalker) HasWire(wire uint32) bool {
+	if int(wire) < w.nbWires {
+		w.wires = append(w.wires, wire)
+	}
+	return true
+}
+
+func (w *wireWalker) GetWireLevel(wire uint32) Level {
+	return 0
+}
@@ -0,0 +1,116 @@
+package constraint_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/airchains-network/gnark/debug"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/r1cs"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+func splitHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	outputs[0].Set(inputs[0])
+	for i := 1; i < len(outputs); i++ {
+		outputs[i].SetUint64(0)
+	}
+	return nil
+}
+
+type unconstrainedCircuit struct {
+	X, Y frontend.Variable
+	kind int
+}
+
+func (c *unconstrainedCircuit) Define(api frontend.API) error {
+	switch c.kind {
+	case 0:
+		// Y is not used
+		api.AssertIsEqual(api.Mul(c.X, c.X), 4)
+	case 1:
+		// the second output of the hint is not used
+		res, err := api.Compiler().NewHint(splitHint, 2, c.X)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(api.Mul(res[0], c.Y), c.X)
+	case 2:
+		// the outputs of the hint are only used in a linear combination
+		res, err := api.Compiler().NewHint(splitHint, 2, c.X)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(api.Add(res[0], res[1]), c.X)
+		api.AssertIsEqual(c.Y, c.Y)
+	default:
+		// the output of the hint is determined by its constraint
+		res, err := api.Compiler().NewHint(splitHint, 1, c.X)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(api.Mul(res[0], c.Y), c.X)
+	}
+	return nil
+}
+
+func TestFindUnconstrainedWires(t *testing.T) {
+	for _, builder := range []struct {
+		name string
+		new  frontend.NewBuilder
+	}{{"r1cs", r1cs.NewBuilder}, {"scs", scs.NewBuilder}} {
+		t.Run(builder.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			compile := func(kind int, opts ...frontend.CompileOption) (constraint.ConstraintSystem, error) {
+				return frontend.Compile(ecc.BN254.ScalarField(), builder.new, &unconstrainedCircuit{kind: kind}, opts...)
+			}
+
+			// unconstrained inputs are not checked by default
+			ccs, err := compile(0)
+			assert.NoError(err)
+			assert.Len(constraint.FindUnconstrainedWires(ccs), 1)
+
+			// unconstrained inputs fail the compilation when checked
+			_, err = compile(0, frontend.WithUnconstrainedInputsCheck())
+			var uErr *constraint.UnconstrainedWiresError
+			assert.True(errors.As(err, &uErr), "expected an UnconstrainedWiresError, got %v", err)
+			assert.Len(uErr.Wires, 1)
+			assert.Equal(constraint.UnconstrainedInput, uErr.Wires[0].Kind)
+			assert.Equal("Y", uErr.Wires[0].Name)
+
+			ccs, err = compile(0, frontend.WithUnconstrainedInputsCheck(), frontend.IgnoreUnconstrainedInputs())
+			assert.NoError(err)
+			assert.Len(constraint.FindUnconstrainedWires(ccs), 1)
+
+			// unconstrained hint outputs don't
+			ccs, err = compile(1, frontend.WithUnconstrainedInputsCheck())
+			assert.NoError(err)
+			wires := constraint.FindUnconstrainedWires(ccs)
+			assert.Len(wires, 1)
+			assert.Equal(constraint.UnconstrainedOutput, wires[0].Kind)
+			assert.Contains(wires[0].Hint, "splitHint")
+			if debug.Debug {
+				assert.Contains(wires[0].Stack, "unconstrained_test.go")
+			}
+			assert.Error(ccs.CheckUnconstrainedWires())
+
+			ccs, err = compile(2)
+			assert.NoError(err)
+			wires = constraint.FindUnconstrainedWires(ccs)
+			assert.Len(wires, 2)
+			for _, w := range wires {
+				assert.Equal(constraint.UnderconstrainedOutput, w.Kind)
+				assert.Equal(wires[0].Constraint, w.Constraint)
+			}
+
+			ccs, err = compile(3)
+			assert.NoError(err)
+			assert.NoError(ccs.CheckUnconstrainedWires())
+		})
+	}
+}
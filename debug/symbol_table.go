@@ -111,3 +111,21 @@ func (st *SymbolTable) locationID(frame *runtime.Frame) int {
 
 	return lID
 }
+
+// Frame is a resolved stack frame.
+type Frame struct {
+	Function string // fully qualified function name
+	File     string
+	Line     int64
+}
+
+// Frames resolves the location IDs of a stack collected with CollectStack.
+func (st *SymbolTable) Frames(stack []int) []Frame {
+	frames := make([]Frame, len(stack))
+	for i, lID := range stack {
+		location := st.Locations[lID]
+		function := st.Functions[location.FunctionID]
+		frames[i] = Frame{Function: function.SystemName, File: function.Filename, Line: location.Line}
+	}
+	return frames
+}
@@ -153,6 +153,7 @@ func defaultCompileConfig() CompileConfig {
 type CompileConfig struct {
 	Capacity                  int
 	IgnoreUnconstrainedInputs bool
+	CheckUnconstrainedInputs  bool
 	CompressThreshold         int
 }
 
@@ -167,8 +168,9 @@ func WithCapacity(capacity int) CompileOption {
 }
 
 // IgnoreUnconstrainedInputs is a compile option which allow compiling input
-// circuits where not all inputs are not constrained. If not set, then the
-// compiler returns an error if there exists an unconstrained input.
+// circuits where not all inputs are not constrained. If not set and the inputs
+// are checked (see WithUnconstrainedInputsCheck), then the compiler returns an
+// error if there exists an unconstrained input.
 //
 // This option is useful for debugging circuits, but should not be used in
 // production settings as it means that there is a potential error in the
@@ -180,6 +182,18 @@ func IgnoreUnconstrainedInputs() CompileOption {
 	}
 }
 
+// WithUnconstrainedInputsCheck is a compile option which enables the check that
+// all the inputs of the circuit appear in at least one constraint (see
+// constraint.FindUnconstrainedWires). If an input is unconstrained, the
+// compiler returns a *constraint.UnconstrainedWiresError, unless
+// IgnoreUnconstrainedInputs is set.
+func WithUnconstrainedInputsCheck() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.CheckUnconstrainedInputs = true
+		return nil
+	}
+}
+
 // WithCompressThreshold is a compile option which enforces automatic variable
 // compression if the length of the linear expression in the variable exceeds
 // given threshold.
@@ -23,6 +23,7 @@ import (
 	"sort"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
 	"github.com/airchains-network/gnark/constraint"
 	"github.com/airchains-network/gnark/debug"
 	"github.com/airchains-network/gnark/frontend"
@@ -43,6 +44,7 @@ import (
 	bw6633r1cs "github.com/airchains-network/gnark/constraint/bw6-633"
 	bw6761r1cs "github.com/airchains-network/gnark/constraint/bw6-761"
 	"github.com/airchains-network/gnark/constraint/solver"
+	goldilocksr1cs "github.com/airchains-network/gnark/constraint/goldilocks"
 	tinyfieldr1cs "github.com/airchains-network/gnark/constraint/tinyfield"
 )
 
@@ -114,6 +116,10 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 			builder.cs = tinyfieldr1cs.NewR1CS(config.Capacity)
 			break
 		}
+		if field.Cmp(goldilocks.Modulus()) == 0 {
+			builder.cs = goldilocksr1cs.NewR1CS(config.Capacity)
+			break
+		}
 		panic("not implemented")
 	}
 
@@ -278,11 +284,20 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		Int("nbConstraints", builder.cs.GetNbConstraints()).
 		Msg("building constraint builder")
 
-	// ensure all inputs and hints are constrained
-	if err := builder.cs.CheckUnconstrainedWires(); err != nil {
-		log.Warn().Msg("circuit has unconstrained inputs")
-		if !builder.config.IgnoreUnconstrainedInputs {
-			return nil, err
+	// if requested, ensure all inputs are constrained; the hint outputs are
+	// checked on demand with CheckUnconstrainedWires
+	if builder.config.CheckUnconstrainedInputs {
+		var unconstrained []constraint.UnconstrainedWire
+		for _, w := range constraint.FindUnconstrainedWires(builder.cs) {
+			if w.Kind == constraint.UnconstrainedInput {
+				unconstrained = append(unconstrained, w)
+			}
+		}
+		if len(unconstrained) != 0 {
+			log.Warn().Msg("circuit has unconstrained inputs")
+			if !builder.config.IgnoreUnconstrainedInputs {
+				return nil, &constraint.UnconstrainedWiresError{Wires: unconstrained}
+			}
 		}
 	}
 
@@ -22,6 +22,7 @@ import (
 	"sort"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
 	"github.com/airchains-network/gnark/constraint"
 	"github.com/airchains-network/gnark/debug"
 	"github.com/airchains-network/gnark/frontend"
@@ -41,6 +42,7 @@ import (
 	bw6633r1cs "github.com/airchains-network/gnark/constraint/bw6-633"
 	bw6761r1cs "github.com/airchains-network/gnark/constraint/bw6-761"
 	"github.com/airchains-network/gnark/constraint/solver"
+	goldilocksr1cs "github.com/airchains-network/gnark/constraint/goldilocks"
 	tinyfieldr1cs "github.com/airchains-network/gnark/constraint/tinyfield"
 )
 
@@ -111,6 +113,10 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 			b.cs = tinyfieldr1cs.NewSparseR1CS(config.Capacity)
 			break
 		}
+		if field.Cmp(goldilocks.Modulus()) == 0 {
+			b.cs = goldilocksr1cs.NewSparseR1CS(config.Capacity)
+			break
+		}
 		panic("not implemented")
 	}
 
@@ -289,12 +295,20 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		Int("nbConstraints", builder.cs.GetNbConstraints()).
 		Msg("building constraint builder")
 
-	// ensure all inputs and hints are constrained
-	err := builder.cs.CheckUnconstrainedWires()
-	if err != nil {
-		log.Warn().Msg("circuit has unconstrained inputs")
-		if !builder.config.IgnoreUnconstrainedInputs {
-			return nil, err
+	// if requested, ensure all inputs are constrained; the hint outputs are
+	// checked on demand with CheckUnconstrainedWires
+	if builder.config.CheckUnconstrainedInputs {
+		var unconstrained []constraint.UnconstrainedWire
+		for _, w := range constraint.FindUnconstrainedWires(builder.cs) {
+			if w.Kind == constraint.UnconstrainedInput {
+				unconstrained = append(unconstrained, w)
+			}
+		}
+		if len(unconstrained) != 0 {
+			log.Warn().Msg("circuit has unconstrained inputs")
+			if !builder.config.IgnoreUnconstrainedInputs {
+				return nil, &constraint.UnconstrainedWiresError{Wires: unconstrained}
+			}
 		}
 	}
 
@@ -68,6 +68,12 @@ func main() {
 		CurveID:   "UNKNOWN",
 		noBackend: true,
 	}
+	goldilocks := templateData{
+		CSPath:    "../../../constraint/goldilocks",
+		Curve:     "goldilocks",
+		CurveID:   "UNKNOWN",
+		noBackend: true,
+	}
 
 	// autogenerate tinyfield
 	tinyfieldConf, err := config.NewFieldConfig("tinyfield", "Element", "0x2f", false)
@@ -87,6 +93,7 @@ func main() {
 		bls24_317,
 		bw6_633,
 		tiny_field,
+		goldilocks,
 	}
 
 	const importCurve = "../imports.go.tmpl"
@@ -101,22 +108,13 @@ func main() {
 			defer wg.Done()
 
 			var (
-				groth16Dir         = strings.Replace(d.RootPath, "{?}", "groth16", 1)
-				groth16MpcSetupDir = filepath.Join(groth16Dir, "mpcsetup")
-				plonkDir           = strings.Replace(d.RootPath, "{?}", "plonk", 1)
-				plonkFriDir        = strings.Replace(d.RootPath, "{?}", "plonkfri", 1)
+				groth16Dir          = strings.Replace(d.RootPath, "{?}", "groth16", 1)
+				groth16MpcSetupDir  = filepath.Join(groth16Dir, "mpcsetup")
+				groth16SnarkPackDir = filepath.Join(groth16Dir, "snarkpack")
+				plonkDir            = strings.Replace(d.RootPath, "{?}", "plonk", 1)
+				plonkFriDir         = strings.Replace(d.RootPath, "{?}", "plonkfri", 1)
 			)
 
-			if err := os.MkdirAll(groth16Dir, 0700); err != nil {
-				panic(err)
-			}
-			if err := os.MkdirAll(plonkDir, 0700); err != nil {
-				panic(err)
-			}
-			if err := os.MkdirAll(plonkFriDir, 0700); err != nil {
-				panic(err)
-			}
-
 			csDir := d.CSPath
 
 			// constraint systems
@@ -130,7 +128,7 @@ func main() {
 			}
 
 			// gkr backend
-			if d.Curve != "tinyfield" {
+			if d.Curve != "tinyfield" && d.Curve != "goldilocks" {
 				entries = []bavard.Entry{{File: filepath.Join(csDir, "gkr.go"), Templates: []string{"gkr.go.tmpl", importCurve}}}
 				if err := bgen.Generate(d, "cs", "./template/representations/", entries...); err != nil {
 					panic(err)
@@ -156,12 +154,17 @@ func main() {
 			if err := os.MkdirAll(plonkDir, 0700); err != nil {
 				panic(err)
 			}
+			if err := os.MkdirAll(plonkFriDir, 0700); err != nil {
+				panic(err)
+			}
 
 			entries = []bavard.Entry{
 				{File: filepath.Join(groth16Dir, "verify.go"), Templates: []string{"groth16/groth16.verify.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "prove.go"), Templates: []string{"groth16/groth16.prove.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "prove_outofcore.go"), Templates: []string{"groth16/groth16.prove.outofcore.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "setup.go"), Templates: []string{"groth16/groth16.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal.go"), Templates: []string{"groth16/groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "marshal_indexed.go"), Templates: []string{"groth16/groth16.marshal.indexed.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal_test.go"), Templates: []string{"groth16/tests/groth16.marshal.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "groth16", "./template/zkpschemes/", entries...); err != nil {
@@ -184,6 +187,7 @@ func main() {
 				{File: filepath.Join(groth16MpcSetupDir, "phase2.go"), Templates: []string{"groth16/mpcsetup/phase2.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16MpcSetupDir, "setup.go"), Templates: []string{"groth16/mpcsetup/setup.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16MpcSetupDir, "setup_test.go"), Templates: []string{"groth16/mpcsetup/setup_test.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16MpcSetupDir, "transcript.go"), Templates: []string{"groth16/mpcsetup/transcript.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16MpcSetupDir, "utils.go"), Templates: []string{"groth16/mpcsetup/utils.go.tmpl", importCurve}},
 			}
 
@@ -191,6 +195,21 @@ func main() {
 				panic(err) // TODO handle
 			}
 
+			// groth16 snarkpack (proof aggregation)
+			if d.Curve == "BN254" || d.Curve == "BLS12-381" {
+				entries = []bavard.Entry{
+					{File: filepath.Join(groth16SnarkPackDir, "aggregate.go"), Templates: []string{"groth16/snarkpack/aggregate.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16SnarkPackDir, "marshal.go"), Templates: []string{"groth16/snarkpack/marshal.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16SnarkPackDir, "snarkpack_test.go"), Templates: []string{"groth16/snarkpack/snarkpack_test.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16SnarkPackDir, "srs.go"), Templates: []string{"groth16/snarkpack/srs.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16SnarkPackDir, "utils.go"), Templates: []string{"groth16/snarkpack/utils.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16SnarkPackDir, "verify.go"), Templates: []string{"groth16/snarkpack/verify.go.tmpl", importCurve}},
+				}
+				if err := bgen.Generate(d, "snarkpack", "./template/zkpschemes/", entries...); err != nil {
+					panic(err)
+				}
+			}
+
 			// plonk
 			entries = []bavard.Entry{
 				{File: filepath.Join(plonkDir, "verify.go"), Templates: []string{"plonk/plonk.verify.go.tmpl", importCurve}},
@@ -208,6 +227,9 @@ func main() {
 				{File: filepath.Join(plonkFriDir, "verify.go"), Templates: []string{"plonkfri/plonk.verify.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkFriDir, "prove.go"), Templates: []string{"plonkfri/plonk.prove.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkFriDir, "setup.go"), Templates: []string{"plonkfri/plonk.setup.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFriDir, "iopp.go"), Templates: []string{"plonkfri/plonk.iopp.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFriDir, "marshal.go"), Templates: []string{"plonkfri/plonk.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFriDir, "marshal_test.go"), Templates: []string{"plonkfri/tests/marshal.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "plonkfri", "./template/zkpschemes/", entries...); err != nil {
 				panic(err)
@@ -217,9 +239,18 @@ func main() {
 
 	}
 
+	// goldilocks isn't the scalar field of a curve, so it has no hash to field
+	// in gnark-crypto/ecc and no branch in constant.HashedBytes
+	curveDatas := make([]templateData, 0, len(datas))
+	for _, d := range datas {
+		if d.Curve != "goldilocks" {
+			curveDatas = append(curveDatas, d)
+		}
+	}
+
 	wg.Add(1)
 	go func() {
-		if err = bgen.Generate(datas, "constant", "./template/representations/",
+		if err = bgen.Generate(curveDatas, "constant", "./template/representations/",
 			bavard.Entry{File: filepath.Join("../../../constant", "constant.go"), Templates: []string{"constant.go.tmpl"}}); err != nil {
 			panic(err)
 		}
@@ -7,9 +7,12 @@ import (
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/algopts"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_emulated"
 	"github.com/airchains-network/gnark/std/algebra/native/sw_bls12377"
 	"github.com/airchains-network/gnark/std/algebra/native/sw_bls24315"
 	"github.com/airchains-network/gnark/std/hash/mimc"
+	"github.com/airchains-network/gnark/std/hash/poseidon2"
 	"github.com/airchains-network/gnark/std/math/bits"
 	"github.com/airchains-network/gnark/std/math/emulated"
 )
@@ -79,6 +82,11 @@ func initSnippets() {
 		mimc.Write(newVariable())
 		_ = mimc.Sum()
 	})
+	registerSnippet("hash/poseidon2", func(api frontend.API, newVariable func() frontend.Variable) {
+		poseidon2, _ := poseidon2.NewMerkleDamgardHasher(api)
+		poseidon2.Write(newVariable())
+		_ = poseidon2.Sum()
+	}, ecc.BN254, ecc.BLS12_377, ecc.BLS12_381)
 	registerSnippet("math/emulated/secp256k1_64", func(api frontend.API, newVariable func() frontend.Variable) {
 		secp256k1, _ := emulated.NewField[emulated.Secp256k1Fp](api)
 
@@ -104,6 +112,13 @@ func initSnippets() {
 		secp256k1.AssertIsEqual(res, newElement())
 	})
 
+	registerSnippet("sw_emulated/secp256k1/MultiScalarMul_8", func(api frontend.API, newVariable func() frontend.Variable) {
+		msmSnippet(api, newVariable, 8)
+	}, ecc.BN254)
+	registerSnippet("sw_emulated/secp256k1/MultiScalarMul_8/windowed", func(api frontend.API, newVariable func() frontend.Variable) {
+		msmSnippet(api, newVariable, 8, algopts.WithWindowedMultiScalarMul(0))
+	}, ecc.BN254)
+
 	registerSnippet("pairing_bls12377", func(api frontend.API, newVariable func() frontend.Variable) {
 
 		var dummyG1 sw_bls12377.G1Affine
@@ -142,6 +157,27 @@ func initSnippets() {
 
 }
 
+func msmSnippet(api frontend.API, newVariable func() frontend.Variable, nbPoints int, opts ...algopts.AlgebraOption) {
+	curve, _ := sw_emulated.New[emulated.Secp256k1Fp, emulated.Secp256k1Fr](api, sw_emulated.GetSecp256k1Params())
+	newLimbs := func(nbLimbs uint) []frontend.Variable {
+		limbs := make([]frontend.Variable, nbLimbs)
+		for i := range limbs {
+			limbs[i] = newVariable()
+		}
+		return limbs
+	}
+	points := make([]*sw_emulated.AffinePoint[emulated.Secp256k1Fp], nbPoints)
+	scalars := make([]*emulated.Element[emulated.Secp256k1Fr], nbPoints)
+	for i := range points {
+		points[i] = &sw_emulated.AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.Element[emulated.Secp256k1Fp]{Limbs: newLimbs(emulated.Secp256k1Fp{}.NbLimbs())},
+			Y: emulated.Element[emulated.Secp256k1Fp]{Limbs: newLimbs(emulated.Secp256k1Fp{}.NbLimbs())},
+		}
+		scalars[i] = &emulated.Element[emulated.Secp256k1Fr]{Limbs: newLimbs(emulated.Secp256k1Fr{}.NbLimbs())}
+	}
+	_, _ = curve.MultiScalarMul(points, scalars, opts...)
+}
+
 type snippetCircuit struct {
 	V      [1024]frontend.Variable
 	s      snippet
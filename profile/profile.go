@@ -13,6 +13,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/airchains-network/gnark/debug"
 	"github.com/airchains-network/gnark/logger"
 	"github.com/airchains-network/gnark/profile/internal/report"
 	"github.com/google/pprof/profile"
@@ -141,6 +142,26 @@ func (p *Profile) NbConstraints() int {
 	return len(p.pprof.Sample)
 }
 
+// Stacks returns the stack traces of the samples (constraints) collected by the
+// profile session, in the order they were added to the constraint system,
+// innermost frame first.
+func (p *Profile) Stacks() [][]debug.Frame {
+	stacks := make([][]debug.Frame, len(p.pprof.Sample))
+	for i, sample := range p.pprof.Sample {
+		stacks[i] = make([]debug.Frame, 0, len(sample.Location))
+		for _, l := range sample.Location {
+			for _, line := range l.Line {
+				stacks[i] = append(stacks[i], debug.Frame{
+					Function: line.Function.SystemName,
+					File:     line.Function.Filename,
+					Line:     line.Line,
+				})
+			}
+		}
+	}
+	return stacks
+}
+
 // Top return a similar output than pprof top command
 func (p *Profile) Top() string {
 	r := report.NewDefault(&p.pprof, report.Options{
@@ -0,0 +1,168 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/hash"
+)
+
+// MultiMerkleProof is a proof of membership of several leaves in a Merkle tree
+// of fixed depth. Compared to several individual proofs, the common nodes of
+// the paths are computed only once and the proof only contains the nodes which
+// cannot be computed from the leaves.
+//
+// The indices of the leaves are fixed at circuit compile time as they define
+// which nodes are shared. Use [NewMultiMerkleProof] to initialize the proof
+// for the circuit definition and [SparseTree.ProveMulti] for computing the
+// witness.
+type MultiMerkleProof struct {
+	// RootHash is the root of the Merkle tree.
+	RootHash frontend.Variable
+
+	// Nodes are the helper nodes for computing the root, ordered by level
+	// starting from the leaf level and then by the index in the level.
+	Nodes []frontend.Variable
+
+	depth   int
+	indices []uint64
+}
+
+// NewMultiMerkleProof returns a placeholder proof for the leaves at indices in
+// a tree of the given depth. It panics if the indices are not distinct or do
+// not fit in the tree.
+func NewMultiMerkleProof(depth int, indices []uint64) MultiMerkleProof {
+	helpers, err := multiProofHelpers(depth, indices)
+	if err != nil {
+		panic(err)
+	}
+	return MultiMerkleProof{
+		Nodes:   make([]frontend.Variable, len(helpers)),
+		depth:   depth,
+		indices: append([]uint64{}, indices...),
+	}
+}
+
+// VerifyProof asserts that leaves are the values of the leaves at the indices
+// given when initializing the proof using [NewMultiMerkleProof].
+func (mp *MultiMerkleProof) VerifyProof(api frontend.API, h hash.FieldHasher, leaves []frontend.Variable) {
+	if len(leaves) != len(mp.indices) {
+		panic(fmt.Sprintf("expected %d leaves, got %d", len(mp.indices), len(leaves)))
+	}
+	leafHashes := make([]frontend.Variable, len(leaves))
+	for i := range leaves {
+		leafHashes[i] = leafSum(api, h, leaves[i])
+	}
+	root, err := multiProofRoot(mp.depth, mp.indices, leafHashes, mp.Nodes, func(a, b frontend.Variable) frontend.Variable {
+		return nodeSum(api, h, a, b)
+	})
+	if err != nil {
+		panic(err)
+	}
+	api.AssertIsEqual(root, mp.RootHash)
+}
+
+// nodePosition is the position of a node in the tree. The leaves are at level
+// zero.
+type nodePosition struct {
+	level int
+	index uint64
+}
+
+// sortedIndices returns the sorted copy of the indices and checks that they
+// are distinct and fit in the tree of the given depth.
+func sortedIndices(depth int, indices []uint64) ([]uint64, error) {
+	if depth < 0 || depth > 64 {
+		return nil, fmt.Errorf("invalid depth %d", depth)
+	}
+	if len(indices) == 0 {
+		return nil, errors.New("no indices")
+	}
+	sorted := append([]uint64{}, indices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i := range sorted {
+		if depth < 64 && sorted[i]>>depth != 0 {
+			return nil, fmt.Errorf("index %d does not fit in tree of depth %d", sorted[i], depth)
+		}
+		if i > 0 && sorted[i] == sorted[i-1] {
+			return nil, fmt.Errorf("duplicate index %d", sorted[i])
+		}
+	}
+	return sorted, nil
+}
+
+// multiProofHelpers returns the positions of the nodes which are required for
+// computing the root from the leaves at indices. The positions are ordered by
+// level and then by the index in the level.
+func multiProofHelpers(depth int, indices []uint64) ([]nodePosition, error) {
+	known, err := sortedIndices(depth, indices)
+	if err != nil {
+		return nil, err
+	}
+	var helpers []nodePosition
+	for level := 0; level < depth; level++ {
+		var parents []uint64
+		for i := 0; i < len(known); i++ {
+			if known[i]&1 == 0 && i+1 < len(known) && known[i+1] == known[i]+1 {
+				// both children are known
+				i++
+			} else {
+				helpers = append(helpers, nodePosition{level: level, index: known[i] ^ 1})
+			}
+			parents = append(parents, known[i]>>1)
+		}
+		known = parents
+	}
+	return helpers, nil
+}
+
+// multiProofRoot computes the root of the tree from the leaf hashes at indices
+// and the helper nodes ordered as in [multiProofHelpers]. The function is
+// generic to share the traversal between the circuit and native
+// implementations.
+func multiProofRoot[T any](depth int, indices []uint64, leafHashes []T, helpers []T, node func(a, b T) T) (T, error) {
+	var root T
+	if len(leafHashes) != len(indices) {
+		return root, errors.New("number of leaves and indices mismatch")
+	}
+	known, err := sortedIndices(depth, indices)
+	if err != nil {
+		return root, err
+	}
+	current := make(map[uint64]T, len(indices))
+	for i := range indices {
+		current[indices[i]] = leafHashes[i]
+	}
+	hi := 0
+	for level := 0; level < depth; level++ {
+		var parents []uint64
+		next := make(map[uint64]T, len(known))
+		for i := 0; i < len(known); i++ {
+			var left, right T
+			idx := known[i]
+			if idx&1 == 0 && i+1 < len(known) && known[i+1] == idx+1 {
+				left, right = current[idx], current[idx+1]
+				i++
+			} else {
+				if hi >= len(helpers) {
+					return root, errors.New("not enough helper nodes")
+				}
+				if idx&1 == 0 {
+					left, right = current[idx], helpers[hi]
+				} else {
+					left, right = helpers[hi], current[idx]
+				}
+				hi++
+			}
+			next[idx>>1] = node(left, right)
+			parents = append(parents, idx>>1)
+		}
+		known, current = parents, next
+	}
+	if hi != len(helpers) {
+		return root, errors.New("too many helper nodes")
+	}
+	return current[0], nil
+}
@@ -0,0 +1,258 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/airchains-network/gnark/frontend"
+)
+
+// nativeLeafSum is the out-of-circuit counterpart of leafSum. It returns the
+// error of the hash function, e.g. if data isn't a canonical encoding.
+func nativeLeafSum(h hash.Hash, data []byte) ([]byte, error) {
+	h.Reset()
+	if _, err := h.Write(data); err != nil {
+		return nil, fmt.Errorf("hash leaf: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// nativeNodeSum is the out-of-circuit counterpart of nodeSum. It returns the
+// error of the hash function, e.g. if a or b isn't a canonical encoding.
+func nativeNodeSum(h hash.Hash, a, b []byte) ([]byte, error) {
+	h.Reset()
+	if _, err := h.Write(a); err != nil {
+		return nil, fmt.Errorf("hash node: %w", err)
+	}
+	if _, err := h.Write(b); err != nil {
+		return nil, fmt.Errorf("hash node: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// Tree is an out-of-circuit Merkle tree for constructing the witnesses for
+// [MerkleProof]. It uses the same hashing as the circuit, i.e. the leaves are
+// hashed as the hash of the leaf data and the inner nodes as the hash of the
+// concatenation of the children.
+//
+// The hash function must be the native counterpart of the in-circuit hash
+// function. For the hash functions registered in
+// [github.com/airchains-network/gnark/std/hash] it can be obtained using
+// [github.com/airchains-network/gnark/std/hash.GetNativeHasher]. The leaf data
+// is written into the hash function as is, so it should be the canonical
+// big-endian encoding of a field element.
+type Tree struct {
+	h      hash.Hash
+	leaves [][]byte
+	// levels[0] are the leaf hashes and levels[depth] the root.
+	levels [][][]byte
+}
+
+// NewTree builds a new Merkle tree from the leaves using the hash function h.
+// The number of leaves must be a power of two. If it is not, then the caller
+// should pad the leaves.
+func NewTree(h hash.Hash, leaves [][]byte) (*Tree, error) {
+	if len(leaves) == 0 || len(leaves)&(len(leaves)-1) != 0 {
+		return nil, fmt.Errorf("number of leaves %d is not a power of two", len(leaves))
+	}
+	t := &Tree{
+		h:      h,
+		leaves: make([][]byte, len(leaves)),
+	}
+	level := make([][]byte, len(leaves))
+	var err error
+	for i := range leaves {
+		t.leaves[i] = append([]byte{}, leaves[i]...)
+		if level[i], err = nativeLeafSum(h, leaves[i]); err != nil {
+			return nil, err
+		}
+	}
+	t.levels = append(t.levels, level)
+	for len(level) > 1 {
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			if next[i], err = nativeNodeSum(h, level[2*i], level[2*i+1]); err != nil {
+				return nil, err
+			}
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree.
+func (t *Tree) Depth() int { return len(t.levels) - 1 }
+
+// Root returns the root of the tree.
+func (t *Tree) Root() []byte { return t.levels[len(t.levels)-1][0] }
+
+// Prove returns the proof of membership of the leaf at index.
+func (t *Tree) Prove(index uint64) (*NativeProof, error) {
+	if index >= uint64(len(t.leaves)) {
+		return nil, fmt.Errorf("index %d out of range", index)
+	}
+	p := &NativeProof{
+		RootHash: t.Root(),
+		Path:     make([][]byte, t.Depth()+1),
+		Index:    index,
+	}
+	p.Path[0] = t.leaves[index]
+	idx := index
+	for level := 0; level < t.Depth(); level++ {
+		p.Path[level+1] = t.levels[level][idx^1]
+		idx >>= 1
+	}
+	return p, nil
+}
+
+// NativeProof is an out-of-circuit Merkle proof corresponding to
+// [MerkleProof]. The first element of the path is the leaf data and the rest
+// are the siblings from the leaf level to the root.
+type NativeProof struct {
+	RootHash []byte
+	Path     [][]byte
+	Index    uint64
+}
+
+// Verify verifies the proof using the hash function h.
+func (p *NativeProof) Verify(h hash.Hash) error {
+	if len(p.Path) == 0 {
+		return errors.New("empty path")
+	}
+	if depth := len(p.Path) - 1; depth < 64 && p.Index>>depth != 0 {
+		return fmt.Errorf("index %d does not fit in tree of depth %d", p.Index, depth)
+	}
+	sum, err := nativeLeafSum(h, p.Path[0])
+	if err != nil {
+		return err
+	}
+	for i := 1; i < len(p.Path); i++ {
+		if (p.Index>>(i-1))&1 == 1 {
+			sum, err = nativeNodeSum(h, p.Path[i], sum)
+		} else {
+			sum, err = nativeNodeSum(h, sum, p.Path[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !bytes.Equal(sum, p.RootHash) {
+		return errors.New("root mismatch")
+	}
+	return nil
+}
+
+// Assignment returns the witness assignment of the proof for the circuit and
+// the leaf index to be given as the leaf argument to [MerkleProof.VerifyProof].
+func (p *NativeProof) Assignment() (MerkleProof, frontend.Variable) {
+	mp := MerkleProof{
+		RootHash: p.RootHash,
+		Path:     make([]frontend.Variable, len(p.Path)),
+	}
+	for i := range p.Path {
+		mp.Path[i] = p.Path[i]
+	}
+	return mp, p.Index
+}
+
+// WriteTo writes the binary encoding of the proof into w. The encoding is the
+// index as 8-byte big-endian integer followed by the root and the path
+// elements, each of them prefixed with their length as 4-byte big-endian
+// integer. The path is prefixed with the number of its elements as 4-byte
+// big-endian integer.
+func (p *NativeProof) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	write := func(v any) error {
+		err := binary.Write(w, binary.BigEndian, v)
+		if err == nil {
+			n += int64(binary.Size(v))
+		}
+		return err
+	}
+	writeBytes := func(b []byte) error {
+		if err := write(uint32(len(b))); err != nil {
+			return err
+		}
+		m, err := w.Write(b)
+		n += int64(m)
+		return err
+	}
+	if err := write(p.Index); err != nil {
+		return n, err
+	}
+	if err := writeBytes(p.RootHash); err != nil {
+		return n, err
+	}
+	if err := write(uint32(len(p.Path))); err != nil {
+		return n, err
+	}
+	for i := range p.Path {
+		if err := writeBytes(p.Path[i]); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+const (
+	// MaxNativeProofDepth is the maximal depth of a [NativeProof] decoded by
+	// [NativeProof.ReadFrom].
+	MaxNativeProofDepth = 256
+	// MaxNativeProofNodeSize is the maximal size in bytes of the root and of
+	// the path elements of a [NativeProof] decoded by [NativeProof.ReadFrom].
+	MaxNativeProofNodeSize = 1024
+)
+
+// ReadFrom reads the binary encoding of the proof written by
+// [NativeProof.WriteTo] from r. As the input may be untrusted, it returns an
+// error if the path is longer than [MaxNativeProofDepth]+1 elements or if a
+// node is longer than [MaxNativeProofNodeSize] bytes.
+func (p *NativeProof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	read := func(v any) error {
+		err := binary.Read(r, binary.BigEndian, v)
+		if err == nil {
+			n += int64(binary.Size(v))
+		}
+		return err
+	}
+	readBytes := func() ([]byte, error) {
+		var l uint32
+		if err := read(&l); err != nil {
+			return nil, err
+		}
+		if l > MaxNativeProofNodeSize {
+			return nil, fmt.Errorf("node size %d exceeds %d bytes", l, MaxNativeProofNodeSize)
+		}
+		b := make([]byte, l)
+		m, err := io.ReadFull(r, b)
+		n += int64(m)
+		return b, err
+	}
+	var err error
+	if err = read(&p.Index); err != nil {
+		return n, err
+	}
+	if p.RootHash, err = readBytes(); err != nil {
+		return n, err
+	}
+	var nbPath uint32
+	if err = read(&nbPath); err != nil {
+		return n, err
+	}
+	if nbPath > MaxNativeProofDepth+1 {
+		return n, fmt.Errorf("path length %d exceeds %d", nbPath, MaxNativeProofDepth+1)
+	}
+	p.Path = make([][]byte, nbPath)
+	for i := range p.Path {
+		if p.Path[i], err = readBytes(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
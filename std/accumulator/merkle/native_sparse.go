@@ -0,0 +1,147 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+)
+
+// SparseTree is an out-of-circuit sparse Merkle tree for constructing the
+// witnesses for [SparseMerkleProof] and [MultiMerkleProof].
+//
+// The hash function h must be the native counterpart of the in-circuit hash
+// function, e.g. for [github.com/airchains-network/gnark/std/hash/mimc] over
+// BN254 use [github.com/consensys/gnark-crypto/hash.MIMC_BN254]. The leaf
+// values are written into the hash function as is, so they should be the
+// canonical big-endian encodings of the field elements.
+type SparseTree struct {
+	h     hash.Hash
+	depth int
+	// empty[i] is the hash of an empty subtree at level i.
+	empty [][]byte
+	// nodes stores the hashes of non-empty subtrees, nodes[0] are the leaf
+	// hashes and nodes[depth] the root.
+	nodes []map[uint64][]byte
+}
+
+// NewSparseTree returns a new empty sparse Merkle tree of the given depth,
+// i.e. with 2^depth leaves.
+func NewSparseTree(h hash.Hash, depth int) (*SparseTree, error) {
+	if depth < 0 || depth > 64 {
+		return nil, fmt.Errorf("invalid depth %d", depth)
+	}
+	t := &SparseTree{
+		h:     h,
+		depth: depth,
+		empty: make([][]byte, depth+1),
+		nodes: make([]map[uint64][]byte, depth+1),
+	}
+	var err error
+	if t.empty[0], err = nativeLeafSum(h, t.emptyValue()); err != nil {
+		return nil, err
+	}
+	for i := 1; i <= depth; i++ {
+		if t.empty[i], err = nativeNodeSum(h, t.empty[i-1], t.empty[i-1]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range t.nodes {
+		t.nodes[i] = make(map[uint64][]byte)
+	}
+	return t, nil
+}
+
+// emptyValue returns the encoding of zero value.
+func (t *SparseTree) emptyValue() []byte {
+	return make([]byte, t.h.BlockSize())
+}
+
+// Depth returns the depth of the tree.
+func (t *SparseTree) Depth() int { return t.depth }
+
+// Root returns the current root of the tree.
+func (t *SparseTree) Root() []byte {
+	return t.node(t.depth, 0)
+}
+
+func (t *SparseTree) checkKey(key uint64) error {
+	if t.depth < 64 && key>>t.depth != 0 {
+		return fmt.Errorf("key %d does not fit in tree of depth %d", key, t.depth)
+	}
+	return nil
+}
+
+func (t *SparseTree) node(level int, index uint64) []byte {
+	if n, ok := t.nodes[level][index]; ok {
+		return n
+	}
+	return t.empty[level]
+}
+
+// Set sets the value of the leaf at key and updates the path to the root.
+// Setting the value to zero removes the leaf. If hashing fails, e.g. because
+// the value isn't a canonical encoding, the tree is left unchanged.
+func (t *SparseTree) Set(key uint64, value []byte) error {
+	if err := t.checkKey(key); err != nil {
+		return err
+	}
+	// compute the whole path before updating the tree
+	path := make([][]byte, t.depth+1)
+	var err error
+	if path[0], err = nativeLeafSum(t.h, value); err != nil {
+		return err
+	}
+	idx := key
+	for level := 0; level < t.depth; level++ {
+		if idx&1 == 0 {
+			path[level+1], err = nativeNodeSum(t.h, path[level], t.node(level, idx+1))
+		} else {
+			path[level+1], err = nativeNodeSum(t.h, t.node(level, idx-1), path[level])
+		}
+		if err != nil {
+			return err
+		}
+		idx >>= 1
+	}
+	idx = key
+	for level, node := range path {
+		if bytes.Equal(node, t.empty[level]) {
+			delete(t.nodes[level], idx)
+		} else {
+			t.nodes[level][idx] = node
+		}
+		idx >>= 1
+	}
+	return nil
+}
+
+// Prove returns the siblings of the path from the leaf at key to the root. It
+// can be used for both [SparseMerkleProof.VerifyMembership] and
+// [SparseMerkleProof.VerifyNonMembership], and for
+// [SparseMerkleProof.UpdateLeaf] before setting the new value.
+func (t *SparseTree) Prove(key uint64) ([][]byte, error) {
+	if err := t.checkKey(key); err != nil {
+		return nil, err
+	}
+	siblings := make([][]byte, t.depth)
+	idx := key
+	for level := 0; level < t.depth; level++ {
+		siblings[level] = t.node(level, idx^1)
+		idx >>= 1
+	}
+	return siblings, nil
+}
+
+// ProveMulti returns the helper nodes of the [MultiMerkleProof] for the leaves
+// at keys.
+func (t *SparseTree) ProveMulti(keys []uint64) ([][]byte, error) {
+	helpers, err := multiProofHelpers(t.depth, keys)
+	if err != nil {
+		return nil, err
+	}
+	res := make([][]byte, len(helpers))
+	for i, pos := range helpers {
+		res[i] = t.node(pos.level, pos.index)
+	}
+	return res, nil
+}
@@ -0,0 +1,151 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/hash"
+	_ "github.com/airchains-network/gnark/std/hash/mimc"
+	_ "github.com/airchains-network/gnark/std/hash/poseidon2"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+type registeredHasherCircuit struct {
+	M    MerkleProof
+	Leaf frontend.Variable
+
+	hasher string
+}
+
+func (c *registeredHasherCircuit) Define(api frontend.API) error {
+	h, err := hash.GetFieldHasher(c.hasher, api)
+	if err != nil {
+		return err
+	}
+	c.M.VerifyProof(api, h, c.Leaf)
+	return nil
+}
+
+func TestNativeTreeRegisteredHashers(t *testing.T) {
+	const depth = 3
+	assert := test.NewAssert(t)
+	names := hash.ListFieldHashers()
+	assert.Contains(names, "mimc")
+	assert.Contains(names, "poseidon2")
+	for _, name := range names {
+		for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381} {
+			name, curve := name, curve
+			assert.Run(func(assert *test.Assert) {
+				h, err := hash.GetNativeHasher(name, curve)
+				assert.NoError(err)
+				leaves := make([][]byte, 1<<depth)
+				for i := range leaves {
+					v, err := rand.Int(rand.Reader, curve.ScalarField())
+					assert.NoError(err)
+					leaves[i] = v.FillBytes(make([]byte, (curve.ScalarField().BitLen()+7)/8))
+				}
+				tree, err := NewTree(h, leaves)
+				assert.NoError(err)
+				assert.Equal(depth, tree.Depth())
+
+				circuit := registeredHasherCircuit{M: MerkleProof{Path: make([]frontend.Variable, depth+1)}, hasher: name}
+				for _, index := range []uint64{0, 3, 1<<depth - 1} {
+					proof, err := tree.Prove(index)
+					assert.NoError(err)
+					assert.NoError(proof.Verify(h))
+
+					mp, leaf := proof.Assignment()
+					witness := registeredHasherCircuit{M: mp, Leaf: leaf}
+					assert.NoError(test.IsSolved(&circuit, &witness, curve.ScalarField()))
+
+					// wrong index
+					witness.Leaf = index ^ 1
+					assert.Error(test.IsSolved(&circuit, &witness, curve.ScalarField()))
+				}
+			}, name, curve.String())
+		}
+	}
+}
+
+func TestNativeProofSerialization(t *testing.T) {
+	assert := test.NewAssert(t)
+	h, err := hash.GetNativeHasher("mimc", ecc.BN254)
+	assert.NoError(err)
+	leaves := make([][]byte, 4)
+	for i := range leaves {
+		leaves[i] = make([]byte, 32)
+		leaves[i][31] = byte(i)
+	}
+	tree, err := NewTree(h, leaves)
+	assert.NoError(err)
+	proof, err := tree.Prove(2)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	written, err := proof.WriteTo(&buf)
+	assert.NoError(err)
+	assert.Equal(int64(buf.Len()), written)
+
+	var decoded NativeProof
+	read, err := decoded.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(written, read)
+	assert.Equal(*proof, decoded)
+	assert.NoError(decoded.Verify(h))
+
+	// truncated input
+	buf.Reset()
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(err)
+	_, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Error(err)
+
+	// lengths exceeding the limits are rejected before allocating
+	huge := []byte{0, 0, 0, 0, 0, 0, 0, 2, 0xff, 0xff, 0xff, 0xff}
+	_, err = decoded.ReadFrom(bytes.NewReader(huge))
+	assert.ErrorContains(err, "node size")
+	huge = []byte{0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff}
+	_, err = decoded.ReadFrom(bytes.NewReader(huge))
+	assert.ErrorContains(err, "path length")
+}
+
+func TestNewTreeErrors(t *testing.T) {
+	assert := test.NewAssert(t)
+	h, err := hash.GetNativeHasher("mimc", ecc.BN254)
+	assert.NoError(err)
+	for _, nbLeaves := range []int{0, 3, 6} {
+		_, err := NewTree(h, make([][]byte, nbLeaves))
+		assert.Error(err, fmt.Sprintf("nbLeaves=%d", nbLeaves))
+	}
+}
+
+func TestNativeNonCanonicalLeaf(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the leaf is larger than the modulus, the hash functions reject it
+	invalid := bytes.Repeat([]byte{0xff}, 32)
+	for _, name := range []string{"mimc", "poseidon2"} {
+		h, err := hash.GetNativeHasher(name, ecc.BN254)
+		assert.NoError(err)
+
+		_, err = NewTree(h, [][]byte{make([]byte, 32), invalid})
+		assert.Error(err, name)
+
+		tree, err := NewTree(h, [][]byte{make([]byte, 32), make([]byte, 32)})
+		assert.NoError(err, name)
+		proof, err := tree.Prove(1)
+		assert.NoError(err, name)
+		assert.NoError(proof.Verify(h), name)
+		proof.Path[0] = invalid
+		assert.Error(proof.Verify(h), name)
+
+		sparse, err := NewSparseTree(h, 4)
+		assert.NoError(err, name)
+		root := sparse.Root()
+		assert.Error(sparse.Set(3, invalid), name)
+		assert.Equal(root, sparse.Root(), name)
+	}
+}
@@ -0,0 +1,66 @@
+package merkle
+
+import (
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/hash"
+)
+
+// SparseMerkleProof is a proof for a sparse Merkle tree of fixed depth. In a
+// sparse Merkle tree every possible key in [0, 2^depth) has a leaf and the key
+// directly defines the path from the leaf to the root. Leaves which have not
+// been set have the value zero. This allows to prove both membership and
+// non-membership of a key.
+//
+// The hashing is the same as in [MerkleProof], i.e. leaves are hashed using
+// the hash of the value and the inner nodes are hashed as the hash of the
+// concatenation of the children. Use [SparseTree] for constructing the
+// witness.
+type SparseMerkleProof struct {
+	// RootHash is the root of the sparse Merkle tree.
+	RootHash frontend.Variable
+
+	// Siblings are the sibling nodes of the path from the leaf to the root,
+	// starting from the leaf level. The number of siblings defines the depth of
+	// the tree.
+	Siblings []frontend.Variable
+}
+
+// VerifyMembership asserts that the leaf at key has the given value.
+func (sp *SparseMerkleProof) VerifyMembership(api frontend.API, h hash.FieldHasher, key, value frontend.Variable) {
+	binKey := api.ToBinary(key, len(sp.Siblings))
+	root := pathRoot(api, h, binKey, leafSum(api, h, value), sp.Siblings)
+	api.AssertIsEqual(root, sp.RootHash)
+}
+
+// VerifyNonMembership asserts that the leaf at key has not been set, i.e. its
+// value is zero.
+func (sp *SparseMerkleProof) VerifyNonMembership(api frontend.API, h hash.FieldHasher, key frontend.Variable) {
+	sp.VerifyMembership(api, h, key, 0)
+}
+
+// UpdateLeaf asserts that the leaf at key has the value oldValue and returns
+// the root of the tree where the leaf value is replaced with newValue. If
+// oldValue is zero, then it corresponds to inserting a new leaf and if
+// newValue is zero, then it corresponds to deleting the leaf.
+//
+// The returned root can be used as a root for the next proof, allowing to
+// prove a sequence of updates.
+func (sp *SparseMerkleProof) UpdateLeaf(api frontend.API, h hash.FieldHasher, key, oldValue, newValue frontend.Variable) frontend.Variable {
+	binKey := api.ToBinary(key, len(sp.Siblings))
+	oldRoot := pathRoot(api, h, binKey, leafSum(api, h, oldValue), sp.Siblings)
+	api.AssertIsEqual(oldRoot, sp.RootHash)
+	return pathRoot(api, h, binKey, leafSum(api, h, newValue), sp.Siblings)
+}
+
+// pathRoot computes the root from the leaf hash and the siblings along the path
+// given by the little-endian bits of the leaf index. If the bit is set, then
+// the current node is the right child.
+func pathRoot(api frontend.API, h hash.FieldHasher, binIndex []frontend.Variable, leaf frontend.Variable, siblings []frontend.Variable) frontend.Variable {
+	sum := leaf
+	for i := range siblings {
+		d1 := api.Select(binIndex[i], siblings[i], sum)
+		d2 := api.Select(binIndex[i], sum, siblings[i])
+		sum = nodeSum(api, h, d1, d2)
+	}
+	return sum
+}
@@ -0,0 +1,245 @@
+package merkle
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/hash/mimc"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/hash"
+)
+
+const sparseTestDepth = 8
+
+func randomValue(t *testing.T) []byte {
+	v, err := rand.Int(rand.Reader, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, fr.Bytes)
+	return v.FillBytes(b)
+}
+
+func newTestSparseTree(t *testing.T, keys []uint64) *SparseTree {
+	tree, err := NewSparseTree(hash.MIMC_BN254.New(), sparseTestDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range keys {
+		if err := tree.Set(k, randomValue(t)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tree
+}
+
+func toVariables(in [][]byte) []frontend.Variable {
+	res := make([]frontend.Variable, len(in))
+	for i := range in {
+		res[i] = in[i]
+	}
+	return res
+}
+
+type sparseMembershipCircuit struct {
+	Proof       SparseMerkleProof
+	Key, Value  frontend.Variable
+	isNonMember bool
+}
+
+func (c *sparseMembershipCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	if c.isNonMember {
+		c.Proof.VerifyNonMembership(api, &h, c.Key)
+	} else {
+		c.Proof.VerifyMembership(api, &h, c.Key, c.Value)
+	}
+	return nil
+}
+
+func TestSparseMembership(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree := newTestSparseTree(t, []uint64{1, 5, 200})
+	value := randomValue(t)
+	assert.NoError(tree.Set(42, value))
+	siblings, err := tree.Prove(42)
+	assert.NoError(err)
+
+	circuit := sparseMembershipCircuit{Proof: SparseMerkleProof{Siblings: make([]frontend.Variable, sparseTestDepth)}}
+	witness := sparseMembershipCircuit{
+		Proof: SparseMerkleProof{RootHash: tree.Root(), Siblings: toVariables(siblings)},
+		Key:   42,
+		Value: value,
+	}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithCurves(ecc.BN254))
+
+	// wrong value
+	witness.Value = randomValue(t)
+	assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+}
+
+func TestSparseNonMembership(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree := newTestSparseTree(t, []uint64{1, 5, 200})
+	circuit := sparseMembershipCircuit{Proof: SparseMerkleProof{Siblings: make([]frontend.Variable, sparseTestDepth)}, isNonMember: true}
+	for _, key := range []uint64{0, 4, 201, 255} {
+		siblings, err := tree.Prove(key)
+		assert.NoError(err)
+		witness := sparseMembershipCircuit{
+			Proof: SparseMerkleProof{RootHash: tree.Root(), Siblings: toVariables(siblings)},
+			Key:   key,
+			Value: 0,
+		}
+		assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+	}
+	// existing key
+	siblings, err := tree.Prove(5)
+	assert.NoError(err)
+	witness := sparseMembershipCircuit{
+		Proof: SparseMerkleProof{RootHash: tree.Root(), Siblings: toVariables(siblings)},
+		Key:   5,
+		Value: 0,
+	}
+	assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+}
+
+type sparseUpdateCircuit struct {
+	Proofs    []SparseMerkleProof
+	Keys      []frontend.Variable
+	OldValues []frontend.Variable
+	NewValues []frontend.Variable
+	NewRoot   frontend.Variable
+}
+
+func (c *sparseUpdateCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	root := c.Proofs[0].RootHash
+	for i := range c.Proofs {
+		api.AssertIsEqual(c.Proofs[i].RootHash, root)
+		root = c.Proofs[i].UpdateLeaf(api, &h, c.Keys[i], c.OldValues[i], c.NewValues[i])
+	}
+	api.AssertIsEqual(root, c.NewRoot)
+	return nil
+}
+
+func TestSparseUpdateLeaf(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree := newTestSparseTree(t, []uint64{1})
+	empty := make([]byte, fr.Bytes)
+	v5, v200 := randomValue(t), randomValue(t)
+	assert.NoError(tree.Set(5, v5))
+	assert.NoError(tree.Set(200, v200))
+	// update existing, insert new and delete existing
+	keys := []uint64{5, 6, 200}
+	oldValues := [][]byte{v5, empty, v200}
+	newValues := [][]byte{randomValue(t), randomValue(t), empty}
+
+	circuit := sparseUpdateCircuit{
+		Proofs:    make([]SparseMerkleProof, len(keys)),
+		Keys:      make([]frontend.Variable, len(keys)),
+		OldValues: make([]frontend.Variable, len(keys)),
+		NewValues: make([]frontend.Variable, len(keys)),
+	}
+	witness := sparseUpdateCircuit{
+		Proofs:    make([]SparseMerkleProof, len(keys)),
+		Keys:      make([]frontend.Variable, len(keys)),
+		OldValues: make([]frontend.Variable, len(keys)),
+		NewValues: make([]frontend.Variable, len(keys)),
+	}
+	for i, key := range keys {
+		circuit.Proofs[i].Siblings = make([]frontend.Variable, sparseTestDepth)
+		siblings, err := tree.Prove(key)
+		assert.NoError(err)
+		witness.Proofs[i] = SparseMerkleProof{RootHash: tree.Root(), Siblings: toVariables(siblings)}
+		witness.Keys[i] = key
+		witness.OldValues[i] = oldValues[i]
+		witness.NewValues[i] = newValues[i]
+		assert.NoError(tree.Set(key, newValues[i]))
+	}
+	witness.NewRoot = tree.Root()
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithCurves(ecc.BN254))
+
+	// wrong old value
+	witness.OldValues[1] = randomValue(t)
+	assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+}
+
+type multiProofCircuit struct {
+	Proof  MultiMerkleProof
+	Leaves []frontend.Variable
+}
+
+func (c *multiProofCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.VerifyProof(api, &h, c.Leaves)
+	return nil
+}
+
+func TestMultiMerkleProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, keys := range [][]uint64{
+		{7},
+		{0, 1},
+		{3, 2, 100, 255},
+		{0, 1, 2, 3, 4, 5, 6, 7},
+	} {
+		tree := newTestSparseTree(t, []uint64{10, 11, 12})
+		values := make([][]byte, len(keys))
+		for i := range keys {
+			values[i] = randomValue(t)
+			assert.NoError(tree.Set(keys[i], values[i]))
+		}
+		helpers, err := tree.ProveMulti(keys)
+		assert.NoError(err)
+
+		circuit := multiProofCircuit{
+			Proof:  NewMultiMerkleProof(sparseTestDepth, keys),
+			Leaves: make([]frontend.Variable, len(keys)),
+		}
+		assert.Equal(len(circuit.Proof.Nodes), len(helpers))
+		witness := multiProofCircuit{
+			Proof:  MultiMerkleProof{RootHash: tree.Root(), Nodes: toVariables(helpers)},
+			Leaves: toVariables(values),
+		}
+		assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+
+		// swapped leaves
+		if len(keys) > 1 {
+			witness.Leaves[0], witness.Leaves[1] = witness.Leaves[1], witness.Leaves[0]
+			assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+		}
+	}
+}
+
+func TestMultiProofHelpers(t *testing.T) {
+	assert := test.NewAssert(t)
+	// all leaves known, no helpers needed
+	helpers, err := multiProofHelpers(2, []uint64{0, 1, 2, 3})
+	assert.NoError(err)
+	assert.Len(helpers, 0)
+	// single leaf needs one helper per level
+	helpers, err = multiProofHelpers(3, []uint64{5})
+	assert.NoError(err)
+	assert.Equal([]nodePosition{{0, 4}, {1, 3}, {2, 0}}, helpers)
+	// shared nodes are not included
+	helpers, err = multiProofHelpers(3, []uint64{0, 3})
+	assert.NoError(err)
+	assert.Equal([]nodePosition{{0, 1}, {0, 2}, {2, 1}}, helpers)
+
+	_, err = multiProofHelpers(3, []uint64{1, 1})
+	assert.Error(err)
+	_, err = multiProofHelpers(3, []uint64{8})
+	assert.Error(err)
+}
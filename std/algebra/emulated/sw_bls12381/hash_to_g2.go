@@ -0,0 +1,235 @@
+package sw_bls12381
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/airchains-network/gnark/std/hash/sha2"
+	"github.com/airchains-network/gnark/std/math/emulated"
+	"github.com/airchains-network/gnark/std/math/uints"
+)
+
+// constants of the simplified SWU map to the curve E2' isogenous to E2 and of
+// the 3-isogeny E2' -> E2, see [RFC 9380], section 8.8.2 and appendix E.3.
+//
+// [RFC 9380]: https://datatracker.ietf.org/doc/html/rfc9380
+var (
+	sswuA = newE2Const("0", "240")
+	sswuB = newE2Const("1012", "1012")
+	sswuZ = newE2Const("-2", "-1")
+
+	isoXNum = []fields_bls12381.E2{
+		newE2Const("889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542"),
+		newE2Const("0", "2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706522"),
+		newE2Const("2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706526", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853261"),
+		newE2Const("3557697382419259905260257622876359250272784728834673675850718343221361467102966990615722337003569479144794908942033", "0"),
+	}
+	isoXDen = []fields_bls12381.E2{
+		newE2Const("0", "-72"),
+		newE2Const("12", "-12"),
+	}
+	isoYNum = []fields_bls12381.E2{
+		newE2Const("3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558", "3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558"),
+		newE2Const("0", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235518"),
+		newE2Const("2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706524", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853263"),
+		newE2Const("2816510427748580758331037284777117739799287910327449993381818688383577828123182200904113516794492504322962636245776", "0"),
+	}
+	isoYDen = []fields_bls12381.E2{
+		newE2Const("-432", "-432"),
+		newE2Const("0", "-216"),
+		newE2Const("18", "-18"),
+	}
+)
+
+func newE2Const(a0, a1 string) fields_bls12381.E2 {
+	return fields_bls12381.E2{
+		A0: emulated.ValueOf[BaseField](a0),
+		A1: emulated.ValueOf[BaseField](a1),
+	}
+}
+
+// HashToG2 hashes the message msg to a point in G2 using the
+// BLS12381G2_XMD:SHA-256_SSWU_RO_ suite from [RFC 9380]. The domain
+// separation tag dst is a constant known at circuit compile time.
+//
+// The result matches the output of HashToG2 in gnark-crypto.
+//
+// ⚠️  The exceptional cases of the map, which happen only when the hashed field
+// elements are zero or roots of Z²u⁴+Zu², are not handled.
+//
+// [RFC 9380]: https://datatracker.ietf.org/doc/html/rfc9380
+func (g2 *G2) HashToG2(msg []uints.U8, dst []byte) (*G2Affine, error) {
+	u, err := g2.hashToFp2(msg, dst, 2)
+	if err != nil {
+		return nil, fmt.Errorf("hash to field: %w", err)
+	}
+	q0 := g2.isogeny(g2.mapToCurve2(u[0]))
+	q1 := g2.isogeny(g2.mapToCurve2(u[1]))
+	return g2.clearCofactor(g2.add(q0, q1)), nil
+}
+
+// hashToFp2 hashes the message msg to count elements in E2 as in [RFC 9380],
+// section 5.2.
+func (g2 *G2) hashToFp2(msg []uints.U8, dst []byte, count int) ([]*fields_bls12381.E2, error) {
+	// L = ceil((ceil(log2(p)) + k) / 8), where k=128 is the security level
+	const L = 64
+	uniform, err := sha2.ExpandMsgXmd(g2.api, msg, dst, 2*count*L)
+	if err != nil {
+		return nil, fmt.Errorf("expand message: %w", err)
+	}
+	res := make([]*fields_bls12381.E2, count)
+	for i := range res {
+		res[i] = &fields_bls12381.E2{
+			A0: *g2.bytesToFp(uniform[2*i*L : (2*i+1)*L]),
+			A1: *g2.bytesToFp(uniform[(2*i+1)*L : (2*i+2)*L]),
+		}
+	}
+	return res, nil
+}
+
+// bytesToFp reduces the 64-byte big-endian integer b modulo p. As b is larger
+// than the emulated elements, we split it as b = hi·2³⁸⁴ + lo.
+func (g2 *G2) bytesToFp(b []uints.U8) *emulated.Element[BaseField] {
+	var fp BaseField
+	nbBits := int(fp.NbLimbs() * fp.BitsPerLimb())
+	split := len(b) - nbBits/8
+	loBits := bytesToBits(g2.api, b[split:])
+	hiBits := bytesToBits(g2.api, b[:split])
+	// pad to obtain an element with full number of limbs
+	for len(hiBits) < nbBits {
+		hiBits = append(hiBits, 0)
+	}
+	shift := new(big.Int).Lsh(big.NewInt(1), uint(nbBits))
+	shift.Mod(shift, fp.Modulus())
+	shiftEl := emulated.ValueOf[BaseField](shift)
+	hi := g2.fp.Mul(g2.fp.FromBits(hiBits...), &shiftEl)
+	return g2.fp.Add(hi, g2.fp.FromBits(loBits...))
+}
+
+// bytesToBits returns the little-endian bit decomposition of the big-endian
+// byte slice b.
+func bytesToBits(api frontend.API, b []uints.U8) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 8*len(b))
+	for i := len(b) - 1; i >= 0; i-- {
+		res = append(res, api.ToBinary(b[i].Val, 8)...)
+	}
+	return res
+}
+
+// mapToCurve2 maps u to a point on the curve E2' isogenous to E2 using the
+// simplified SWU map, see [RFC 9380], section 6.6.2.
+//
+// Instead of computing the square root in-circuit, we compute both candidate
+// x-coordinates x1 and x2=Z·u²·x1 and obtain the point (x, y) with a hint. As
+// g(x2)=Z³·u⁶·g(x1) and Z is a non-square, exactly one of g(x1) and g(x2) is a
+// square. Thus it is sufficient to check that x ∈ {x1, x2} and y²=g(x).
+func (g2 *G2) mapToCurve2(u *fields_bls12381.E2) *G2Affine {
+	// tv1 = Z·u²
+	tv1 := g2.Ext2.Mul(g2.Ext2.Square(u), &sswuZ)
+	// tv2 = tv1² + tv1
+	tv2 := g2.Ext2.Add(g2.Ext2.Square(tv1), tv1)
+	// x1 = (-B/A)·(1 + 1/tv2) = -B·(tv2 + 1) / (A·tv2)
+	num := g2.Ext2.Neg(g2.Ext2.Mul(g2.Ext2.Add(tv2, g2.Ext2.One()), &sswuB))
+	den := g2.Ext2.Mul(tv2, &sswuA)
+	x1 := g2.Ext2.DivUnchecked(num, den)
+	// x2 = Z·u²·x1
+	x2 := g2.Ext2.Mul(tv1, x1)
+	gx1 := g2.sswuRHS(x1)
+	gx2 := g2.sswuRHS(x2)
+
+	res, err := g2.fp.NewHint(sswuHint, 4, &u.A0, &u.A1, &x1.A0, &x1.A1, &x2.A0, &x2.A1, &gx1.A0, &gx1.A1, &gx2.A0, &gx2.A1)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	x := &fields_bls12381.E2{A0: *res[0], A1: *res[1]}
+	y := &fields_bls12381.E2{A0: *res[2], A1: *res[3]}
+
+	// (x - x1)·(x - x2) == 0
+	g2.Ext2.AssertIsEqual(g2.Ext2.Mul(g2.Ext2.Sub(x, x1), g2.Ext2.Sub(x, x2)), g2.Ext2.Zero())
+	// y² == g(x)
+	g2.Ext2.AssertIsEqual(g2.Ext2.Square(y), g2.sswuRHS(x))
+	// sgn0(u) == sgn0(y)
+	g2.api.AssertIsEqual(g2.sgn0(u), g2.sgn0(y))
+
+	return &G2Affine{
+		P: g2AffP{X: *x, Y: *y},
+	}
+}
+
+// sswuRHS returns x³+A·x+B on the isogenous curve E2'.
+func (g2 *G2) sswuRHS(x *fields_bls12381.E2) *fields_bls12381.E2 {
+	res := g2.Ext2.Add(g2.Ext2.Square(x), &sswuA)
+	res = g2.Ext2.Mul(res, x)
+	return g2.Ext2.Add(res, &sswuB)
+}
+
+// sgn0 returns the sign of x as defined in [RFC 9380], section 4.1.
+func (g2 *G2) sgn0(x *fields_bls12381.E2) frontend.Variable {
+	a0 := g2.fp.Reduce(&x.A0)
+	g2.fp.AssertIsInRange(a0)
+	a0Bits := g2.fp.ToBits(a0)
+	a1 := g2.fp.Reduce(&x.A1)
+	g2.fp.AssertIsInRange(a1)
+	a1Bits := g2.fp.ToBits(a1)
+	isZero0 := g2.api.IsZero(g2.api.Add(a0Bits[0], a0Bits[1], a0Bits[2:]...))
+	// sign_0 OR (zero_0 AND sign_1). When a0 is zero then its sign is zero, so
+	// we can use addition for OR.
+	return g2.api.Add(a0Bits[0], g2.api.Mul(isZero0, a1Bits[0]))
+}
+
+// isogeny maps the point p on E2' to E2 using the 3-isogeny from [RFC 9380],
+// appendix E.3.
+func (g2 *G2) isogeny(p *G2Affine) *G2Affine {
+	xNum := g2.evalPolynomial(isoXNum, false, &p.P.X)
+	xDen := g2.evalPolynomial(isoXDen, true, &p.P.X)
+	yNum := g2.evalPolynomial(isoYNum, false, &p.P.X)
+	yDen := g2.evalPolynomial(isoYDen, true, &p.P.X)
+	x := g2.Ext2.DivUnchecked(xNum, xDen)
+	y := g2.Ext2.Mul(&p.P.Y, g2.Ext2.DivUnchecked(yNum, yDen))
+	return &G2Affine{
+		P: g2AffP{X: *x, Y: *y},
+	}
+}
+
+// evalPolynomial evaluates the polynomial with coefficients coeffs (in
+// increasing degree) at x using Horner's method. If monic is set, then the
+// leading coefficient 1 is implicit.
+func (g2 *G2) evalPolynomial(coeffs []fields_bls12381.E2, monic bool, x *fields_bls12381.E2) *fields_bls12381.E2 {
+	res := &coeffs[len(coeffs)-1]
+	if monic {
+		res = g2.Ext2.Add(res, x)
+	}
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		res = g2.Ext2.Mul(res, x)
+		res = g2.Ext2.Add(res, &coeffs[i])
+	}
+	return res
+}
+
+// clearCofactor maps the point q on E2 to G2 by multiplying it with the
+// effective cofactor h_eff from [RFC 9380], section 8.8.2. We use the method by
+// Budroni and Pintore:
+//
+//	h_eff·Q = [x²-x-1]Q + [x-1]ψ(Q) + ψ²([2]Q)
+//
+// where x is the curve seed.
+func (g2 *G2) clearCofactor(q *G2Affine) *G2Affine {
+	// [x]Q and [x²]Q
+	xq := g2.scalarMulBySeed(q)
+	xxq := g2.scalarMulBySeed(xq)
+	// [x²-x-1]Q
+	res := g2.sub(g2.sub(xxq, xq), q)
+	// ψ([x-1]Q)
+	t := g2.psi(g2.sub(xq, q))
+	res = g2.add(res, t)
+	// ψ²([2]Q) = (w·X, -Y) where [2]Q = (X, Y)
+	t = g2.double(q)
+	tx := g2.Ext2.MulByElement(&t.P.X, g2.w)
+	t = &G2Affine{
+		P: g2AffP{X: *tx, Y: t.P.Y},
+	}
+	return g2.sub(res, t)
+}
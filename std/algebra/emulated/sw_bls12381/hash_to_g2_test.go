@@ -0,0 +1,47 @@
+package sw_bls12381
+
+import (
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+type hashToG2Circuit struct {
+	Msg []uints.U8
+	Res G2Affine
+
+	dst []byte
+}
+
+func (c *hashToG2Circuit) Define(api frontend.API) error {
+	g2 := NewG2(api)
+	res, err := g2.HashToG2(c.Msg, c.dst)
+	if err != nil {
+		return err
+	}
+	g2.AssertIsEqual(res, &c.Res)
+	return nil
+}
+
+func TestHashToG2TestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")
+	for _, msg := range []string{"", "abc"} {
+		res, err := bls12381.HashToG2([]byte(msg), dst)
+		assert.NoError(err)
+		witness := hashToG2Circuit{
+			Msg: uints.NewU8Array([]byte(msg)),
+			Res: NewG2Affine(res),
+		}
+		circuit := hashToG2Circuit{
+			Msg: make([]uints.U8, len(msg)),
+			dst: dst,
+		}
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+}
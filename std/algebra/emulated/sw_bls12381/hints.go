@@ -0,0 +1,60 @@
+package sw_bls12381
+
+import (
+	"math/big"
+
+	"github.com/airchains-network/gnark/constraint/solver"
+	"github.com/airchains-network/gnark/std/math/emulated"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		sswuHint,
+	}
+}
+
+// sswuHint returns the point (x, y) of the simplified SWU map given the two
+// candidates x1 and x2 and the corresponding right hand sides gx1 and gx2. The
+// sign of y is chosen to match the sign of u.
+func sswuHint(nativeMod *big.Int, nativeInputs, nativeOutputs []*big.Int) error {
+	return emulated.UnwrapHint(nativeInputs, nativeOutputs,
+		func(mod *big.Int, inputs, outputs []*big.Int) error {
+			var u, x, gx, y bls12381.E2
+
+			u.A0.SetBigInt(inputs[0])
+			u.A1.SetBigInt(inputs[1])
+			x.A0.SetBigInt(inputs[2])
+			x.A1.SetBigInt(inputs[3])
+			gx.A0.SetBigInt(inputs[6])
+			gx.A1.SetBigInt(inputs[7])
+			if gx.Legendre() != 1 {
+				x.A0.SetBigInt(inputs[4])
+				x.A1.SetBigInt(inputs[5])
+				gx.A0.SetBigInt(inputs[8])
+				gx.A1.SetBigInt(inputs[9])
+			}
+			y.Sqrt(&gx)
+			if sgn0(&u) != sgn0(&y) {
+				y.Neg(&y)
+			}
+
+			x.A0.BigInt(outputs[0])
+			x.A1.BigInt(outputs[1])
+			y.A0.BigInt(outputs[2])
+			y.A1.BigInt(outputs[3])
+
+			return nil
+		})
+}
+
+// sgn0 returns the sign of z as defined in RFC 9380, section 4.1.
+func sgn0(z *bls12381.E2) bool {
+	a0, a1 := z.A0.BigInt(new(big.Int)), z.A1.BigInt(new(big.Int))
+	return a0.Bit(0) == 1 || (a0.Sign() == 0 && a1.Bit(0) == 1)
+}
@@ -0,0 +1,277 @@
+package sw_emulated
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/algopts"
+	"github.com/airchains-network/gnark/std/lookup/logderivlookup"
+	"github.com/airchains-network/gnark/std/math/emulated"
+)
+
+// multiScalarMulWindowed computes the multi scalar multiplication of the points
+// p and scalars s using the windowed method with shared doublings. It doesn't
+// modify the inputs.
+//
+// For every point P_i we precompute the table of multiples [d]P_i for d in
+// [1, 2^c) where c is the window size and store the limbs of all the multiples
+// in a single log-derivative lookup table. The scalars are then split into
+// windows of c bits and we process the windows from the most significant one.
+// For every window we double the accumulator c times and add the multiples
+// [d_ij]P_i selected from the table using the digit d_ij of the scalar s_i as
+// an index. The doublings are shared between all the points, which is the main
+// saving compared to performing the scalar multiplications independently.
+// See [algopts.WithWindowedMultiScalarMul] for the comparison with the bucket
+// method.
+//
+// We use incomplete formulas for the additions and doublings, so the
+// accumulator is initialised with a fixed point R whose discrete logarithm is
+// unknown and the accumulated multiple of R is subtracted at the end using
+// complete formulas. As the inputs are chosen by the prover, a table entry may
+// still be equal or opposite to the accumulator, for example when P_i = R. In
+// that case the slope of the incomplete formula would be 0/0 and the prover
+// could choose it freely. To prevent this, all the denominators are
+// constrained to be invertible (see [Curve.msmAdd] and [Curve.msmDouble]):
+// such inputs make the circuit unsatisfiable instead. For honestly generated
+// inputs this happens only with negligible probability, as it would give the
+// discrete logarithm of R.
+//
+// ⚠️  Points must be nonzero and not of small order.
+func (c *Curve[B, S]) multiScalarMulWindowed(p []*AffinePoint[B], s []*emulated.Element[S], opts ...algopts.AlgebraOption) (*AffinePoint[B], error) {
+	cfg, err := algopts.NewConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("new config: %w", err)
+	}
+	if len(p) != len(s) {
+		return nil, fmt.Errorf("mismatching points and scalars slice lengths")
+	}
+	var fp B
+	var fr S
+	nbLimbs := int(fp.NbLimbs())
+	nbBits := fr.Modulus().BitLen()
+	if cfg.NbScalarBits > 0 && cfg.NbScalarBits < nbBits {
+		nbBits = cfg.NbScalarBits
+	}
+	window := cfg.MSMWindow
+	if window == 0 {
+		window = msmWindowSize(len(p), nbBits)
+	}
+	nbWindows := (nbBits + window - 1) / window
+	nbEntries := 1 << window
+	// every multiple is stored as limbs of X followed by the limbs of Y
+	stride := 2 * nbLimbs
+
+	// build the table of multiples. We store P_i at the index of the zero
+	// digit to keep all entries on the curve, but never use it.
+	table := logderivlookup.New(c.api)
+	for i := range p {
+		q := &AffinePoint[B]{
+			X: *c.baseApi.Reduce(&p[i].X),
+			Y: *c.baseApi.Reduce(&p[i].Y),
+		}
+		multiples := make([]*AffinePoint[B], nbEntries)
+		multiples[0] = q
+		multiples[1] = q
+		if nbEntries > 2 {
+			multiples[2] = c.msmDouble(q)
+		}
+		for d := 3; d < nbEntries; d++ {
+			multiples[d] = c.msmAdd(multiples[d-1], q)
+		}
+		for d := range multiples {
+			if len(multiples[d].X.Limbs) != nbLimbs || len(multiples[d].Y.Limbs) != nbLimbs {
+				return nil, fmt.Errorf("point %d: unexpected number of limbs", i)
+			}
+			for j := range multiples[d].X.Limbs {
+				table.Insert(multiples[d].X.Limbs[j])
+			}
+			for j := range multiples[d].Y.Limbs {
+				table.Insert(multiples[d].Y.Limbs[j])
+			}
+		}
+	}
+
+	// decompose the scalars into windows. The digits are native variables.
+	digits := make([][]frontend.Variable, len(s))
+	isZero := make([][]frontend.Variable, len(s))
+	for i := range s {
+		sr := c.scalarApi.Reduce(s[i])
+		sBits := c.scalarApi.ToBits(sr)
+		digits[i] = make([]frontend.Variable, nbWindows)
+		isZero[i] = make([]frontend.Variable, nbWindows)
+		for j := 0; j < nbWindows; j++ {
+			var digit frontend.Variable = 0
+			for k := window - 1; k >= 0; k-- {
+				digit = c.api.Mul(digit, 2)
+				if j*window+k < nbBits {
+					digit = c.api.Add(digit, sBits[j*window+k])
+				}
+			}
+			digits[i][j] = digit
+			isZero[i][j] = c.api.IsZero(digit)
+		}
+	}
+
+	R, Rshifted := msmOffsetPoints[B](c.params, window*(nbWindows-1))
+	acc := &AffinePoint[B]{
+		X: emulated.ValueOf[B](R[0]),
+		Y: emulated.ValueOf[B](R[1]),
+	}
+	inds := make([]frontend.Variable, stride)
+	for j := nbWindows - 1; j >= 0; j-- {
+		if j != nbWindows-1 {
+			for k := 0; k < window; k++ {
+				acc = c.msmDouble(acc)
+			}
+		}
+		for i := range p {
+			// index of the first limb of [d_ij]P_i
+			base := c.api.Mul(c.api.Add(i*nbEntries, digits[i][j]), stride)
+			for l := range inds {
+				inds[l] = c.api.Add(base, l)
+			}
+			limbs := table.Lookup(inds...)
+			q := &AffinePoint[B]{
+				X: *c.baseApi.NewElement(limbs[:nbLimbs]),
+				Y: *c.baseApi.NewElement(limbs[nbLimbs:]),
+			}
+			acc = c.Select(isZero[i][j], acc, c.msmAdd(acc, q))
+		}
+	}
+
+	// subtract the offset R' = [2^(c*(nbWindows-1))]R. The result is the
+	// point at infinity when acc = R'. Otherwise, acc.x ≠ R'.x unless acc = -R',
+	// which we reject, so the slope of the chord is well defined.
+	offset := &AffinePoint[B]{
+		X: emulated.ValueOf[B](Rshifted[0]),
+		Y: emulated.ValueOf[B](Rshifted[1]),
+	}
+	isOffset := c.baseApi.IsZero(c.baseApi.Sub(&acc.X, &offset.X))
+	c.baseApi.AssertIsEqual(c.baseApi.Select(isOffset, &acc.Y, &offset.Y), &offset.Y)
+	dummy := c.Select(isOffset, c.Neg(c.Generator()), acc)
+	res := c.add(dummy, c.Neg(offset))
+	zero := c.baseApi.Zero()
+	return c.Select(isOffset, &AffinePoint[B]{X: *zero, Y: *zero}, res), nil
+}
+
+// msmAdd adds p and q and returns it. It doesn't modify p nor q. It uses the
+// incomplete formulas in affine coordinates, but contrary to [Curve.add] it
+// constrains q.x-p.x to be invertible. If p = ±q, then the circuit is not
+// satisfiable instead of leaving the slope unconstrained.
+func (c *Curve[B, S]) msmAdd(p, q *AffinePoint[B]) *AffinePoint[B] {
+	// compute λ = (q.y-p.y)/(q.x-p.x)
+	qypy := c.baseApi.Sub(&q.Y, &p.Y)
+	qxpx := c.baseApi.Sub(&q.X, &p.X)
+	λ := c.baseApi.MulMod(qypy, c.baseApi.Inverse(qxpx))
+
+	// xr = λ²-p.x-q.x
+	λλ := c.baseApi.MulMod(λ, λ)
+	qxpx = c.baseApi.Add(&p.X, &q.X)
+	xr := c.baseApi.Sub(λλ, qxpx)
+
+	// p.y = λ(p.x-r.x) - p.y
+	pxrx := c.baseApi.Sub(&p.X, xr)
+	λpxrx := c.baseApi.MulMod(λ, pxrx)
+	yr := c.baseApi.Sub(λpxrx, &p.Y)
+
+	return &AffinePoint[B]{
+		X: *c.baseApi.Reduce(xr),
+		Y: *c.baseApi.Reduce(yr),
+	}
+}
+
+// msmDouble doubles p and returns it. It doesn't modify p. Contrary to
+// [Curve.double], it constrains 2p.y to be invertible. If p.y = 0, for example
+// when p is (0,0), then the circuit is not satisfiable instead of leaving the
+// slope unconstrained.
+func (c *Curve[B, S]) msmDouble(p *AffinePoint[B]) *AffinePoint[B] {
+	// compute λ = (3p.x²+a)/2*p.y
+	xx3a := c.baseApi.MulMod(&p.X, &p.X)
+	xx3a = c.baseApi.MulConst(xx3a, big.NewInt(3))
+	if c.addA {
+		xx3a = c.baseApi.Add(xx3a, &c.a)
+	}
+	y2 := c.baseApi.MulConst(&p.Y, big.NewInt(2))
+	λ := c.baseApi.MulMod(xx3a, c.baseApi.Inverse(y2))
+
+	// xr = λ²-2p.x
+	x2 := c.baseApi.MulConst(&p.X, big.NewInt(2))
+	λλ := c.baseApi.MulMod(λ, λ)
+	xr := c.baseApi.Sub(λλ, x2)
+
+	// yr = λ(p-xr) - p.y
+	pxrx := c.baseApi.Sub(&p.X, xr)
+	λpxrx := c.baseApi.MulMod(λ, pxrx)
+	yr := c.baseApi.Sub(λpxrx, &p.Y)
+
+	return &AffinePoint[B]{
+		X: *c.baseApi.Reduce(xr),
+		Y: *c.baseApi.Reduce(yr),
+	}
+}
+
+// msmWindowSize returns the window size minimising the number of point
+// operations when computing multi scalar multiplication of nbPoints points
+// with nbBits-bit scalars.
+func msmWindowSize(nbPoints, nbBits int) int {
+	best, bestCost := 1, -1
+	for w := 1; w <= 8; w++ {
+		nbWindows := (nbBits + w - 1) / w
+		// table precomputation, window additions and shared doublings
+		cost := nbPoints*((1<<w)-2) + nbPoints*nbWindows + w*(nbWindows-1)
+		if bestCost < 0 || cost < bestCost {
+			best, bestCost = w, cost
+		}
+	}
+	return best
+}
+
+// msmOffsetPoints returns the point R on the curve with the smallest
+// x-coordinate and its multiple [2^nbDoublings]R. As R is chosen
+// independently of the generator, its discrete logarithm is unknown.
+func msmOffsetPoints[B emulated.FieldParams](params CurveParams, nbDoublings int) (R, Rshifted [2]*big.Int) {
+	var fp B
+	p := fp.Modulus()
+	x := big.NewInt(0)
+	y := new(big.Int)
+	rhs := new(big.Int)
+	for {
+		x.Add(x, big.NewInt(1))
+		// y² = x³ + ax + b
+		rhs.Mul(x, x)
+		rhs.Add(rhs, params.A)
+		rhs.Mul(rhs, x)
+		rhs.Add(rhs, params.B)
+		rhs.Mod(rhs, p)
+		if rhs.Sign() != 0 && y.ModSqrt(rhs, p) != nil {
+			break
+		}
+	}
+	R = [2]*big.Int{new(big.Int).Set(x), new(big.Int).Set(y)}
+
+	rx, ry := new(big.Int).Set(x), new(big.Int).Set(y)
+	lambda, tmp := new(big.Int), new(big.Int)
+	for i := 0; i < nbDoublings; i++ {
+		// λ = (3x²+a)/2y
+		lambda.Mul(rx, rx)
+		lambda.Mul(lambda, big.NewInt(3))
+		lambda.Add(lambda, params.A)
+		tmp.Lsh(ry, 1)
+		tmp.ModInverse(tmp, p)
+		lambda.Mul(lambda, tmp)
+		lambda.Mod(lambda, p)
+		// x' = λ²-2x, y' = λ(x-x')-y
+		tmp.Mul(lambda, lambda)
+		tmp.Sub(tmp, rx)
+		tmp.Sub(tmp, rx)
+		tmp.Mod(tmp, p)
+		rx.Sub(rx, tmp)
+		rx.Mul(rx, lambda)
+		ry.Sub(rx, ry)
+		ry.Mod(ry, p)
+		rx.Set(tmp)
+	}
+	Rshifted = [2]*big.Int{rx, ry}
+	return
+}
@@ -0,0 +1,183 @@
+package sw_emulated
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	fr_secp "github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/airchains-network/gnark/std/algebra/algopts"
+	"github.com/airchains-network/gnark/std/math/emulated"
+	"github.com/airchains-network/gnark/test"
+)
+
+// multiScalarMulBuckets computes the multi scalar multiplication of the points
+// p and scalars s using the bucket (Pippenger) method with windows of window
+// bits. It is only used as a reference to compare the number of constraints
+// with [Curve.multiScalarMulWindowed].
+//
+// In a circuit the bucket of a point depends on the scalar, so every point is
+// conditionally added to all the 2^window-1 buckets of every window. As the
+// buckets start empty and may receive equal points, we need the complete
+// formulas of [Curve.AddUnified].
+func (c *Curve[B, S]) multiScalarMulBuckets(p []*AffinePoint[B], s []*emulated.Element[S], window int) *AffinePoint[B] {
+	var fr S
+	nbBits := fr.Modulus().BitLen()
+	nbWindows := (nbBits + window - 1) / window
+	nbBuckets := (1 << window) - 1
+
+	sBits := make([][]frontend.Variable, len(s))
+	for i := range s {
+		sBits[i] = c.scalarApi.ToBits(c.scalarApi.Reduce(s[i]))
+	}
+	zero := c.baseApi.Zero()
+	infinity := &AffinePoint[B]{X: *zero, Y: *zero}
+
+	acc := infinity
+	buckets := make([]*AffinePoint[B], nbBuckets)
+	for j := nbWindows - 1; j >= 0; j-- {
+		for k := 0; k < window; k++ {
+			acc = c.AddUnified(acc, acc)
+		}
+		for d := range buckets {
+			buckets[d] = infinity
+		}
+		for i := range p {
+			var digit frontend.Variable = 0
+			for k := window - 1; k >= 0; k-- {
+				digit = c.api.Mul(digit, 2)
+				if j*window+k < nbBits {
+					digit = c.api.Add(digit, sBits[i][j*window+k])
+				}
+			}
+			// bucket d accumulates the points with digit d+1
+			for d := range buckets {
+				isDigit := c.api.IsZero(c.api.Sub(digit, d+1))
+				buckets[d] = c.Select(isDigit, c.AddUnified(buckets[d], p[i]), buckets[d])
+			}
+		}
+		// Σ (d+1)*B_d using running sums
+		running, sum := infinity, infinity
+		for d := nbBuckets - 1; d >= 0; d-- {
+			running = c.AddUnified(running, buckets[d])
+			sum = c.AddUnified(sum, running)
+		}
+		acc = c.AddUnified(acc, sum)
+	}
+	return acc
+}
+
+type MultiScalarMulBucketsTest[T, S emulated.FieldParams] struct {
+	Points  []AffinePoint[T]
+	Scalars []emulated.Element[S]
+	Res     AffinePoint[T]
+
+	// window is the window size of the bucket method. If zero, then the
+	// windowed method with lookup tables is used instead.
+	window int
+}
+
+func (c *MultiScalarMulBucketsTest[T, S]) Define(api frontend.API) error {
+	cr, err := New[T, S](api, GetCurveParams[T]())
+	if err != nil {
+		return err
+	}
+	ps := make([]*AffinePoint[T], len(c.Points))
+	for i := range c.Points {
+		ps[i] = &c.Points[i]
+	}
+	ss := make([]*emulated.Element[S], len(c.Scalars))
+	for i := range c.Scalars {
+		ss[i] = &c.Scalars[i]
+	}
+	var res *AffinePoint[T]
+	if c.window == 0 {
+		if res, err = cr.MultiScalarMul(ps, ss, algopts.WithWindowedMultiScalarMul(0)); err != nil {
+			return err
+		}
+	} else {
+		res = cr.multiScalarMulBuckets(ps, ss, c.window)
+	}
+	cr.AssertIsEqual(res, &c.Res)
+	return nil
+}
+
+func newMultiScalarMulBucketsCircuit(nbPoints, window int) *MultiScalarMulBucketsTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr] {
+	return &MultiScalarMulBucketsTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Points:  make([]AffinePoint[emulated.Secp256k1Fp], nbPoints),
+		Scalars: make([]emulated.Element[emulated.Secp256k1Fr], nbPoints),
+		window:  window,
+	}
+}
+
+func TestMultiScalarMulBuckets(t *testing.T) {
+	assert := test.NewAssert(t)
+	nbLen := 3
+	P := make([]secp256k1.G1Affine, nbLen)
+	S := make([]fr_secp.Element, nbLen)
+	_, g := secp256k1.Generators()
+	for i := 0; i < nbLen; i++ {
+		S[i].SetRandom()
+		P[i].ScalarMultiplication(&g, S[i].BigInt(new(big.Int)))
+	}
+	var res secp256k1.G1Affine
+	_, err := res.MultiExp(P, S, ecc.MultiExpConfig{})
+	assert.NoError(err)
+
+	assignment := newMultiScalarMulBucketsCircuit(nbLen, 0)
+	for i := range P {
+		assignment.Points[i] = AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](P[i].X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](P[i].Y),
+		}
+		assignment.Scalars[i] = emulated.ValueOf[emulated.Secp256k1Fr](S[i])
+	}
+	assignment.Res = AffinePoint[emulated.Secp256k1Fp]{
+		X: emulated.ValueOf[emulated.Secp256k1Fp](res.X),
+		Y: emulated.ValueOf[emulated.Secp256k1Fp](res.Y),
+	}
+	for _, window := range []int{1, 2} {
+		err = test.IsSolved(newMultiScalarMulBucketsCircuit(nbLen, window), assignment, testCurve.ScalarField())
+		assert.NoError(err, "window %d", window)
+	}
+}
+
+func TestMultiScalarMulWindowedConstraints(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	assert := test.NewAssert(t)
+	const nbPoints = 2
+	windowed, err := frontend.Compile(testCurve.ScalarField(), scs.NewBuilder, newMultiScalarMulBucketsCircuit(nbPoints, 0))
+	assert.NoError(err)
+	// the bucket method costs n*(2^c-1)/c conditional additions per bit, so
+	// the window of one bit is the cheapest one (see BenchmarkMultiScalarMul
+	// for the larger windows).
+	buckets, err := frontend.Compile(testCurve.ScalarField(), scs.NewBuilder, newMultiScalarMulBucketsCircuit(nbPoints, 1))
+	assert.NoError(err)
+	assert.Less(windowed.GetNbConstraints(), buckets.GetNbConstraints())
+}
+
+var ccsBench constraint.ConstraintSystem
+
+func BenchmarkMultiScalarMul(b *testing.B) {
+	for _, nbPoints := range []int{2, 8} {
+		for _, window := range []int{0, 1, 2} {
+			name := fmt.Sprintf("points=%d/windowed", nbPoints)
+			if window != 0 {
+				name = fmt.Sprintf("points=%d/buckets=%d", nbPoints, window)
+			}
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					ccsBench, _ = frontend.Compile(testCurve.ScalarField(), scs.NewBuilder, newMultiScalarMulBucketsCircuit(nbPoints, window))
+				}
+				b.Log("plonk", ccsBench.GetNbConstraints())
+			})
+		}
+	}
+}
@@ -921,6 +921,10 @@ func (c *Curve[B, S]) JointScalarMulBase(p *AffinePoint[B], s2, s1 *emulated.Ele
 // scalars s. It returns an error if the length of the slices mismatch. If the
 // input slices are empty, then returns point at infinity.
 //
+// When the option [algopts.WithWindowedMultiScalarMul] is given, then the
+// points are aggregated using the windowed method with shared doublings. The
+// zero scalars are allowed in this case.
+//
 // ⚠️  Points and scalars must be nonzero.
 func (c *Curve[B, S]) MultiScalarMul(p []*AffinePoint[B], s []*emulated.Element[S], opts ...algopts.AlgebraOption) (*AffinePoint[B], error) {
 
@@ -934,6 +938,12 @@ func (c *Curve[B, S]) MultiScalarMul(p []*AffinePoint[B], s []*emulated.Element[
 	if err != nil {
 		return nil, fmt.Errorf("new config: %w", err)
 	}
+	if cfg.WindowedMulti {
+		if cfg.FoldMulti {
+			return nil, fmt.Errorf("windowed and folding multi scalar multiplication are exclusive")
+		}
+		return c.multiScalarMulWindowed(p, s, opts...)
+	}
 	if !cfg.FoldMulti {
 		// the scalars are unique
 		if len(p) != len(s) {
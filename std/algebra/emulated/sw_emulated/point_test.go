@@ -1091,3 +1091,219 @@ func TestJointScalarMul6(t *testing.T) {
 	err := test.IsSolved(&circuit, &witness, testCurve.ScalarField())
 	assert.NoError(err)
 }
+
+type MultiScalarMulWindowedTest[T, S emulated.FieldParams] struct {
+	Points  []AffinePoint[T]
+	Scalars []emulated.Element[S]
+	Res     AffinePoint[T]
+
+	window int
+}
+
+func (c *MultiScalarMulWindowedTest[T, S]) Define(api frontend.API) error {
+	cr, err := New[T, S](api, GetCurveParams[T]())
+	if err != nil {
+		return err
+	}
+	ps := make([]*AffinePoint[T], len(c.Points))
+	for i := range c.Points {
+		ps[i] = &c.Points[i]
+	}
+	ss := make([]*emulated.Element[S], len(c.Scalars))
+	for i := range c.Scalars {
+		ss[i] = &c.Scalars[i]
+	}
+	res, err := cr.MultiScalarMul(ps, ss, algopts.WithWindowedMultiScalarMul(c.window))
+	if err != nil {
+		return err
+	}
+	cr.AssertIsEqual(res, &c.Res)
+	return nil
+}
+
+func TestMultiScalarMulWindowed(t *testing.T) {
+	assert := test.NewAssert(t)
+	nbLen := 5
+	P := make([]secp256k1.G1Affine, nbLen)
+	S := make([]fr_secp.Element, nbLen)
+	_, g := secp256k1.Generators()
+	for i := 0; i < nbLen; i++ {
+		S[i].SetRandom()
+		P[i].ScalarMultiplication(&g, S[i].BigInt(new(big.Int)))
+	}
+	// zero scalars are allowed
+	S[1].SetZero()
+	P[2] = g
+	var res secp256k1.G1Affine
+	_, err := res.MultiExp(P, S, ecc.MultiExpConfig{})
+	assert.NoError(err)
+
+	cP := make([]AffinePoint[emulated.Secp256k1Fp], len(P))
+	for i := range cP {
+		cP[i] = AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](P[i].X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](P[i].Y),
+		}
+	}
+	cS := make([]emulated.Element[emulated.Secp256k1Fr], len(S))
+	for i := range cS {
+		cS[i] = emulated.ValueOf[emulated.Secp256k1Fr](S[i])
+	}
+	for _, window := range []int{0, 1, 3} {
+		assignment := MultiScalarMulWindowedTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			Points:  cP,
+			Scalars: cS,
+			Res: AffinePoint[emulated.Secp256k1Fp]{
+				X: emulated.ValueOf[emulated.Secp256k1Fp](res.X),
+				Y: emulated.ValueOf[emulated.Secp256k1Fp](res.Y),
+			},
+		}
+		err = test.IsSolved(&MultiScalarMulWindowedTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			Points:  make([]AffinePoint[emulated.Secp256k1Fp], nbLen),
+			Scalars: make([]emulated.Element[emulated.Secp256k1Fr], nbLen),
+			window:  window,
+		}, &assignment, testCurve.ScalarField())
+		assert.NoError(err, "window %d", window)
+	}
+}
+
+func TestMultiScalarMulWindowed2(t *testing.T) {
+	assert := test.NewAssert(t)
+	p256 := elliptic.P256()
+	nbLen := 3
+	cP := make([]AffinePoint[emulated.P256Fp], nbLen)
+	cS := make([]emulated.Element[emulated.P256Fr], nbLen)
+	var resX, resY *big.Int
+	for i := 0; i < nbLen; i++ {
+		k, err := rand.Int(rand.Reader, p256.Params().N)
+		assert.NoError(err)
+		s, err := rand.Int(rand.Reader, p256.Params().N)
+		assert.NoError(err)
+		px, py := p256.ScalarBaseMult(k.Bytes())
+		qx, qy := p256.ScalarMult(px, py, s.Bytes())
+		if i == 0 {
+			resX, resY = qx, qy
+		} else {
+			resX, resY = p256.Add(resX, resY, qx, qy)
+		}
+		cP[i] = AffinePoint[emulated.P256Fp]{
+			X: emulated.ValueOf[emulated.P256Fp](px),
+			Y: emulated.ValueOf[emulated.P256Fp](py),
+		}
+		cS[i] = emulated.ValueOf[emulated.P256Fr](s)
+	}
+	assignment := MultiScalarMulWindowedTest[emulated.P256Fp, emulated.P256Fr]{
+		Points:  cP,
+		Scalars: cS,
+		Res: AffinePoint[emulated.P256Fp]{
+			X: emulated.ValueOf[emulated.P256Fp](resX),
+			Y: emulated.ValueOf[emulated.P256Fp](resY),
+		},
+	}
+	err := test.IsSolved(&MultiScalarMulWindowedTest[emulated.P256Fp, emulated.P256Fr]{
+		Points:  make([]AffinePoint[emulated.P256Fp], nbLen),
+		Scalars: make([]emulated.Element[emulated.P256Fr], nbLen),
+		window:  4,
+	}, &assignment, testCurve.ScalarField())
+	assert.NoError(err)
+}
+
+func TestMultiScalarMulWindowedRepeated(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, g := secp256k1.Generators()
+	var g2, gNeg secp256k1.G1Affine
+	g2.Double(&g)
+	gNeg.Neg(&g)
+	for _, tc := range []struct {
+		P []secp256k1.G1Affine
+		S []int64
+	}{
+		// the points repeat and are opposite
+		{[]secp256k1.G1Affine{g, g, gNeg, g2, g}, []int64{1, 1, 3, 2, 0}},
+		// the result is the point at infinity
+		{[]secp256k1.G1Affine{g, gNeg}, []int64{5, 5}},
+	} {
+		var resJac, tmp secp256k1.G1Jac
+		for i := range tc.P {
+			tmp.FromAffine(&tc.P[i])
+			tmp.ScalarMultiplication(&tmp, big.NewInt(tc.S[i]))
+			resJac.AddAssign(&tmp)
+		}
+		var res secp256k1.G1Affine
+		res.FromJacobian(&resJac)
+		cP := make([]AffinePoint[emulated.Secp256k1Fp], len(tc.P))
+		cS := make([]emulated.Element[emulated.Secp256k1Fr], len(tc.S))
+		for i := range tc.P {
+			cP[i] = AffinePoint[emulated.Secp256k1Fp]{
+				X: emulated.ValueOf[emulated.Secp256k1Fp](tc.P[i].X),
+				Y: emulated.ValueOf[emulated.Secp256k1Fp](tc.P[i].Y),
+			}
+			cS[i] = emulated.ValueOf[emulated.Secp256k1Fr](tc.S[i])
+		}
+		for _, window := range []int{1, 2} {
+			assignment := MultiScalarMulWindowedTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+				Points:  cP,
+				Scalars: cS,
+				Res: AffinePoint[emulated.Secp256k1Fp]{
+					X: emulated.ValueOf[emulated.Secp256k1Fp](res.X),
+					Y: emulated.ValueOf[emulated.Secp256k1Fp](res.Y),
+				},
+			}
+			err := test.IsSolved(&MultiScalarMulWindowedTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+				Points:  make([]AffinePoint[emulated.Secp256k1Fp], len(cP)),
+				Scalars: make([]emulated.Element[emulated.Secp256k1Fr], len(cS)),
+				window:  window,
+			}, &assignment, testCurve.ScalarField())
+			assert.NoError(err, "window %d", window)
+		}
+	}
+}
+
+func TestMultiScalarMulWindowedOffsetCollision(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := GetSecp256k1Params()
+	// with the window size 1 and 256-bit scalars the offset is shifted 255 times
+	R, Rshifted := msmOffsetPoints[emulated.Secp256k1Fp](params, 255)
+	var r, rs, phiRs, p secp256k1.G1Affine
+	r.X.SetBigInt(R[0])
+	r.Y.SetBigInt(R[1])
+	rs.X.SetBigInt(Rshifted[0])
+	rs.Y.SetBigInt(Rshifted[1])
+	// φ(R') = (ωx, y) has the same y-coordinate as R'
+	var omega fp_secp.Element
+	omega.SetBigInt(params.ThirdRootOne)
+	phiRs.X.Mul(&rs.X, &omega)
+	phiRs.Y.Set(&rs.Y)
+	p.Sub(&phiRs, &rs)
+	toAffine := func(q secp256k1.G1Affine) AffinePoint[emulated.Secp256k1Fp] {
+		return AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](q.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](q.Y),
+		}
+	}
+	infinity := AffinePoint[emulated.Secp256k1Fp]{
+		X: emulated.ValueOf[emulated.Secp256k1Fp](0),
+		Y: emulated.ValueOf[emulated.Secp256k1Fp](0),
+	}
+	circuit := MultiScalarMulWindowedTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Points:  make([]AffinePoint[emulated.Secp256k1Fp], 1),
+		Scalars: make([]emulated.Element[emulated.Secp256k1Fr], 1),
+		window:  1,
+	}
+	one := []emulated.Element[emulated.Secp256k1Fr]{emulated.ValueOf[emulated.Secp256k1Fr](1)}
+	// the accumulator is φ(R') before subtracting the offset, the result is
+	// still correct
+	err := test.IsSolved(&circuit, &MultiScalarMulWindowedTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Points: []AffinePoint[emulated.Secp256k1Fp]{toAffine(p)}, Scalars: one, Res: toAffine(p),
+	}, testCurve.ScalarField())
+	assert.NoError(err)
+	err = test.IsSolved(&circuit, &MultiScalarMulWindowedTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Points: []AffinePoint[emulated.Secp256k1Fp]{toAffine(p)}, Scalars: one, Res: infinity,
+	}, testCurve.ScalarField())
+	assert.Error(err)
+	// the point is equal to the offset R, the slope would be 0/0
+	err = test.IsSolved(&circuit, &MultiScalarMulWindowedTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Points: []AffinePoint[emulated.Secp256k1Fp]{toAffine(r)}, Scalars: one, Res: toAffine(r),
+	}, testCurve.ScalarField())
+	assert.Error(err)
+}
@@ -1,3 +1,26 @@
 package evmprecompiles
 
-// will implement later
+import (
+	"fmt"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/hash/sha2"
+	"github.com/airchains-network/gnark/std/math/uints"
+)
+
+// SHA256 implements [SHA256] precompile contract at address 0x02.
+//
+// The input in is the call data padded to the maximal length, which is fixed at
+// circuit compile time. Only the first length bytes are hashed, so the same
+// circuit handles all the inputs up to len(in) bytes. The function returns the
+// 32-byte digest.
+//
+// [SHA256]: https://ethereum.github.io/execution-specs/autoapi/ethereum/paris/vm/precompiled_contracts/sha256/index.html
+func SHA256(api frontend.API, in []uints.U8, length frontend.Variable) []uints.U8 {
+	h, err := sha2.New(api)
+	if err != nil {
+		panic(fmt.Sprintf("new sha2: %v", err))
+	}
+	h.Write(in)
+	return h.FixedLengthSum(length)
+}
@@ -0,0 +1,42 @@
+package evmprecompiles
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+type sha256Circuit struct {
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected [32]uints.U8
+}
+
+func (c *sha256Circuit) Define(api frontend.API) error {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	res := SHA256(api, c.In, c.Length)
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestSHA256(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := []byte("The quick brown fox jumps over the lazy dog, twice: the quick brown fox jumps over the lazy dog")
+	// lengths spanning one and two blocks of padded input
+	for _, length := range []int{0, 1, 43, 55, 56, 63, 64, len(in)} {
+		dgst := sha256.Sum256(in[:length])
+		witness := sha256Circuit{In: uints.NewU8Array(in), Length: length}
+		copy(witness.Expected[:], uints.NewU8Array(dgst[:]))
+		err := test.IsSolved(&sha256Circuit{In: make([]uints.U8, len(in))}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "length %d", length)
+	}
+}
@@ -0,0 +1,26 @@
+package evmprecompiles
+
+import (
+	"fmt"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/hash/ripemd160"
+	"github.com/airchains-network/gnark/std/math/uints"
+)
+
+// RIPEMD160 implements [RIPEMD160] precompile contract at address 0x03.
+//
+// The length of the input is fixed at circuit compile time, but may be
+// arbitrary. The function returns the 20-byte digest. The EVM left-pads the
+// digest with 12 zero bytes to 32 bytes, it is up to the caller to do so if
+// necessary.
+//
+// [RIPEMD160]: https://ethereum.github.io/execution-specs/autoapi/ethereum/paris/vm/precompiled_contracts/ripemd160/index.html
+func RIPEMD160(api frontend.API, in []uints.U8) []uints.U8 {
+	h, err := ripemd160.New(api)
+	if err != nil {
+		panic(fmt.Sprintf("new ripemd160: %v", err))
+	}
+	h.Write(in)
+	return h.Sum()
+}
@@ -0,0 +1,40 @@
+package evmprecompiles
+
+import (
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // used only as reference implementation
+)
+
+type ripemd160Circuit struct {
+	In       []uints.U8
+	Expected [20]uints.U8
+}
+
+func (c *ripemd160Circuit) Define(api frontend.API) error {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	res := RIPEMD160(api, c.In)
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestRIPEMD160(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := []byte("The quick brown fox jumps over the lazy dog")
+	h := ripemd160.New()
+	h.Write(in)
+	dgst := h.Sum(nil)
+	witness := ripemd160Circuit{In: uints.NewU8Array(in)}
+	copy(witness.Expected[:], uints.NewU8Array(dgst))
+	err := test.IsSolved(&ripemd160Circuit{In: make([]uints.U8, len(in))}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
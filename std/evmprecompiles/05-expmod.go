@@ -1 +1,42 @@
 package evmprecompiles
+
+import (
+	"fmt"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/emulated"
+)
+
+// Expmod implements [MODEXP] precompile contract at address 0x05.
+//
+// The maximum sizes of the base, exponent and modulus are bounded by the type
+// parameter P, e.g. [emulated.Mod1e4096] allows for up to 4096-bit inputs. The
+// circuit size depends on the type parameter P and not on the actual sizes of
+// the inputs. For smaller inputs use smaller parametrization, e.g.
+// [emulated.Mod1e512] instead of [emulated.Mod1e4096].
+//
+// The returned result is the canonical representative modulo modulus. If the
+// modulus is zero, then the result is zero.
+//
+// [MODEXP]: https://ethereum.github.io/execution-specs/autoapi/ethereum/paris/vm/precompiled_contracts/expmod/index.html
+func Expmod[P emulated.FieldParams](api frontend.API, base, exp, modulus *emulated.Element[P]) *emulated.Element[P] {
+	f, err := emulated.NewField[P](api)
+	if err != nil {
+		panic(fmt.Sprintf("new field: %v", err))
+	}
+	// x mod 0 = 0. In case modulus is zero, then compute with dummy modulus 1
+	// and return zero as a result.
+	modBits := f.ToBits(modulus)
+	isZeroMod := api.IsZero(api.Add(0, 0, modBits...))
+	mod := f.Select(isZeroMod, f.One(), f.FromBits(modBits...))
+	res := f.ModExp(base, exp, mod)
+	// the result is only congruent to the expected value, ensure that it is
+	// strictly less than the modulus.
+	f.AssertIsLessOrEqual(res, mod)
+	var isEqual frontend.Variable = 1
+	for i := range res.Limbs {
+		isEqual = api.Mul(isEqual, api.IsZero(api.Sub(res.Limbs[i], mod.Limbs[i])))
+	}
+	api.AssertIsEqual(isEqual, 0)
+	return f.Select(isZeroMod, f.Zero(), res)
+}
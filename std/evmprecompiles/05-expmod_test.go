@@ -0,0 +1,84 @@
+package evmprecompiles
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/emulated"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+type expmodCircuit struct {
+	Base     emulated.Element[emulated.Mod1e512]
+	Exp      emulated.Element[emulated.Mod1e512]
+	Mod      emulated.Element[emulated.Mod1e512]
+	Expected emulated.Element[emulated.Mod1e512]
+}
+
+func (c *expmodCircuit) Define(api frontend.API) error {
+	f, err := emulated.NewField[emulated.Mod1e512](api)
+	if err != nil {
+		return err
+	}
+	res := Expmod(api, &c.Base, &c.Exp, &c.Mod)
+	f.AssertLimbsEquality(res, &c.Expected)
+	return nil
+}
+
+func testInstance(base, exp, modulus, result *big.Int) error {
+	circuit := &expmodCircuit{}
+	assignment := &expmodCircuit{
+		Base:     emulated.ValueOf[emulated.Mod1e512](base),
+		Exp:      emulated.ValueOf[emulated.Mod1e512](exp),
+		Mod:      emulated.ValueOf[emulated.Mod1e512](modulus),
+		Expected: emulated.ValueOf[emulated.Mod1e512](result),
+	}
+	return test.IsSolved(circuit, assignment, ecc.BN254.ScalarField())
+}
+
+func TestRandomInstance(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, bits := range []int{256, 512} {
+		bits := bits
+		assert.Run(func(assert *test.Assert) {
+			modulus := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+			base, _ := rand.Int(rand.Reader, modulus)
+			exp, _ := rand.Int(rand.Reader, modulus)
+			modulus.Sub(modulus, big.NewInt(1))
+			res := new(big.Int).Exp(base, exp, modulus)
+			err := testInstance(base, exp, modulus, res)
+			assert.NoError(err)
+		}, fmt.Sprintf("random-%d", bits))
+	}
+}
+
+func TestEdgeCases(t *testing.T) {
+	assert := test.NewAssert(t)
+	// EIP-198 example: Fermat's little theorem for the secp256k1 base field.
+	p, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	pm1 := new(big.Int).Sub(p, big.NewInt(1))
+	testCases := []struct {
+		base, exp, modulus, result *big.Int
+	}{
+		{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)},   // 0^0 mod 0 = 0
+		{big.NewInt(0), big.NewInt(0), big.NewInt(1), big.NewInt(0)},   // 0^0 mod 1 = 0
+		{big.NewInt(0), big.NewInt(0), big.NewInt(2), big.NewInt(1)},   // 0^0 mod 2 = 1
+		{big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(0)},   // 0^1 mod 2 = 0
+		{big.NewInt(3), big.NewInt(5), big.NewInt(0), big.NewInt(0)},   // 3^5 mod 0 = 0
+		{big.NewInt(10), big.NewInt(1), big.NewInt(7), big.NewInt(3)},  // 10^1 mod 7 = 3
+		{big.NewInt(3), pm1, p, big.NewInt(1)},                         // 3^(p-1) mod p = 1
+		{big.NewInt(10), big.NewInt(1), big.NewInt(7), big.NewInt(10)}, // wrong result
+	}
+	for i, tc := range testCases {
+		err := testInstance(tc.base, tc.exp, tc.modulus, tc.result)
+		if i < len(testCases)-1 {
+			assert.NoError(err, "case %d", i)
+		} else {
+			assert.Error(err, "case %d", i)
+		}
+	}
+}
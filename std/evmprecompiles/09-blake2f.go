@@ -0,0 +1,24 @@
+package evmprecompiles
+
+import (
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/std/permutation/blake2"
+)
+
+// BLAKE2F implements [BLAKE2F] precompile contract at address 0x09.
+//
+// The inputs are the state vector h, the message block vector m, the offset
+// counters t and the final block indicator flag final, all given as
+// little-endian words. The flag final must be boolean. The number of rounds
+// is fixed at circuit compile time as the circuit size depends linearly on it.
+// The function returns the new state vector.
+//
+// [BLAKE2F]: https://eips.ethereum.org/EIPS/eip-152
+func BLAKE2F(api frontend.API, rounds int, h [8]uints.U64, m [16]uints.U64, t [2]uints.U64, final frontend.Variable) [8]uints.U64 {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		panic(err)
+	}
+	return blake2.CompressBlake2b(api, uapi, rounds, h, m, t, final)
+}
@@ -0,0 +1,74 @@
+package evmprecompiles
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+type blake2fCircuit struct {
+	H        [8]uints.U64
+	M        [16]uints.U64
+	T        [2]uints.U64
+	F        frontend.Variable
+	Expected [8]uints.U64
+
+	rounds int
+}
+
+func (c *blake2fCircuit) Define(api frontend.API) error {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+	res := BLAKE2F(api, c.rounds, c.H, c.M, c.T, c.F)
+	for i := range res {
+		uapi.AssertEq(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+func parseBlake2fWords(in []byte, out []uints.U64) {
+	for i := range out {
+		out[i] = uints.NewU64(binary.LittleEndian.Uint64(in[8*i : 8*(i+1)]))
+	}
+}
+
+func TestBLAKE2F(t *testing.T) {
+	assert := test.NewAssert(t)
+	// test vectors 5-7 from EIP-152. All use the same state, message and
+	// offset counter.
+	h, _ := hex.DecodeString("48c9bdf267e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d182e6ad7f520e511f6c3e2b8c68059b6bbd41fbabd9831f79217e1319cde05b")
+	m := make([]byte, 128)
+	copy(m, "abc")
+	tt := make([]byte, 16)
+	tt[0] = 3
+	testCases := []struct {
+		rounds int
+		final  int
+		output string
+	}{
+		{12, 1, "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+		{12, 0, "75ab69d3190a562c51aef8d88f1c2775876944407270c42c9844252c26d2875298743e7f6d5ea2f2d3e8d226039cd31b4e426ac4f2d3d666a610c2116fde4735"},
+		{1, 1, "b63a380cb2897d521994a85234ee2c181b5f844d2c624c002677e9703449d2fba551b3a8333bcdf5f2f7e08993d53923de3d64fcc68c034e717b9293fed7a421"},
+	}
+	for _, tc := range testCases {
+		output, err := hex.DecodeString(tc.output)
+		assert.NoError(err)
+
+		var witness blake2fCircuit
+		parseBlake2fWords(h, witness.H[:])
+		parseBlake2fWords(m, witness.M[:])
+		parseBlake2fWords(tt, witness.T[:])
+		witness.F = tc.final
+		parseBlake2fWords(output, witness.Expected[:])
+
+		err = test.IsSolved(&blake2fCircuit{rounds: tc.rounds}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+}
@@ -0,0 +1,162 @@
+package evmprecompiles
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/airchains-network/gnark/std/commitments/kzg"
+	"github.com/airchains-network/gnark/std/hash/sha2"
+	"github.com/airchains-network/gnark/std/math/emulated"
+	"github.com/airchains-network/gnark/std/math/uints"
+)
+
+// KZGVersionedHashVersion is the version byte of the versioned hash of a KZG
+// commitment as defined in [EIP-4844].
+//
+// [EIP-4844]: https://eips.ethereum.org/EIPS/eip-4844
+const KZGVersionedHashVersion = 0x01
+
+// KZGPointEvaluation implements [KZG_POINT_EVALUATION] precompile contract at
+// address 0x0a.
+//
+// The function asserts that versionedHash corresponds to the commitment and
+// that proof is a valid opening proof of the commitment at point z to the
+// value y. Both z and y must be canonical scalars. The commitment and proof
+// are given as uncompressed points and are asserted to be in G1. As in
+// EIP-4844, the commitment and the proof may be the point at infinity, which is
+// represented as (0,0) and compressed as 0xc0 followed by zeros.
+//
+// The verifying key vk corresponds to the KZG trusted setup used by Ethereum.
+// The precompile returns constant values (number of field elements per blob
+// and the scalar field modulus) which are not returned by the function.
+//
+// [KZG_POINT_EVALUATION]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+func KZGPointEvaluation(api frontend.API,
+	versionedHash [32]uints.U8,
+	z, y *emulated.Element[sw_bls12381.ScalarField],
+	commitment, proof *sw_bls12381.G1Affine,
+	vk kzg.VerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine]) {
+	fp, err := emulated.NewField[sw_bls12381.BaseField](api)
+	if err != nil {
+		panic(fmt.Sprintf("new base field: %v", err))
+	}
+	fr, err := emulated.NewField[sw_bls12381.ScalarField](api)
+	if err != nil {
+		panic(fmt.Sprintf("new scalar field: %v", err))
+	}
+	curve, err := sw_emulated.New[sw_bls12381.BaseField, sw_bls12381.ScalarField](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		panic(fmt.Sprintf("new curve: %v", err))
+	}
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		panic(fmt.Sprintf("new pairing: %v", err))
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		panic(fmt.Sprintf("new uints: %v", err))
+	}
+	h, err := sha2.New(api)
+	if err != nil {
+		panic(fmt.Sprintf("new sha2: %v", err))
+	}
+
+	// versioned hash is the SHA256 hash of the compressed commitment with the
+	// first byte replaced by the version.
+	h.Write(compressG1(api, fp, commitment))
+	dgst := h.Sum()
+	uapi.ByteAssertEq(versionedHash[0], uints.NewU8(KZGVersionedHashVersion))
+	for i := 1; i < len(versionedHash); i++ {
+		uapi.ByteAssertEq(versionedHash[i], dgst[i])
+	}
+
+	fr.AssertIsInRange(z)
+	fr.AssertIsInRange(y)
+
+	// the subgroup check and the scalar multiplications don't handle the
+	// point at infinity, so we use the generator instead and select the
+	// infinity back afterwards.
+	g := curve.Generator()
+	isInfC := api.And(fp.IsZero(&commitment.X), fp.IsZero(&commitment.Y))
+	isInfP := api.And(fp.IsZero(&proof.X), fp.IsZero(&proof.Y))
+	pairing.AssertIsOnG1(curve.Select(isInfC, g, commitment))
+	pairing.AssertIsOnG1(curve.Select(isInfP, g, proof))
+
+	// the opening proof is valid if
+	//
+	//	e([f(α) - f(z) + z*H(α)]G₁, G₂) · e([-H(α)]G₁, [α]G₂) == 1
+	//
+	// where [f(α)]G₁ is the commitment and [H(α)]G₁ the proof.
+	zero := fp.Zero()
+	infinity := &sw_bls12381.G1Affine{X: *zero, Y: *zero}
+	isZeroZ := fr.IsZero(z)
+	zNonZero := fr.Select(isZeroZ, fr.One(), z)
+	zH := curve.ScalarMul(curve.Select(isInfP, g, proof), zNonZero)
+	zH = curve.Select(api.Or(isInfP, isZeroZ), infinity, zH)
+	totalG1 := curve.AddUnified(commitment, curve.Neg(curve.ScalarMulBase(y)))
+	totalG1 = curve.AddUnified(totalG1, zH)
+	negH := curve.Neg(proof)
+
+	// the pairing check doesn't handle the point at infinity either. As the
+	// pairing is non-degenerate, the check holds with one side at infinity only
+	// if the other side is at infinity too. Then the check holds trivially.
+	isInfTotal := api.And(fp.IsZero(&totalG1.X), fp.IsZero(&totalG1.Y))
+	api.AssertIsEqual(isInfTotal, isInfP)
+	res, err := pairing.Pair(
+		[]*sw_bls12381.G1Affine{curve.Select(isInfTotal, g, totalG1), curve.Select(isInfP, g, negH)},
+		[]*sw_bls12381.G2Affine{&vk.G2[0], &vk.G2[1]},
+	)
+	if err != nil {
+		panic(fmt.Sprintf("pair: %v", err))
+	}
+	one := pairing.Ext12.One()
+	pairing.AssertIsEqual(pairing.Ext12.Select(isInfP, one, res), one)
+}
+
+// compressG1 returns the 48-byte compressed encoding of a point p as defined in
+// the [ZCash serialization format]. The bytes are the big-endian
+// representation of the x coordinate with the three most significant bits
+// used as flags. The compression flag is set, the infinity flag is set if p is
+// (0,0) and the sign flag is set if y is lexicographically largest, i.e.
+// y > (q-1)/2. For the point at infinity, the encoding is 0xc0 followed by
+// zeros.
+//
+// [ZCash serialization format]: https://github.com/zkcrypto/pairing/blob/master/src/bls12_381/README.md#serialization
+func compressG1(api frontend.API, fp *emulated.Field[sw_bls12381.BaseField], p *sw_bls12381.G1Affine) []uints.U8 {
+	fp.AssertIsInRange(&p.X)
+	fp.AssertIsInRange(&p.Y)
+	xBits := fp.ToBits(&p.X)
+	yBits := fp.ToBits(&p.Y)
+
+	// compare y against constant (q-1)/2 starting from the most significant
+	// bit. isGreater is set at the first bit where y has 1 and the constant 0.
+	var q sw_bls12381.BaseField
+	half := new(big.Int).Sub(q.Modulus(), big.NewInt(1))
+	half.Rsh(half, 1)
+	var isGreater, isEqual frontend.Variable = 0, 1
+	for i := len(yBits) - 1; i >= 0; i-- {
+		if half.Bit(i) == 0 {
+			isGreater = api.Add(isGreater, api.Mul(isEqual, yBits[i]))
+			isEqual = api.Mul(isEqual, api.Sub(1, yBits[i]))
+		} else {
+			isEqual = api.Mul(isEqual, yBits[i])
+		}
+	}
+
+	const nbBytes = 48
+	res := make([]uints.U8, nbBytes)
+	for i := range res {
+		// the bits are little-endian, but the bytes big-endian
+		lo := 8 * (nbBytes - 1 - i)
+		res[i] = uints.U8{Val: api.FromBinary(xBits[lo : lo+8]...)}
+	}
+	// the x coordinate is less than 2^381, so the flag bits are zero after
+	// range checking. For the point at infinity, x and y are zero, so only the
+	// compression and infinity flags are set.
+	isInf := api.And(fp.IsZero(&p.X), fp.IsZero(&p.Y))
+	res[0].Val = api.Add(res[0].Val, 0x80, api.Mul(isInf, 0x40), api.Mul(isGreater, 0x20))
+	return res
+}
@@ -0,0 +1,158 @@
+package evmprecompiles
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/airchains-network/gnark/std/commitments/kzg"
+	"github.com/airchains-network/gnark/std/math/emulated"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+)
+
+type kzgPointEvalCircuit struct {
+	VersionedHash [32]uints.U8
+	Z, Y          emulated.Element[sw_bls12381.ScalarField]
+	Commitment    sw_bls12381.G1Affine
+	Proof         sw_bls12381.G1Affine
+	Vk            kzg.VerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine]
+}
+
+func (c *kzgPointEvalCircuit) Define(api frontend.API) error {
+	KZGPointEvaluation(api, c.VersionedHash, &c.Z, &c.Y, &c.Commitment, &c.Proof, c.Vk)
+	return nil
+}
+
+func kzgPointEvalWitness(assert *test.Assert, srs *kzg_bls12381.SRS, f []fr_bls12381.Element, z fr_bls12381.Element) kzgPointEvalCircuit {
+	com, err := kzg_bls12381.Commit(f, srs.Pk)
+	assert.NoError(err)
+	proof, err := kzg_bls12381.Open(f, z, srs.Pk)
+	assert.NoError(err)
+
+	compressed := com.Bytes()
+	versionedHash := sha256.Sum256(compressed[:])
+	versionedHash[0] = KZGVersionedHashVersion
+
+	wVk, err := kzg.ValueOfVerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine](srs.Vk)
+	assert.NoError(err)
+	witness := kzgPointEvalCircuit{
+		Z:          sw_bls12381.NewScalar(z),
+		Y:          sw_bls12381.NewScalar(proof.ClaimedValue),
+		Commitment: sw_bls12381.NewG1Affine(com),
+		Proof:      sw_bls12381.NewG1Affine(proof.H),
+		Vk:         wVk,
+	}
+	copy(witness.VersionedHash[:], uints.NewU8Array(versionedHash[:]))
+	return witness
+}
+
+func TestKZGPointEvaluation(t *testing.T) {
+	assert := test.NewAssert(t)
+	alpha, err := rand.Int(rand.Reader, ecc.BLS12_381.ScalarField())
+	assert.NoError(err)
+	srs, err := kzg_bls12381.NewSRS(16, alpha)
+	assert.NoError(err)
+	f := make([]fr_bls12381.Element, 10)
+	for i := range f {
+		f[i].SetRandom()
+	}
+	var z fr_bls12381.Element
+	z.SetRandom()
+	witness := kzgPointEvalWitness(assert, srs, f, z)
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// wrong versioned hash
+	witness.VersionedHash[1] = uints.NewU8(witness.VersionedHash[1].Val.(uint8) ^ 1)
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// opening at zero
+	witness = kzgPointEvalWitness(assert, srs, f, fr_bls12381.Element{})
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestKZGPointEvaluationInfinity(t *testing.T) {
+	assert := test.NewAssert(t)
+	alpha, err := rand.Int(rand.Reader, ecc.BLS12_381.ScalarField())
+	assert.NoError(err)
+	srs, err := kzg_bls12381.NewSRS(16, alpha)
+	assert.NoError(err)
+	var z fr_bls12381.Element
+	z.SetRandom()
+
+	// zero polynomial: commitment and proof at infinity
+	zero := make([]fr_bls12381.Element, 2)
+	witness := kzgPointEvalWitness(assert, srs, zero, z)
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// constant polynomial: proof at infinity
+	constant := make([]fr_bls12381.Element, 2)
+	constant[0].SetRandom()
+	witness = kzgPointEvalWitness(assert, srs, constant, z)
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the proof at infinity doesn't open the commitment to another value
+	witness.Y = sw_bls12381.NewScalar(fr_bls12381.NewElement(1))
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// polynomial X-α: commitment at infinity
+	var a fr_bls12381.Element
+	a.SetBigInt(alpha)
+	vanishing := make([]fr_bls12381.Element, 2)
+	vanishing[0].Neg(&a)
+	vanishing[1].SetOne()
+	witness = kzgPointEvalWitness(assert, srs, vanishing, z)
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type compressG1Circuit struct {
+	P        sw_bls12381.G1Affine
+	Expected [48]uints.U8
+}
+
+func (c *compressG1Circuit) Define(api frontend.API) error {
+	fp, err := emulated.NewField[sw_bls12381.BaseField](api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	res := compressG1(api, fp, &c.P)
+	for i := range c.Expected {
+		uapi.ByteAssertEq(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestCompressG1(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, _, g1, _ := bls12381.Generators()
+	var s fr_bls12381.Element
+	s.SetRandom()
+	var p bls12381.G1Affine
+	p.ScalarMultiplication(&g1, s.BigInt(new(big.Int)))
+	// check both signs of y and the point at infinity
+	for _, pp := range []bls12381.G1Affine{p, *new(bls12381.G1Affine).Neg(&p), {}} {
+		expected := pp.Bytes()
+		witness := compressG1Circuit{P: sw_bls12381.NewG1Affine(pp)}
+		copy(witness.Expected[:], uints.NewU8Array(expected[:]))
+		err := test.IsSolved(&compressG1Circuit{}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+}
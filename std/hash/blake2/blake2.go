@@ -0,0 +1,128 @@
+// Package blake2 implements BLAKE2b and BLAKE2s hash computation.
+//
+// This package extends the BLAKE2 compression functions [blake2] into full
+// unkeyed hash functions as defined in [RFC 7693]. Instances correspond to
+// golang.org/x/crypto/blake2b and golang.org/x/crypto/blake2s with nil key.
+//
+// [RFC 7693]: https://www.rfc-editor.org/rfc/rfc7693
+package blake2
+
+import (
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/hash"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/std/permutation/blake2"
+)
+
+type digest[T uints.Long] struct {
+	uapi      *uints.BinaryField[T]
+	in        []uints.U8
+	iv        []uint64
+	blockSize int
+	outputLen int
+	compress  func(h [8]T, m [16]T, t [2]T, final frontend.Variable) [8]T
+}
+
+// NewBlake2b512 creates a new BLAKE2b-512 hash.
+func NewBlake2b512(api frontend.API) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, err
+	}
+	return &digest[uints.U64]{
+		uapi:      uapi,
+		iv:        blake2.IV2b[:],
+		blockSize: 128,
+		outputLen: 64,
+		compress: func(h [8]uints.U64, m [16]uints.U64, t [2]uints.U64, final frontend.Variable) [8]uints.U64 {
+			return blake2.CompressBlake2b(api, uapi, blake2.Blake2bRounds, h, m, t, final)
+		},
+	}, nil
+}
+
+// NewBlake2s256 creates a new BLAKE2s-256 hash.
+func NewBlake2s256(api frontend.API) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]uint64, len(blake2.IV2s))
+	for i := range iv {
+		iv[i] = uint64(blake2.IV2s[i])
+	}
+	return &digest[uints.U32]{
+		uapi:      uapi,
+		iv:        iv,
+		blockSize: 64,
+		outputLen: 32,
+		compress: func(h [8]uints.U32, m [16]uints.U32, t [2]uints.U32, final frontend.Variable) [8]uints.U32 {
+			return blake2.CompressBlake2s(api, uapi, blake2.Blake2sRounds, h, m, t, final)
+		},
+	}, nil
+}
+
+func (d *digest[T]) Write(in []uints.U8) {
+	d.in = append(d.in, in...)
+}
+
+func (d *digest[T]) Size() int { return d.outputLen }
+
+func (d *digest[T]) Reset() {
+	d.in = nil
+}
+
+func (d *digest[T]) Sum() []uints.U8 {
+	// parameter block for unkeyed hashing with sequential mode, only the
+	// digest length is set.
+	var h [8]T
+	for i := range h {
+		v := d.iv[i]
+		if i == 0 {
+			v ^= 0x01010000 ^ uint64(d.outputLen)
+		}
+		h[i] = newWord[T](v)
+	}
+
+	nbBlocks := (len(d.in) + d.blockSize - 1) / d.blockSize
+	if nbBlocks == 0 {
+		// empty input is hashed as a single zero block.
+		nbBlocks = 1
+	}
+	wordLen := len(h[0])
+	for i := 0; i < nbBlocks; i++ {
+		block := make([]uints.U8, d.blockSize)
+		for j := range block {
+			if i*d.blockSize+j < len(d.in) {
+				block[j] = d.in[i*d.blockSize+j]
+			} else {
+				block[j] = uints.NewU8(0)
+			}
+		}
+		var m [16]T
+		for j := range m {
+			m[j] = d.uapi.PackLSB(block[j*wordLen : (j+1)*wordLen]...)
+		}
+		final, counter := 0, uint64((i+1)*d.blockSize)
+		if i == nbBlocks-1 {
+			final, counter = 1, uint64(len(d.in))
+		}
+		// the counter is 2 words long, but we do not support inputs which do
+		// not fit into a single word.
+		t := [2]T{newWord[T](counter), newWord[T](0)}
+		h = d.compress(h, m, t, final)
+	}
+
+	var ret []uints.U8
+	for i := range h {
+		ret = append(ret, d.uapi.UnpackLSB(h[i])...)
+	}
+	return ret[:d.outputLen]
+}
+
+func newWord[T uints.Long](v uint64) T {
+	var r T
+	for i := 0; i < len(r); i++ {
+		r[i] = uints.NewU8(uint8(v >> (8 * i)))
+	}
+	return r
+}
@@ -0,0 +1,89 @@
+package blake2
+
+import (
+	"crypto/rand"
+	"fmt"
+	"hash"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	zkhash "github.com/airchains-network/gnark/std/hash"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+)
+
+type testCase struct {
+	zk     func(api frontend.API) (zkhash.BinaryHasher, error)
+	native func() hash.Hash
+}
+
+var testCases = map[string]testCase{
+	"BLAKE2b-512": {NewBlake2b512, func() hash.Hash { h, _ := blake2b.New512(nil); return h }},
+	"BLAKE2s-256": {NewBlake2s256, func() hash.Hash { h, _ := blake2s.New256(nil); return h }},
+}
+
+type blake2Circuit struct {
+	In       []uints.U8
+	Expected []uints.U8
+
+	hasher string
+}
+
+func (c *blake2Circuit) Define(api frontend.API) error {
+	newHasher, ok := testCases[c.hasher]
+	if !ok {
+		return fmt.Errorf("hash function unknown: %s", c.hasher)
+	}
+	h, err := newHasher.zk(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+
+	h.Write(c.In)
+	res := h.Sum()
+	if len(res) != len(c.Expected) {
+		return fmt.Errorf("digest length mismatch")
+	}
+
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestBLAKE2(t *testing.T) {
+	assert := test.NewAssert(t)
+	for name := range testCases {
+		for _, length := range []int{0, 3, 64, 128, 200} {
+			name, length := name, length
+			assert.Run(func(assert *test.Assert) {
+				in := make([]byte, length)
+				_, err := rand.Reader.Read(in)
+				assert.NoError(err)
+
+				h := testCases[name].native()
+				h.Write(in)
+				expected := h.Sum(nil)
+
+				circuit := &blake2Circuit{
+					In:       make([]uints.U8, len(in)),
+					Expected: make([]uints.U8, len(expected)),
+					hasher:   name,
+				}
+				witness := &blake2Circuit{
+					In:       uints.NewU8Array(in),
+					Expected: uints.NewU8Array(expected),
+				}
+				err = test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+				assert.NoError(err)
+			}, name, fmt.Sprintf("length=%d", length))
+		}
+	}
+}
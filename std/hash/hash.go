@@ -19,10 +19,13 @@ package hash
 
 import (
 	"errors"
+	stdhash "hash"
+	"sort"
 	"sync"
 
 	"github.com/airchains-network/gnark/frontend"
 	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/consensys/gnark-crypto/ecc"
 )
 
 // FieldHasher hashes inputs into a short digest. This interface mocks
@@ -41,8 +44,9 @@ type FieldHasher interface {
 }
 
 var (
-	builderRegistry = make(map[string]func(api frontend.API) (FieldHasher, error))
-	lock            sync.RWMutex
+	builderRegistry       = make(map[string]func(api frontend.API) (FieldHasher, error))
+	nativeBuilderRegistry = make(map[string]func(curve ecc.ID) (stdhash.Hash, error))
+	lock                  sync.RWMutex
 )
 
 func Register(name string, builder func(api frontend.API) (FieldHasher, error)) {
@@ -61,6 +65,40 @@ func GetFieldHasher(name string, api frontend.API) (FieldHasher, error) {
 	return builder(api)
 }
 
+// RegisterNative registers the out-of-circuit counterpart of the hash function
+// registered using [Register] under the same name. The native hash function
+// must compute the same digest as the in-circuit one when the written data is
+// the concatenation of the big-endian encodings of the field elements.
+func RegisterNative(name string, builder func(curve ecc.ID) (stdhash.Hash, error)) {
+	lock.Lock()
+	defer lock.Unlock()
+	nativeBuilderRegistry[name] = builder
+}
+
+// GetNativeHasher returns the out-of-circuit counterpart of the field hasher
+// registered under name for the scalar field of the given curve.
+func GetNativeHasher(name string, curve ecc.ID) (stdhash.Hash, error) {
+	lock.RLock()
+	defer lock.RUnlock()
+	builder, ok := nativeBuilderRegistry[name]
+	if !ok {
+		return nil, errors.New("native hash function not found")
+	}
+	return builder(curve)
+}
+
+// ListFieldHashers returns the sorted names of the registered field hashers.
+func ListFieldHashers() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+	names := make([]string, 0, len(builderRegistry))
+	for name := range builderRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // BinaryHasher hashes inputs into a short digest. It takes as inputs bytes and
 // outputs byte array whose length depends on the underlying hash function. For
 // SNARK-native hash functions use [FieldHasher].
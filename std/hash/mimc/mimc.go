@@ -18,57 +18,30 @@ limitations under the License.
 package mimc
 
 import (
-	"errors"
-	"math/big"
-
-	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/airchains-network/gnark/frontend"
-	"github.com/airchains-network/gnark/internal/utils"
+	"github.com/airchains-network/gnark/std/hash"
+	"github.com/airchains-network/gnark/std/internal/mimc"
 )
 
-// MiMC contains the params of the Mimc hash func and the curves on which it is implemented
-type MiMC struct {
-	params []big.Int           // slice containing constants for the encryption rounds
-	id     ecc.ID              // id needed to know which encryption function to use
-	h      frontend.Variable   // current vector in the Miyaguchi–Preneel scheme
-	data   []frontend.Variable // state storage. data is updated when Write() is called. Sum sums the data.
-	api    frontend.API        // underlying constraint system
+// Name is the name under which the hasher is registered in the hash registry
+// of package [github.com/airchains-network/gnark/std/hash].
+const Name = "mimc"
+
+func init() {
+	hash.Register(Name, func(api frontend.API) (hash.FieldHasher, error) {
+		h, err := NewMiMC(api)
+		if err != nil {
+			return nil, err
+		}
+		return &h, nil
+	})
+	hash.RegisterNative(Name, NewHasher)
 }
 
+// MiMC contains the params of the Mimc hash func and the curves on which it is implemented
+type MiMC = mimc.MiMC
+
 // NewMiMC returns a MiMC instance, that can be used in a gnark circuit
 func NewMiMC(api frontend.API) (MiMC, error) {
-	// TODO @gbotrel use field
-	if constructor, ok := newMimc[utils.FieldToCurve(api.Compiler().Field())]; ok {
-		return constructor(api), nil
-	}
-	return MiMC{}, errors.New("unknown curve id")
-}
-
-// Write adds more data to the running hash.
-func (h *MiMC) Write(data ...frontend.Variable) {
-	h.data = append(h.data, data...)
-}
-
-// Reset resets the Hash to its initial state.
-func (h *MiMC) Reset() {
-	h.data = nil
-	h.h = 0
-}
-
-// Sum hash (in r1cs form) using Miyaguchi–Preneel:
-// https://en.wikipedia.org/wiki/One-way_compression_function
-// The XOR operation is replaced by field addition.
-// See github.com/consensys/gnark-crypto for reference implementation.
-func (h *MiMC) Sum() frontend.Variable {
-
-	//h.Write(data...)s
-	for _, stream := range h.data {
-		r := encryptFuncs[h.id](*h, stream)
-		h.h = h.api.Add(h.h, r, stream)
-	}
-
-	h.data = nil // flush the data already hashed
-
-	return h.h
-
+	return mimc.NewMiMC(api)
 }
@@ -0,0 +1,36 @@
+package mimc
+
+import (
+	"fmt"
+	stdhash "hash"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+)
+
+// NewHasher returns the out-of-circuit MiMC hash function over the scalar
+// field of the given curve, as implemented in gnark-crypto. It computes the
+// same digest as the in-circuit hasher returned by [NewMiMC] when the written
+// data is the concatenation of the big-endian encodings of the field elements.
+func NewHasher(curve ecc.ID) (stdhash.Hash, error) {
+	var h hash.Hash
+	switch curve {
+	case ecc.BN254:
+		h = hash.MIMC_BN254
+	case ecc.BLS12_381:
+		h = hash.MIMC_BLS12_381
+	case ecc.BLS12_377:
+		h = hash.MIMC_BLS12_377
+	case ecc.BW6_761:
+		h = hash.MIMC_BW6_761
+	case ecc.BW6_633:
+		h = hash.MIMC_BW6_633
+	case ecc.BLS24_315:
+		h = hash.MIMC_BLS24_315
+	case ecc.BLS24_317:
+		h = hash.MIMC_BLS24_317
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curve)
+	}
+	return h.New(), nil
+}
@@ -0,0 +1,201 @@
+package poseidon2
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/airchains-network/gnark/std/permutation/poseidon2"
+	"github.com/consensys/gnark-crypto/ecc"
+	fieldhash "github.com/consensys/gnark-crypto/field/hash"
+)
+
+// NewHasher returns the out-of-circuit Poseidon2 hash function over the scalar
+// field of the given curve. It computes the same digest as the in-circuit
+// hasher returned by [NewMerkleDamgardHasher] when the written data is the
+// concatenation of the big-endian encodings of the field elements.
+//
+// The data written to the hash is buffered across the calls to Write, so that
+// the digest depends only on the concatenation of the written data. Every block
+// of [hash.Hash.BlockSize] bytes is interpreted as the big-endian encoding of a
+// field element and Write returns an error if it is not canonical, i.e. not
+// smaller than the modulus. If the written data is not a multiple of the block
+// size, then the last block is padded with zeros on the left.
+func NewHasher(curve ecc.ID) (hash.Hash, error) {
+	params, err := poseidon2.GetDefaultParameters(curve)
+	if err != nil {
+		return nil, err
+	}
+	d := &digest{params: params}
+	d.Reset()
+	return d, nil
+}
+
+type digest struct {
+	params *poseidon2.Parameters
+	state  *big.Int
+	data   []*big.Int
+	// buf holds the written bytes which do not form a full block yet
+	buf []byte
+}
+
+// Write adds more data to the running hash. It returns an error if a block of
+// the data is not the canonical encoding of a field element, in which case
+// none of p is written.
+func (d *digest) Write(p []byte) (int, error) {
+	bs := d.BlockSize()
+	buf := make([]byte, 0, len(d.buf)+len(p))
+	buf = append(append(buf, d.buf...), p...)
+	var elems []*big.Int
+	for ; len(buf) >= bs; buf = buf[bs:] {
+		e := new(big.Int).SetBytes(buf[:bs])
+		if e.Cmp(d.params.Field) >= 0 {
+			return 0, errors.New("invalid input: block is not the canonical encoding of a field element")
+		}
+		elems = append(elems, e)
+	}
+	d.data = append(d.data, elems...)
+	d.buf = buf
+	return len(p), nil
+}
+
+// WriteString hashes the raw bytes into a field element (see RFC 9380) and adds
+// it to the running hash. It is used by the Fiat-Shamir transcript for writing
+// the challenge names. The pending partial block of the data written before is
+// absorbed first.
+func (d *digest) WriteString(rawBytes []byte) {
+	d.flush()
+	// 128 bits of security, see RFC 9380 section 5.
+	l := 16 + d.BlockSize()
+	bts, err := fieldhash.ExpandMsgXmd(rawBytes, []byte("string:"), l)
+	if err != nil {
+		panic(err)
+	}
+	e := new(big.Int).SetBytes(bts)
+	d.data = append(d.data, e.Mod(e, d.params.Field))
+}
+
+// Sum absorbs the written data into the state and appends the big-endian
+// encoding of the state to b.
+func (d *digest) Sum(b []byte) []byte {
+	d.flush()
+	for _, e := range d.data {
+		res, err := d.params.Compress(d.state, e)
+		if err != nil {
+			panic(err) // cannot happen, default parameters have width 2
+		}
+		d.state = res
+	}
+	d.data = nil // flush the data already hashed
+	res := make([]byte, d.Size())
+	d.state.FillBytes(res)
+	return append(b, res...)
+}
+
+// flush appends the pending partial block, padded with zeros on the left, to
+// the data to be hashed.
+func (d *digest) flush() {
+	if len(d.buf) != 0 {
+		d.data = append(d.data, new(big.Int).SetBytes(d.buf))
+		d.buf = nil
+	}
+}
+
+// Reset resets the hash to its initial state.
+func (d *digest) Reset() {
+	d.state = new(big.Int)
+	d.data = nil
+	d.buf = nil
+}
+
+// Size returns the number of bytes returned by Sum.
+func (d *digest) Size() int {
+	return (d.params.Field.BitLen() + 7) / 8
+}
+
+// BlockSize returns the number of bytes corresponding to a single field
+// element.
+func (d *digest) BlockSize() int {
+	return d.Size()
+}
+
+// NewChallengeHasher returns a Poseidon2 hash function over the scalar field of
+// the given curve which accepts arbitrary data. It can be used for example as
+// the challenge hash function of the provers and verifiers (see
+// [github.com/airchains-network/gnark/backend.WithProverChallengeHashFunction]).
+//
+// The written data is partitioned into chunks of [hash.Hash.BlockSize] bytes,
+// one byte less than the field elements, and every chunk is written as a field
+// element to the hash returned by [NewHasher]. The last chunk is padded with
+// zeros on the right.
+func NewChallengeHasher(curve ecc.ID) (hash.Hash, error) {
+	h, err := NewHasher(curve)
+	if err != nil {
+		return nil, err
+	}
+	return &challengeDigest{h: h.(*digest)}, nil
+}
+
+type challengeDigest struct {
+	h *digest
+	// buf holds the written bytes which do not form a full chunk yet
+	buf []byte
+}
+
+// Write adds more data to the running hash. It never returns an error.
+func (d *challengeDigest) Write(p []byte) (int, error) {
+	d.buf = append(d.buf, p...)
+	bs := d.BlockSize()
+	for len(d.buf) >= bs {
+		d.writeChunk(d.buf[:bs])
+		d.buf = d.buf[bs:]
+	}
+	return len(p), nil
+}
+
+// writeChunk writes the chunk prefixed with a zero byte, so that it is the
+// canonical encoding of a field element.
+func (d *challengeDigest) writeChunk(chunk []byte) {
+	block := make([]byte, d.h.BlockSize())
+	copy(block[1:], chunk)
+	if _, err := d.h.Write(block); err != nil {
+		panic(err) // cannot happen, the block is smaller than the modulus
+	}
+}
+
+// flush writes the pending partial chunk.
+func (d *challengeDigest) flush() {
+	if len(d.buf) != 0 {
+		d.writeChunk(d.buf)
+		d.buf = nil
+	}
+}
+
+// WriteString flushes the pending partial chunk and adds the raw bytes hashed
+// into a field element to the running hash (see [digest.WriteString]).
+func (d *challengeDigest) WriteString(rawBytes []byte) {
+	d.flush()
+	d.h.WriteString(rawBytes)
+}
+
+// Sum flushes the pending partial chunk and appends the digest to b.
+func (d *challengeDigest) Sum(b []byte) []byte {
+	d.flush()
+	return d.h.Sum(b)
+}
+
+// Reset resets the hash to its initial state.
+func (d *challengeDigest) Reset() {
+	d.h.Reset()
+	d.buf = nil
+}
+
+// Size returns the number of bytes returned by Sum.
+func (d *challengeDigest) Size() int {
+	return d.h.Size()
+}
+
+// BlockSize returns the number of bytes written as a single field element.
+func (d *challengeDigest) BlockSize() int {
+	return d.h.BlockSize() - 1
+}
@@ -0,0 +1,64 @@
+// Package poseidon2 implements the Poseidon2 hash function over the native
+// field.
+//
+// The hash function is defined as a Merkle-Damgård construction over the
+// width-2 Poseidon2 compression function from [poseidon2.Permutation.Compress],
+// with zero initial value. The hasher and its out-of-circuit counterpart are
+// registered in the hash registry under the name [Name] so that they can be
+// retrieved using [hash.GetFieldHasher] and [hash.GetNativeHasher].
+//
+// The matching out-of-circuit hash function is returned by [NewHasher].
+package poseidon2
+
+import (
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/hash"
+	"github.com/airchains-network/gnark/std/permutation/poseidon2"
+)
+
+// Name is the name under which the hasher is registered in the hash registry.
+const Name = "poseidon2"
+
+func init() {
+	hash.Register(Name, func(api frontend.API) (hash.FieldHasher, error) {
+		return NewMerkleDamgardHasher(api)
+	})
+	hash.RegisterNative(Name, NewHasher)
+}
+
+type merkleDamgardHasher struct {
+	api   frontend.API
+	perm  *poseidon2.Permutation
+	state frontend.Variable
+	data  []frontend.Variable
+}
+
+// NewMerkleDamgardHasher returns a new Poseidon2 hasher using the default
+// parameters for the native field.
+func NewMerkleDamgardHasher(api frontend.API) (hash.FieldHasher, error) {
+	perm, err := poseidon2.NewPoseidon2(api)
+	if err != nil {
+		return nil, err
+	}
+	return &merkleDamgardHasher{api: api, perm: perm, state: 0}, nil
+}
+
+// Write adds more data to the running hash.
+func (h *merkleDamgardHasher) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset resets the hash to its initial state.
+func (h *merkleDamgardHasher) Reset() {
+	h.data = nil
+	h.state = 0
+}
+
+// Sum absorbs the written data into the state and returns the state.
+func (h *merkleDamgardHasher) Sum() frontend.Variable {
+	for _, d := range h.data {
+		h.state = h.perm.Compress(h.state, d)
+	}
+	h.data = nil // flush the data already hashed
+	return h.state
+}
@@ -0,0 +1,143 @@
+package poseidon2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/backend"
+	"github.com/airchains-network/gnark/backend/plonk"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/airchains-network/gnark/std/hash"
+	"github.com/airchains-network/gnark/test"
+	"github.com/airchains-network/gnark/test/unsafekzg"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+type poseidon2Circuit struct {
+	ExpectedResult frontend.Variable `gnark:"data,public"`
+	Data           [10]frontend.Variable
+}
+
+func (circuit *poseidon2Circuit) Define(api frontend.API) error {
+	h, err := hash.GetFieldHasher(Name, api)
+	if err != nil {
+		return err
+	}
+	h.Write(circuit.Data[:]...)
+	api.AssertIsEqual(h.Sum(), circuit.ExpectedResult)
+	return nil
+}
+
+func TestPoseidon2All(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381} {
+		modulus := curve.ScalarField()
+		var data [10]big.Int
+		data[0].Sub(modulus, big.NewInt(1))
+		for i := 1; i < 10; i++ {
+			data[i].Add(&data[i-1], &data[i-1]).Mod(&data[i], modulus)
+		}
+
+		h, err := NewHasher(curve)
+		assert.NoError(err)
+		for i := range data {
+			buf := make([]byte, h.BlockSize())
+			_, err := h.Write(data[i].FillBytes(buf))
+			assert.NoError(err)
+		}
+		expected := h.Sum(nil)
+
+		var validWitness, invalidWitness poseidon2Circuit
+		for i := range data {
+			validWitness.Data[i] = data[i].String()
+			invalidWitness.Data[i] = data[i].String()
+		}
+		validWitness.ExpectedResult = expected
+		invalidWitness.ExpectedResult = 1
+
+		assert.CheckCircuit(&poseidon2Circuit{},
+			test.WithValidAssignment(&validWitness),
+			test.WithInvalidAssignment(&invalidWitness),
+			test.WithCurves(curve))
+	}
+}
+
+func TestHasherWrite(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381} {
+		h, err := NewHasher(curve)
+		assert.NoError(err)
+		bs := h.BlockSize()
+		data := make([]byte, 3*bs+5)
+		for i := range data {
+			// the most significant byte of every block is zero
+			if i%bs != 0 {
+				data[i] = byte(i)
+			}
+		}
+
+		// splitting the data across the calls to Write doesn't change the digest
+		_, err = h.Write(data)
+		assert.NoError(err)
+		expected := h.Sum(nil)
+		for _, split := range []int{1, bs - 1, bs, bs + 3, 2*bs + 7} {
+			h.Reset()
+			_, err = h.Write(data[:split])
+			assert.NoError(err)
+			_, err = h.Write(data[split:])
+			assert.NoError(err)
+			assert.Equal(expected, h.Sum(nil), "split at %d", split)
+		}
+
+		// blocks which are not canonical are rejected
+		h.Reset()
+		modulus := make([]byte, bs)
+		curve.ScalarField().FillBytes(modulus)
+		_, err = h.Write(modulus[:bs/2])
+		assert.NoError(err)
+		_, err = h.Write(modulus[bs/2:])
+		assert.Error(err)
+	}
+}
+
+type smallCircuit struct {
+	X frontend.Variable `gnark:",public"`
+	Y frontend.Variable
+}
+
+func (c *smallCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestChallengeHash(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381} {
+		curve := curve
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &smallCircuit{})
+			assert.NoError(err)
+			srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+			assert.NoError(err)
+			witness, err := frontend.NewWitness(&smallCircuit{X: 3, Y: 9}, curve.ScalarField())
+			assert.NoError(err)
+			pubWitness, err := witness.Public()
+			assert.NoError(err)
+
+			proverHash, err := NewChallengeHasher(curve)
+			assert.NoError(err)
+			verifierHash, err := NewChallengeHasher(curve)
+			assert.NoError(err)
+			proof, err := plonk.Prove(ccs, pk, witness, backend.WithProverChallengeHashFunction(proverHash))
+			assert.NoError(err)
+			err = plonk.Verify(proof, vk, pubWitness, backend.WithVerifierChallengeHashFunction(verifierHash))
+			assert.NoError(err)
+			err = plonk.Verify(proof, vk, pubWitness)
+			assert.Error(err)
+		}, curve.String())
+	}
+}
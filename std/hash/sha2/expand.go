@@ -0,0 +1,70 @@
+package sha2
+
+import (
+	"fmt"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/uints"
+)
+
+// ExpandMsgXmd expands the message msg into lenInBytes pseudo-random bytes
+// using SHA256 as described in [RFC 9380], section 5.3.1. The domain
+// separation tag dst is a constant known at circuit compile time.
+//
+// [RFC 9380]: https://datatracker.ietf.org/doc/html/rfc9380#section-5.3.1
+func ExpandMsgXmd(api frontend.API, msg []uints.U8, dst []byte, lenInBytes int) ([]uints.U8, error) {
+	const bInBytes = 32
+	const rInBytes = 64
+	ell := (lenInBytes + bInBytes - 1) / bInBytes
+	if ell > 255 || lenInBytes > 65535 {
+		return nil, fmt.Errorf("invalid output length %d", lenInBytes)
+	}
+	if len(dst) > 255 {
+		return nil, fmt.Errorf("invalid domain separation tag length %d", len(dst))
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints api: %w", err)
+	}
+	dstPrime := uints.NewU8Array(append(append([]byte{}, dst...), byte(len(dst))))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	h, err := New(api)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(uints.NewU8Array(make([]byte, rInBytes)))
+	h.Write(msg)
+	h.Write(uints.NewU8Array([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0}))
+	h.Write(dstPrime)
+	b0 := h.Sum()
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	h, err = New(api)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(b0)
+	h.Write([]uints.U8{uints.NewU8(1)})
+	h.Write(dstPrime)
+	bi := h.Sum()
+	res := make([]uints.U8, 0, ell*bInBytes)
+	res = append(res, bi...)
+
+	for i := 2; i <= ell; i++ {
+		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
+		h, err = New(api)
+		if err != nil {
+			return nil, err
+		}
+		for j := 0; j < bInBytes; j += 4 {
+			x := uapi.Xor(uapi.PackMSB(b0[j:j+4]...), uapi.PackMSB(bi[j:j+4]...))
+			h.Write(uapi.UnpackMSB(x))
+		}
+		h.Write([]uints.U8{uints.NewU8(uint8(i))})
+		h.Write(dstPrime)
+		bi = h.Sum()
+		res = append(res, bi...)
+	}
+	return res[:lenInBytes], nil
+}
@@ -0,0 +1,59 @@
+package sha2
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	fieldhash "github.com/consensys/gnark-crypto/field/hash"
+)
+
+type expandCircuit struct {
+	In       []uints.U8
+	Expected []uints.U8
+
+	dst []byte
+}
+
+func (c *expandCircuit) Define(api frontend.API) error {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	res, err := ExpandMsgXmd(api, c.In, c.dst, len(c.Expected))
+	if err != nil {
+		return err
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestExpandMsgXmd(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	for _, msg := range []string{"", "abc"} {
+		for _, lenInBytes := range []int{32, 128} {
+			msg, lenInBytes := msg, lenInBytes
+			assert.Run(func(assert *test.Assert) {
+				expected, err := fieldhash.ExpandMsgXmd([]byte(msg), dst, lenInBytes)
+				assert.NoError(err)
+				circuit := &expandCircuit{
+					In:       make([]uints.U8, len(msg)),
+					Expected: make([]uints.U8, lenInBytes),
+					dst:      dst,
+				}
+				witness := &expandCircuit{
+					In:       uints.NewU8Array([]byte(msg)),
+					Expected: uints.NewU8Array(expected),
+				}
+				err = test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+				assert.NoError(err)
+			}, fmt.Sprintf("msg=%q/len=%d", msg, lenInBytes))
+		}
+	}
+}
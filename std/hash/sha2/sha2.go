@@ -18,16 +18,17 @@ var _seed = uints.NewU32Array([]uint32{
 })
 
 type digest struct {
+	api  frontend.API
 	uapi *uints.BinaryField[uints.U32]
 	in   []uints.U8
 }
 
-func New(api frontend.API) (hash.BinaryHasher, error) {
+func New(api frontend.API) (hash.BinaryFixedLengthHasher, error) {
 	uapi, err := uints.New[uints.U32](api)
 	if err != nil {
 		return nil, err
 	}
-	return &digest{uapi: uapi}, nil
+	return &digest{api: api, uapi: uapi}, nil
 }
 
 func (d *digest) Write(data []uints.U8) {
@@ -70,16 +71,90 @@ func (d *digest) Sum() []uints.U8 {
 	return ret
 }
 
+// FixedLengthSum returns the digest of the first length bytes of the input.
+// The length must be at most the total number of bytes written. The padding is
+// placed at a position depending on length and the digest is selected from the
+// intermediate digest of the block where the padding ends. This allows to hash
+// variable-length inputs with a single circuit for all lengths up to the total
+// written length.
 func (d *digest) FixedLengthSum(length frontend.Variable) []uints.U8 {
-	panic("TODO")
-	// we need to do two things here -- first the padding has to be put to the
-	// right place. For that we need to know how many blocks we have used. We
-	// need to fit at least 9 more bytes (padding byte and 8 bytes for input
-	// length). Knowing the block, we have to keep running track if the current
-	// block is the expected one.
-	//
-	// idea - have a mask for blocks where 1 is only for the block we want to
-	// use.
+	api := d.api
+	maxLen := len(d.in)
+	// we need to fit at least 9 more bytes (padding byte and 8 bytes for input
+	// length).
+	nbBlocks := (maxLen + 9 + 63) / 64
+
+	// isEnd[i] is 1 only at i == length. We assert that exactly one of them is
+	// set, which also ensures that 0 <= length <= maxLen.
+	isEnd := make([]frontend.Variable, maxLen+1)
+	for i := range isEnd {
+		isEnd[i] = api.IsZero(api.Sub(length, i))
+	}
+	api.AssertIsEqual(api.Add(0, 0, isEnd...), 1)
+
+	// the length of the input in bits as big-endian bytes. We assume the input
+	// is shorter than 2^29 bytes, so the top four bytes are zero.
+	bitLen := api.Mul(length, 8)
+	bitLenBytes := d.uapi.ValueOf(bitLen)
+	api.AssertIsEqual(d.uapi.ToValue(bitLenBytes), bitLen)
+	lenBytes := append(uints.NewU8Array(make([]uint8, 4)), d.uapi.UnpackMSB(bitLenBytes)...)
+
+	// the padded input. All bytes after length are zeroed and the padding byte
+	// 0x80 is put right after the input.
+	padded := make([]uints.U8, nbBlocks*64)
+	var past frontend.Variable = 0
+	for i := range padded {
+		var v frontend.Variable = 0
+		if i <= maxLen {
+			past = api.Add(past, isEnd[i])
+		}
+		if i < maxLen {
+			v = api.Mul(d.in[i].Val, api.Sub(1, past))
+		}
+		if i <= maxLen {
+			v = api.Add(v, api.Mul(isEnd[i], 0x80))
+		}
+		padded[i] = uints.U8{Val: v}
+	}
+
+	// isLast[k] is 1 only for the block where the input length is written,
+	// i.e. the block containing length+8.
+	isLast := make([]frontend.Variable, nbBlocks)
+	for k := range isLast {
+		var acc frontend.Variable = 0
+		for i := 64*k - 8; i <= 64*k+55; i++ {
+			if i >= 0 && i <= maxLen {
+				acc = api.Add(acc, isEnd[i])
+			}
+		}
+		isLast[k] = acc
+		for j := range lenBytes {
+			padded[64*k+56+j].Val = api.Add(padded[64*k+56+j].Val, api.Mul(isLast[k], lenBytes[j].Val))
+		}
+	}
+
+	var runningDigest [8]uints.U32
+	var buf [64]uints.U8
+	copy(runningDigest[:], _seed)
+	res := make([]frontend.Variable, d.Size())
+	for i := range res {
+		res[i] = 0
+	}
+	for k := 0; k < nbBlocks; k++ {
+		copy(buf[:], padded[k*64:(k+1)*64])
+		runningDigest = sha2.Permute(d.uapi, runningDigest, buf)
+		for i := range runningDigest {
+			bts := d.uapi.UnpackMSB(runningDigest[i])
+			for j := range bts {
+				res[4*i+j] = api.Add(res[4*i+j], api.Mul(isLast[k], bts[j].Val))
+			}
+		}
+	}
+	ret := make([]uints.U8, len(res))
+	for i := range ret {
+		ret[i] = uints.U8{Val: res[i]}
+	}
+	return ret
 }
 
 func (d *digest) Reset() {
@@ -48,3 +48,58 @@ func TestSHA2(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+type sha2FixedLengthCircuit struct {
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected [32]uints.U8
+}
+
+func (c *sha2FixedLengthCircuit) Define(api frontend.API) error {
+	h, err := New(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.FixedLengthSum(c.Length)
+	if len(res) != 32 {
+		return fmt.Errorf("not 32 bytes")
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestSHA2FixedLengthSum(t *testing.T) {
+	const maxLen = 130
+	bts := make([]byte, maxLen)
+	for i := range bts {
+		bts[i] = byte(i + 1)
+	}
+	for _, length := range []int{0, 1, 55, 56, 63, 64, 65, 119, 120, maxLen} {
+		dgst := sha256.Sum256(bts[:length])
+		witness := sha2FixedLengthCircuit{
+			In:     uints.NewU8Array(bts),
+			Length: length,
+		}
+		copy(witness.Expected[:], uints.NewU8Array(dgst[:]))
+		err := test.IsSolved(&sha2FixedLengthCircuit{In: make([]uints.U8, maxLen)}, &witness, ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatalf("length %d: %v", length, err)
+		}
+	}
+	// length larger than the input is not allowed
+	witness := sha2FixedLengthCircuit{
+		In:     uints.NewU8Array(bts),
+		Length: maxLen + 1,
+	}
+	err := test.IsSolved(&sha2FixedLengthCircuit{In: make([]uints.U8, maxLen)}, &witness, ecc.BN254.ScalarField())
+	if err == nil {
+		t.Fatal("expected error for too long input")
+	}
+}
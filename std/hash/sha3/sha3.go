@@ -1,11 +1,13 @@
 package sha3
 
 import (
+	"github.com/airchains-network/gnark/frontend"
 	"github.com/airchains-network/gnark/std/math/uints"
 	"github.com/airchains-network/gnark/std/permutation/keccakf"
 )
 
 type digest struct {
+	api       frontend.API
 	uapi      *uints.BinaryField[uints.U64]
 	state     [25]uints.U64 // 1600 bits state: 25 x 64
 	in        []uints.U8    // input to be digested
@@ -32,6 +34,84 @@ func (d *digest) Sum() []uints.U8 {
 	return d.squeezeBlocks()
 }
 
+// FixedLengthSum returns the digest of the first length bytes of the input.
+// The length must be at most the total number of bytes written. The padding is
+// placed at a position depending on length and the digest is squeezed from the
+// state after absorbing the block where the padding ends. This allows to hash
+// variable-length inputs with a single circuit for all lengths up to the total
+// written length.
+func (d *digest) FixedLengthSum(length frontend.Variable) []uints.U8 {
+	api := d.api
+	maxLen := len(d.in)
+	// we need to fit at least one padding byte.
+	nbBlocks := maxLen/d.rate + 1
+
+	// isEnd[i] is 1 only at i == length. We assert that exactly one of them is
+	// set, which also ensures that 0 <= length <= maxLen.
+	isEnd := make([]frontend.Variable, maxLen+1)
+	for i := range isEnd {
+		isEnd[i] = api.IsZero(api.Sub(length, i))
+	}
+	api.AssertIsEqual(api.Add(0, 0, isEnd...), 1)
+
+	// the padded input. All bytes after length are zeroed and the domain
+	// separation byte is put right after the input.
+	padded := make([]uints.U8, nbBlocks*d.rate)
+	var past frontend.Variable = 0
+	for i := range padded {
+		var v frontend.Variable = 0
+		if i <= maxLen {
+			past = api.Add(past, isEnd[i])
+		}
+		if i < maxLen {
+			v = api.Mul(d.in[i].Val, api.Sub(1, past))
+		}
+		if i <= maxLen {
+			v = api.Add(v, api.Mul(isEnd[i], d.dsbyte))
+		}
+		padded[i] = uints.U8{Val: v}
+	}
+
+	// isLast[k] is 1 only for the block where the input ends. The last byte of
+	// the block gets the final padding bit. As the domain separation byte is
+	// less than 0x80, then we can add the bit even if they are in the same
+	// byte.
+	isLast := make([]frontend.Variable, nbBlocks)
+	for k := range isLast {
+		var acc frontend.Variable = 0
+		for i := k * d.rate; i < (k+1)*d.rate && i <= maxLen; i++ {
+			acc = api.Add(acc, isEnd[i])
+		}
+		isLast[k] = acc
+		last := (k+1)*d.rate - 1
+		padded[last].Val = api.Add(padded[last].Val, api.Mul(isLast[k], 0x80))
+	}
+
+	state := newState()
+	blocks := d.composeBlocks(padded)
+	res := make([]frontend.Variable, d.outputLen)
+	for i := range res {
+		res[i] = 0
+	}
+	for k, block := range blocks {
+		for i := range block {
+			state[i] = d.uapi.Xor(state[i], block[i])
+		}
+		state = keccakf.Permute(d.uapi, state)
+		for i := 0; i < d.outputLen/8; i++ {
+			bts := d.uapi.UnpackLSB(state[i])
+			for j := range bts {
+				res[8*i+j] = api.Add(res[8*i+j], api.Mul(isLast[k], bts[j].Val))
+			}
+		}
+	}
+	ret := make([]uints.U8, len(res))
+	for i := range ret {
+		ret[i] = uints.U8{Val: res[i]}
+	}
+	return ret
+}
+
 func (d *digest) padding() []uints.U8 {
 	padded := make([]uints.U8, len(d.in))
 	copy(padded[:], d.in[:])
@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/airchains-network/gnark/constraint/solver"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_bls12381"
 	"github.com/airchains-network/gnark/std/algebra/emulated/sw_emulated"
 	"github.com/airchains-network/gnark/std/algebra/native/sw_bls12377"
 	"github.com/airchains-network/gnark/std/algebra/native/sw_bls24315"
@@ -15,6 +16,7 @@ import (
 	"github.com/airchains-network/gnark/std/math/emulated"
 	"github.com/airchains-network/gnark/std/rangecheck"
 	"github.com/airchains-network/gnark/std/selector"
+	"github.com/airchains-network/gnark/std/signature/ecdsa"
 )
 
 var registerOnce sync.Once
@@ -42,4 +44,6 @@ func registerHints() {
 	solver.RegisterHint(logderivarg.GetHints()...)
 	solver.RegisterHint(bitslice.GetHints()...)
 	solver.RegisterHint(sw_emulated.GetHints()...)
+	solver.RegisterHint(sw_bls12381.GetHints()...)
+	solver.RegisterHint(ecdsa.GetHints()...)
 }
@@ -44,7 +44,7 @@ import (
 
 	"github.com/airchains-network/gnark/constraint/solver"
 	"github.com/airchains-network/gnark/frontend"
-	"github.com/airchains-network/gnark/std/hash/mimc"
+	"github.com/airchains-network/gnark/std/internal/mimc"
 	"github.com/airchains-network/gnark/std/multicommit"
 )
 
@@ -918,6 +918,10 @@ func testIsZero[T FieldParams](t *testing.T) {
 		circuit := IsZeroCircuit[T]{}
 		assert.ProverSucceeded(&circuit, &IsZeroCircuit[T]{X: ValueOf[T](X), Y: ValueOf[T](Y), Zero: 1}, test.WithCurves(testCurve), test.NoSerializationChecks(), test.WithBackends(backend.GROTH16, backend.PLONK))
 		assert.ProverSucceeded(&circuit, &IsZeroCircuit[T]{X: ValueOf[T](X), Y: ValueOf[T](0), Zero: 0}, test.WithCurves(testCurve), test.NoSerializationChecks(), test.WithBackends(backend.GROTH16, backend.PLONK))
+		// the lowest limb is zero, but not the element
+		L := new(big.Int).Lsh(big.NewInt(1), fp.BitsPerLimb())
+		L.Mod(L, fp.Modulus())
+		assert.ProverSucceeded(&circuit, &IsZeroCircuit[T]{X: ValueOf[T](L), Y: ValueOf[T](0), Zero: 0}, test.WithCurves(testCurve), test.NoSerializationChecks(), test.WithBackends(backend.GROTH16, backend.PLONK))
 	}, testName[T]())
 }
 
@@ -956,3 +960,65 @@ func testSqrt[T FieldParams](t *testing.T) {
 		assert.ProverSucceeded(&SqrtCircuit[T]{}, &SqrtCircuit[T]{X: ValueOf[T](X), Expected: ValueOf[T](exp)}, test.WithCurves(testCurve), test.NoSerializationChecks(), test.WithBackends(backend.GROTH16, backend.PLONK))
 	}, testName[T]())
 }
+
+type ModMulCircuit[T FieldParams] struct {
+	A, B, Modulus, Expected Element[T]
+}
+
+func (c *ModMulCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.ModMul(&c.A, &c.B, &c.Modulus)
+	f.AssertLimbsEquality(res, &c.Expected)
+	return nil
+}
+
+func TestModMul(t *testing.T) {
+	assert := test.NewAssert(t)
+	var fp Mod1e512
+	for _, modBits := range []int{2, 64, 200, 512} {
+		modBits := modBits
+		assert.Run(func(assert *test.Assert) {
+			modulus, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(modBits)))
+			modulus.SetBit(modulus, modBits-1, 1)
+			A, _ := rand.Int(rand.Reader, fp.Modulus())
+			B, _ := rand.Int(rand.Reader, fp.Modulus())
+			expected := new(big.Int).Mul(A, B)
+			expected.Mod(expected, modulus)
+			witness := ModMulCircuit[Mod1e512]{A: ValueOf[Mod1e512](A), B: ValueOf[Mod1e512](B), Modulus: ValueOf[Mod1e512](modulus), Expected: ValueOf[Mod1e512](expected)}
+			assert.CheckCircuit(&ModMulCircuit[Mod1e512]{}, test.WithValidAssignment(&witness), test.WithCurves(testCurve), test.NoSerializationChecks(), test.WithBackends(backend.GROTH16))
+
+			wrong := ModMulCircuit[Mod1e512]{A: ValueOf[Mod1e512](A), B: ValueOf[Mod1e512](B), Modulus: ValueOf[Mod1e512](modulus), Expected: ValueOf[Mod1e512](new(big.Int).Add(expected, big.NewInt(1)))}
+			err := test.IsSolved(&ModMulCircuit[Mod1e512]{}, &wrong, testCurve.ScalarField())
+			assert.Error(err)
+		}, fmt.Sprintf("modbits=%d", modBits))
+	}
+}
+
+type ModExpCircuit[T FieldParams] struct {
+	Base, Exp, Modulus, Expected Element[T]
+}
+
+func (c *ModExpCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.ModExp(&c.Base, &c.Exp, &c.Modulus)
+	f.AssertLimbsEquality(res, &c.Expected)
+	return nil
+}
+
+func TestModExp(t *testing.T) {
+	assert := test.NewAssert(t)
+	var fp Mod1e512
+	base, _ := rand.Int(rand.Reader, fp.Modulus())
+	exp, _ := rand.Int(rand.Reader, fp.Modulus())
+	modulus, _ := rand.Int(rand.Reader, fp.Modulus())
+	expected := new(big.Int).Exp(base, exp, modulus)
+	witness := ModExpCircuit[Mod1e512]{Base: ValueOf[Mod1e512](base), Exp: ValueOf[Mod1e512](exp), Modulus: ValueOf[Mod1e512](modulus), Expected: ValueOf[Mod1e512](expected)}
+	err := test.IsSolved(&ModExpCircuit[Mod1e512]{}, &witness, testCurve.ScalarField())
+	assert.NoError(err)
+}
@@ -281,3 +281,39 @@ func (fp BLS12315Fp) Modulus() *big.Int { return ecc.BLS24_315.BaseField() }
 type BLS12315Fr struct{ fourLimbPrimeField }
 
 func (fr BLS12315Fr) Modulus() *big.Int { return ecc.BLS24_315.ScalarField() }
+
+// Mod1e4096 provides type parametrization for emulated arithmetic:
+//   - limbs: 64
+//   - limb width: 64 bits
+//
+// The modulus for type parametrisation is 2^4096-1.
+//
+// This is non-prime modulus. It is mainly targeted for using variable-modulus
+// operations (ModMul, ModExp) with variable modulus up to 4096 bits.
+type Mod1e4096 struct{}
+
+func (Mod1e4096) NbLimbs() uint     { return 64 }
+func (Mod1e4096) BitsPerLimb() uint { return 64 }
+func (Mod1e4096) IsPrime() bool     { return false }
+func (Mod1e4096) Modulus() *big.Int {
+	val := new(big.Int).Lsh(big.NewInt(1), 4096)
+	return val.Sub(val, big.NewInt(1))
+}
+
+// Mod1e512 provides type parametrization for emulated arithmetic:
+//   - limbs: 8
+//   - limb width: 64 bits
+//
+// The modulus for type parametrisation is 2^512-1.
+//
+// This is non-prime modulus. It is mainly targeted for using variable-modulus
+// operations (ModMul, ModExp) with variable modulus up to 512 bits.
+type Mod1e512 struct{}
+
+func (Mod1e512) NbLimbs() uint     { return 8 }
+func (Mod1e512) BitsPerLimb() uint { return 64 }
+func (Mod1e512) IsPrime() bool     { return false }
+func (Mod1e512) Modulus() *big.Int {
+	val := new(big.Int).Lsh(big.NewInt(1), 512)
+	return val.Sub(val, big.NewInt(1))
+}
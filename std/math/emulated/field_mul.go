@@ -22,11 +22,12 @@ import (
 // and return pointers, and to change the values the user has to explicitly
 // dereference.
 //
-// We store the values a, b, r, k, c. They are as follows:
+// We store the values a, b, r, k, c and optionally p. They are as follows:
 //   - a, b - the inputs what we are multiplying. Do not have to be reduced.
 //   - r - the multiplication result reduced modulo the emulation parameter.
 //   - k - the quotient for integer multiplication a*b divided by emulation parameter.
 //   - c - element representing carry. Used only for aligning the limb widths.
+//   - p - the modulus for the check. If nil, then the emulation parameter is used.
 //
 // Given these values, the following holds:
 //
@@ -58,6 +59,7 @@ type mulCheck[T FieldParams] struct {
 	r    *Element[T] // reduced value
 	k    *Element[T] // coefficient
 	c    *Element[T] // carry
+	p    *Element[T] // modulus if non-nil
 }
 
 // evalRound1 evaluates first c(X), r(X) and k(X) at a given random point at[0].
@@ -69,18 +71,26 @@ func (mc *mulCheck[T]) evalRound1(api frontend.API, at []frontend.Variable) {
 	mc.k = mc.f.evalWithChallenge(mc.k, at)
 }
 
-// evalRound2 now evaluates a and b at a given random point at[0]. However, it
-// may happen that a or b is equal to r from a previous mulcheck. In that case
-// we can reuse the evaluation to save constraints.
+// evalRound2 now evaluates a, b and the custom modulus p (if set) at a given
+// random point at[0]. However, it may happen that a or b is equal to r from a
+// previous mulcheck. In that case we can reuse the evaluation to save
+// constraints.
 func (mc *mulCheck[T]) evalRound2(api frontend.API, at []frontend.Variable) {
 	mc.a = mc.f.evalWithChallenge(mc.a, at)
 	mc.b = mc.f.evalWithChallenge(mc.b, at)
+	if mc.p != nil {
+		mc.p = mc.f.evalWithChallenge(mc.p, at)
+	}
 }
 
 // check checks a(ch) * b(ch) = r(ch) + k(ch) * p(ch) + (2^t - ch) c(ch). As the
 // computation of p(ch) and (2^t-ch) can be shared over all mulCheck instances,
-// then we get them already evaluated as peval and coef.
+// then we get them already evaluated as peval and coef. If the check has a
+// custom modulus, then its evaluation is used instead of peval.
 func (mc *mulCheck[T]) check(api frontend.API, peval, coef frontend.Variable) {
+	if mc.p != nil {
+		peval = mc.p.evaluation
+	}
 	ls := api.Mul(mc.a.evaluation, mc.b.evaluation)
 	rs := api.Add(mc.r.evaluation, api.Mul(peval, mc.k.evaluation), api.Mul(mc.c.evaluation, coef))
 	api.AssertIsEqual(ls, rs)
@@ -99,6 +109,10 @@ func (mc *mulCheck[T]) cleanEvaluations() {
 	mc.k.isEvaluated = false
 	mc.c.evaluation = 0
 	mc.c.isEvaluated = false
+	if mc.p != nil {
+		mc.p.evaluation = 0
+		mc.p.isEvaluated = false
+	}
 }
 
 // mulMod returns a*b mod r. In practice it computes the result using a hint and
@@ -106,7 +120,7 @@ func (mc *mulCheck[T]) cleanEvaluations() {
 func (f *Field[T]) mulMod(a, b *Element[T], _ uint) *Element[T] {
 	f.enforceWidthConditional(a)
 	f.enforceWidthConditional(b)
-	k, r, c, err := f.callMulHint(a, b)
+	k, r, c, err := f.callMulHint(a, b, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -167,6 +181,9 @@ func (f *Field[T]) performMulChecks(api frontend.API) error {
 		toCommit = append(toCommit, f.mulChecks[i].r.Limbs...)
 		toCommit = append(toCommit, f.mulChecks[i].k.Limbs...)
 		toCommit = append(toCommit, f.mulChecks[i].c.Limbs...)
+		if f.mulChecks[i].p != nil {
+			toCommit = append(toCommit, f.mulChecks[i].p.Limbs...)
+		}
 	}
 	// we give all the inputs as inputs to obtain random verifier challenge.
 	multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
@@ -209,8 +226,9 @@ func (f *Field[T]) performMulChecks(api frontend.API) error {
 	return nil
 }
 
-// callMulHint uses hint to compute r, k and c.
-func (f *Field[T]) callMulHint(a, b *Element[T]) (quo, rem, carries *Element[T], err error) {
+// callMulHint uses hint to compute r, k and c. If modulus is nil, then reduces
+// modulo the emulation parameter.
+func (f *Field[T]) callMulHint(a, b, modulus *Element[T]) (quo, rem, carries *Element[T], err error) {
 	// inputs is always nblimbs
 	// quotient may be larger if inputs have overflow
 	// remainder is always nblimbs
@@ -219,8 +237,16 @@ func (f *Field[T]) callMulHint(a, b *Element[T]) (quo, rem, carries *Element[T],
 	// skip error handle - it happens when we are supposed to reduce. But we
 	// already check it as a precondition. We only need the overflow here.
 	nbLimbs, nbBits := f.fParams.NbLimbs(), f.fParams.BitsPerLimb()
+	modBits := uint(f.fParams.Modulus().BitLen())
+	if modulus != nil {
+		// we do not know the bit length of the custom modulus, assume the worst
+		// case for sizing the quotient.
+		modBits = 1
+	} else {
+		modulus = f.Modulus()
+	}
 	nbQuoLimbs := ((2*nbLimbs-1)*nbBits + nextOverflow + 1 - //
-		uint(f.fParams.Modulus().BitLen()) + //
+		modBits + //
 		nbBits - 1) /
 		nbBits
 	nbRemLimbs := nbLimbs
@@ -229,7 +255,7 @@ func (f *Field[T]) callMulHint(a, b *Element[T]) (quo, rem, carries *Element[T],
 		nbBits,
 		nbLimbs,
 	}
-	hintInputs = append(hintInputs, f.Modulus().Limbs...)
+	hintInputs = append(hintInputs, modulus.Limbs...)
 	hintInputs = append(hintInputs, a.Limbs...)
 	hintInputs = append(hintInputs, b.Limbs...)
 	ret, err := f.api.NewHint(mulHint, int(nbQuoLimbs)+int(nbRemLimbs)+int(nbCarryLimbs), hintInputs...)
@@ -274,6 +300,9 @@ func mulHint(field *big.Int, inputs, outputs []*big.Int) error {
 	if err := recompose(blimbs, uint(nbBits), b); err != nil {
 		return fmt.Errorf("recompose b: %w", err)
 	}
+	if p.Sign() == 0 {
+		return fmt.Errorf("modulus is zero")
+	}
 	quo := new(big.Int)
 	rem := new(big.Int)
 	ab := new(big.Int).Mul(a, b)
@@ -428,3 +457,59 @@ func (f *Field[T]) mul(a, b *Element[T], nextOverflow uint) *Element[T] {
 	}
 	return f.newInternalElement(mulResult, nextOverflow)
 }
+
+// ModMul computes a*b mod modulus. Instead of taking modulus as a constant
+// parametrized by T, it is passed as an argument. This allows to use a variable
+// modulus in the circuit. Type parameter T should be sufficiently big to fit a,
+// b and modulus. Recommended to use predefined [Mod1e4096] or [Mod1e512].
+//
+// NB! circuit complexity depends on T rather than on the actual length of the
+// modulus.
+func (f *Field[T]) ModMul(a, b *Element[T], modulus *Element[T]) *Element[T] {
+	f.enforceWidthConditional(modulus)
+	if modulus.overflow != 0 || len(modulus.Limbs) != int(f.fParams.NbLimbs()) {
+		panic("modulus must have zero overflow and full limb count")
+	}
+	f.enforceWidthConditional(a)
+	f.enforceWidthConditional(b)
+	// we cannot use reduceAndOp here as the reduction is done modulo the
+	// emulation parameter, not the given modulus.
+	if _, err := f.mulPreCond(a, b); err != nil {
+		panic(fmt.Sprintf("mod mul: %v", err))
+	}
+	k, r, c, err := f.callMulHint(a, b, modulus)
+	if err != nil {
+		panic(err)
+	}
+	mc := mulCheck[T]{
+		f: f,
+		a: a,
+		b: b,
+		c: c,
+		k: k,
+		r: r,
+		p: modulus,
+	}
+	f.mulChecks = append(f.mulChecks, mc)
+	return r
+}
+
+// ModExp computes base^exp mod modulus using square-and-multiply. The exponent
+// is interpreted as an integer, i.e. it is not reduced modulo any value. See
+// [Field.ModMul] for the requirements on the type parameter T.
+//
+// The result is congruent to base^exp modulo modulus, but is not necessarily
+// the canonical representative. To enforce it, compare the result against the
+// modulus.
+func (f *Field[T]) ModExp(base, exp, modulus *Element[T]) *Element[T] {
+	expBts := f.ToBits(exp)
+	res := f.ModMul(f.One(), f.One(), modulus)
+	base = f.ModMul(base, f.One(), modulus)
+	for i := range expBts {
+		res = f.Select(expBts[i], f.ModMul(res, base, modulus), res)
+		if i < len(expBts)-1 {
+			base = f.ModMul(base, base, modulus)
+		}
+	}
+	return res
+}
@@ -39,4 +39,6 @@ type (
 	P384Fr      = emparams.P384Fr
 	BW6761Fp    = emparams.BW6761Fp
 	BW6761Fr    = emparams.BW6761Fr
+	Mod1e4096   = emparams.Mod1e4096
+	Mod1e512    = emparams.Mod1e512
 )
@@ -16,6 +16,7 @@ func GetHints() []solver.Hint {
 		andHint,
 		xorHint,
 		toBytes,
+		carryHint,
 	}
 }
 
@@ -40,9 +41,11 @@ func toBytes(m *big.Int, inputs []*big.Int, outputs []*big.Int) error {
 	if len(outputs) != nbLimbs {
 		return fmt.Errorf("output must be 8 elements")
 	}
-	if !inputs[1].IsUint64() {
-		return fmt.Errorf("input must be 64 bits")
+	if inputs[1].Sign() < 0 {
+		return fmt.Errorf("input must be non-negative")
 	}
+	// we allow the input to overflow the number of limbs for computing modular
+	// additions. The carry is computed by carryHint and constrained in Add.
 	base := new(big.Int).Lsh(big.NewInt(1), uint(8))
 	tmp := new(big.Int).Set(inputs[1])
 	for i := 0; i < nbLimbs; i++ {
@@ -51,3 +54,22 @@ func toBytes(m *big.Int, inputs []*big.Int, outputs []*big.Int) error {
 	}
 	return nil
 }
+
+// carryHint returns the carry of the sum inputs[1] of nbLimbs = inputs[0] bytes,
+// that is inputs[1] >> (8*nbLimbs).
+func carryHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("input must be 2 elements")
+	}
+	if len(outputs) != 1 {
+		return fmt.Errorf("output must be 1 element")
+	}
+	if !inputs[0].IsUint64() {
+		return fmt.Errorf("first input must be uint64")
+	}
+	if inputs[1].Sign() < 0 {
+		return fmt.Errorf("input must be non-negative")
+	}
+	outputs[0].Rsh(inputs[1], uint(8*inputs[0].Uint64()))
+	return nil
+}
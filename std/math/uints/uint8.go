@@ -24,6 +24,8 @@ package uints
 
 import (
 	"fmt"
+	"math/big"
+	"math/bits"
 
 	"github.com/airchains-network/gnark/frontend"
 	"github.com/airchains-network/gnark/std/internal/logderivprecomp"
@@ -254,6 +256,9 @@ func (bf *BinaryField[T]) Not(a T) T {
 	return r
 }
 
+// Add returns the sum of the inputs modulo 2^w, where w is the bit width of T.
+// The carry is range checked and the decomposition of the sum into the result
+// and the carry is constrained.
 func (bf *BinaryField[T]) Add(a ...T) T {
 	va := make([]frontend.Variable, len(a))
 	for i := range a {
@@ -261,7 +266,14 @@ func (bf *BinaryField[T]) Add(a ...T) T {
 	}
 	vres := bf.api.Add(va[0], va[1], va[2:]...)
 	res := bf.ValueOf(vres)
-	// TODO: should also check the that carry we omitted is correct.
+	// the sum of n inputs is less than n*2^w, so the carry is less than n.
+	var r T
+	carry, err := bf.api.Compiler().NewHint(carryHint, 1, len(r), vres)
+	if err != nil {
+		panic(err)
+	}
+	bf.rchecker.Check(carry[0], bits.Len(uint(len(a)-1)))
+	bf.api.AssertIsEqual(bf.api.Add(bf.ToValue(res), bf.api.Mul(carry[0], new(big.Int).Lsh(big.NewInt(1), uint(8*len(r))))), vres)
 	return res
 }
 
@@ -1,11 +1,14 @@
 package uints
 
 import (
+	"math/big"
 	"math/bits"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/airchains-network/gnark/constraint/solver"
 	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
 	"github.com/airchains-network/gnark/test"
 )
 
@@ -79,3 +82,42 @@ func TestRshift(t *testing.T) {
 	err = test.IsSolved(&rshiftCircuit{Shift: 11}, &rshiftCircuit{Shift: 11, In: NewU32(0x12345678), Expected: NewU32(0x12345678 >> 11)}, ecc.BN254.ScalarField())
 	assert.NoError(err)
 }
+
+type addCircuit struct {
+	In       [3]U64
+	Expected U64
+}
+
+func (c *addCircuit) Define(api frontend.API) error {
+	uapi, err := New[U64](api)
+	if err != nil {
+		return err
+	}
+	res := uapi.Add(c.In[0], c.In[1], c.In[2])
+	uapi.AssertEq(res, c.Expected)
+	return nil
+}
+
+func TestAdd(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := [3]uint64{0xffffffffffffffff, 0xfedcba9876543210, 0x0123456789abcdef}
+	assignment := addCircuit{
+		In:       [3]U64{NewU64(in[0]), NewU64(in[1]), NewU64(in[2])},
+		Expected: NewU64(in[0] + in[1] + in[2]),
+	}
+	err := test.IsSolved(&addCircuit{}, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the result must not be chosen freely by the prover
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &addCircuit{})
+	assert.NoError(err)
+	assignment.Expected = NewU64(in[0] + in[1] + in[2] + 1)
+	w, err := frontend.NewWitness(&assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	wrongBytes := func(m *big.Int, inputs, outputs []*big.Int) error {
+		sum := new(big.Int).Add(inputs[1], big.NewInt(1))
+		return toBytes(m, []*big.Int{inputs[0], sum}, outputs)
+	}
+	_, err = ccs.Solve(w, solver.OverrideHint(solver.GetHintID(toBytes), wrongBytes))
+	assert.Error(err)
+}
@@ -0,0 +1,120 @@
+// Package blake2 implements the BLAKE2b and BLAKE2s compression functions.
+//
+// This package exposes only the compression function F as defined in [RFC
+// 7693]. For the full hash functions see the
+// [github.com/airchains-network/gnark/std/hash/blake2] package. The compression
+// function with a variable number of rounds is also used directly in the EVM
+// BLAKE2F precompile, see
+// [github.com/airchains-network/gnark/std/evmprecompiles.BLAKE2F].
+//
+// [RFC 7693]: https://www.rfc-editor.org/rfc/rfc7693
+package blake2
+
+import (
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/uints"
+)
+
+// Blake2bRounds and Blake2sRounds are the number of rounds of the compression
+// functions as used in the hash functions.
+const (
+	Blake2bRounds = 12
+	Blake2sRounds = 10
+)
+
+// IV2b is the initialisation vector of BLAKE2b.
+var IV2b = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+// IV2s is the initialisation vector of BLAKE2s.
+var IV2s = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A, 0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+var sigma = [10][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+var (
+	rot2b = [4]int{32, 24, 16, 63}
+	rot2s = [4]int{16, 12, 8, 7}
+)
+
+// CompressBlake2b applies the BLAKE2b compression function F on the state h
+// with message block m, offset counter t and final block indicator final using
+// the given number of rounds. The indicator final must be boolean. The
+// standard number of rounds is [Blake2bRounds].
+func CompressBlake2b(api frontend.API, uapi *uints.BinaryField[uints.U64], rounds int, h [8]uints.U64, m [16]uints.U64, t [2]uints.U64, final frontend.Variable) [8]uints.U64 {
+	return compress(api, uapi, uints.NewU64Array(IV2b[:]), rot2b, rounds, h, m, t, final)
+}
+
+// CompressBlake2s applies the BLAKE2s compression function F on the state h
+// with message block m, offset counter t and final block indicator final using
+// the given number of rounds. The indicator final must be boolean. The
+// standard number of rounds is [Blake2sRounds].
+func CompressBlake2s(api frontend.API, uapi *uints.BinaryField[uints.U32], rounds int, h [8]uints.U32, m [16]uints.U32, t [2]uints.U32, final frontend.Variable) [8]uints.U32 {
+	return compress(api, uapi, uints.NewU32Array(IV2s[:]), rot2s, rounds, h, m, t, final)
+}
+
+func compress[T uints.Long](api frontend.API, uapi *uints.BinaryField[T], iv []T, rot [4]int, rounds int, h [8]T, m [16]T, t [2]T, final frontend.Variable) [8]T {
+	var v [16]T
+	copy(v[:8], h[:])
+	copy(v[8:], iv)
+	v[12] = uapi.Xor(v[12], t[0])
+	v[13] = uapi.Xor(v[13], t[1])
+
+	// invert all bits of v[14] if the block is final. When final is constant
+	// we avoid the lookups.
+	if c, ok := api.Compiler().ConstantValue(final); ok {
+		if c.Sign() != 0 {
+			v[14] = uapi.Not(v[14])
+		}
+	} else {
+		api.AssertIsBoolean(final)
+		var mask T
+		for i := 0; i < len(mask); i++ {
+			mask[i] = uints.U8{Val: api.Mul(final, 0xff)}
+		}
+		v[14] = uapi.Xor(v[14], mask)
+	}
+
+	g := func(a, b, c, d int, x, y T) {
+		v[a] = uapi.Add(v[a], v[b], x)
+		v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -rot[0])
+		v[c] = uapi.Add(v[c], v[d])
+		v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -rot[1])
+		v[a] = uapi.Add(v[a], v[b], y)
+		v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -rot[2])
+		v[c] = uapi.Add(v[c], v[d])
+		v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -rot[3])
+	}
+
+	for i := 0; i < rounds; i++ {
+		s := sigma[i%10]
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+
+	var res [8]T
+	for i := range res {
+		res[i] = uapi.Xor(h[i], v[i], v[i+8])
+	}
+	return res
+}
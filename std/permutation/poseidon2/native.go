@@ -0,0 +1,71 @@
+package poseidon2
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Permute applies the permutation out-of-circuit on the state in place. The
+// elements of the state are reduced modulo the field. It is the reference
+// implementation of [Permutation.Permutation].
+func (p *Parameters) Permute(state []*big.Int) error {
+	if len(state) != p.Width {
+		return errors.New("poseidon2: invalid state length")
+	}
+	for i := range state {
+		state[i].Mod(state[i], p.Field)
+	}
+	exp := big.NewInt(int64(p.DegreeSBox))
+	rf := p.NbFullRounds / 2
+
+	p.nativeMatMulExternal(state)
+	for i := 0; i < p.NbFullRounds+p.NbPartialRounds; i++ {
+		if i < rf || i >= rf+p.NbPartialRounds {
+			for j := range state {
+				state[j].Add(state[j], p.RoundKeys[i][j])
+				state[j].Exp(state[j], exp, p.Field)
+			}
+			p.nativeMatMulExternal(state)
+		} else {
+			state[0].Add(state[0], p.RoundKeys[i][0])
+			state[0].Exp(state[0], exp, p.Field)
+			p.nativeMatMulInternal(state)
+		}
+	}
+	return nil
+}
+
+// Compress applies the permutation on (left, right) and returns the second
+// element of the output added to right. It is the reference implementation of
+// [Permutation.Compress].
+func (p *Parameters) Compress(left, right *big.Int) (*big.Int, error) {
+	if p.Width != 2 {
+		return nil, errors.New("poseidon2: compression requires width 2")
+	}
+	state := []*big.Int{new(big.Int).Set(left), new(big.Int).Set(right)}
+	if err := p.Permute(state); err != nil {
+		return nil, err
+	}
+	res := state[1].Add(state[1], right)
+	return res.Mod(res, p.Field), nil
+}
+
+func (p *Parameters) nativeMatMulExternal(state []*big.Int) {
+	// circ(2, 1) for width 2 and circ(2, 1, 1) for width 3, i.e. every
+	// element is added the sum of the state.
+	sum := new(big.Int)
+	for i := range state {
+		sum.Add(sum, state[i])
+	}
+	for i := range state {
+		state[i].Add(state[i], sum).Mod(state[i], p.Field)
+	}
+}
+
+func (p *Parameters) nativeMatMulInternal(state []*big.Int) {
+	// [[2, 1], [1, 3]] for width 2 and [[2, 1, 1], [1, 2, 1], [1, 1, 3]] for
+	// width 3, i.e. the external matrix with the last element doubled.
+	last := new(big.Int).Set(state[len(state)-1])
+	p.nativeMatMulExternal(state)
+	state[len(state)-1].Add(state[len(state)-1], last).Mod(state[len(state)-1], p.Field)
+}
@@ -0,0 +1,177 @@
+package poseidon2
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+var (
+	ErrInvalidWidth      = errors.New("poseidon2: only widths 2 and 3 are supported")
+	ErrInvalidSBox       = errors.New("poseidon2: degree of the S-box must be coprime with q-1")
+	ErrInvalidFullRounds = errors.New("poseidon2: number of full rounds must be even")
+)
+
+// Parameters defines a Poseidon2 permutation instance over a prime field.
+type Parameters struct {
+	// Field is the modulus of the field the permutation is defined over.
+	Field *big.Int
+	// Width is the size of the state.
+	Width int
+	// DegreeSBox is the exponent d of the S-box x -> x^d.
+	DegreeSBox int
+	// NbFullRounds is the total number of full rounds. Half of them are
+	// performed before the partial rounds and half after.
+	NbFullRounds int
+	// NbPartialRounds is the number of partial rounds.
+	NbPartialRounds int
+	// RoundKeys are the round constants. For full rounds there are Width
+	// constants, for partial rounds there is a single constant.
+	RoundKeys [][]*big.Int
+}
+
+// NewParameters returns a new Poseidon2 instance over the field defined by
+// modulus. The round constants are sampled with the Grain LFSR initialised
+// with the instance description, as in the reference implementation [HorizenLabs].
+//
+// The numbers of rounds are not checked, they should be obtained from the
+// round numbers script of the reference implementation for the targeted
+// security level.
+//
+// [HorizenLabs]: https://github.com/HorizenLabs/poseidon2
+func NewParameters(modulus *big.Int, width, degreeSBox, nbFullRounds, nbPartialRounds int) (*Parameters, error) {
+	if width != 2 && width != 3 {
+		return nil, ErrInvalidWidth
+	}
+	if nbFullRounds%2 != 0 {
+		return nil, ErrInvalidFullRounds
+	}
+	qMinusOne := new(big.Int).Sub(modulus, big.NewInt(1))
+	if new(big.Int).GCD(nil, nil, big.NewInt(int64(degreeSBox)), qMinusOne).Cmp(big.NewInt(1)) != 0 {
+		return nil, ErrInvalidSBox
+	}
+	p := &Parameters{
+		Field:           new(big.Int).Set(modulus),
+		Width:           width,
+		DegreeSBox:      degreeSBox,
+		NbFullRounds:    nbFullRounds,
+		NbPartialRounds: nbPartialRounds,
+	}
+	p.initRoundKeys()
+	return p, nil
+}
+
+// GetDefaultParameters returns the width-2 Poseidon2 instance used for
+// compression and Merkle-Damgård hashing over the scalar field of curve.
+//
+// The instances target 128 bits of security. The S-box degree is the smallest
+// one coprime with q-1 and the numbers of rounds are the ones given by the
+// round numbers script of the reference implementation, which includes the
+// security margin of 2 full rounds and 7.5% partial rounds. For BN254 and
+// BLS12-381 they are the reference instances R_F=8, R_P=56 with d=5. For
+// BLS12-377, d=5 is not a permutation and the script gives R_F=8, R_P=37 with
+// d=11.
+func GetDefaultParameters(curve ecc.ID) (*Parameters, error) {
+	switch curve {
+	case ecc.BN254:
+		return NewParameters(curve.ScalarField(), 2, 5, 8, 56)
+	case ecc.BLS12_377:
+		return NewParameters(curve.ScalarField(), 2, 11, 8, 37)
+	case ecc.BLS12_381:
+		return NewParameters(curve.ScalarField(), 2, 5, 8, 56)
+	default:
+		return nil, fmt.Errorf("poseidon2: no default parameters for curve %s", curve)
+	}
+}
+
+// String returns the description of the instance.
+func (p *Parameters) String() string {
+	return fmt.Sprintf("Poseidon2[q=%s,t=%d,rF=%d,rP=%d,d=%d]", p.Field.Text(16), p.Width, p.NbFullRounds, p.NbPartialRounds, p.DegreeSBox)
+}
+
+func (p *Parameters) initRoundKeys() {
+	n := p.Field.BitLen()
+	g := newGrainLFSR(n, p.Width, p.NbFullRounds, p.NbPartialRounds)
+
+	rf := p.NbFullRounds / 2
+	p.RoundKeys = make([][]*big.Int, p.NbFullRounds+p.NbPartialRounds)
+	for i := range p.RoundKeys {
+		nbKeys := 1
+		if i < rf || i >= rf+p.NbPartialRounds {
+			nbKeys = p.Width
+		}
+		p.RoundKeys[i] = make([]*big.Int, nbKeys)
+		for j := range p.RoundKeys[i] {
+			p.RoundKeys[i][j] = g.nextElement(n, p.Field)
+		}
+	}
+}
+
+// grainLFSR is the 80-bit self-shrinking Grain LFSR used by Poseidon and
+// Poseidon2 to sample the round constants.
+type grainLFSR struct {
+	state [80]uint8
+}
+
+// newGrainLFSR returns the LFSR initialised with the description of an
+// instance over a prime field of n bits with the S-box x -> x^d, and discards
+// the first 160 bits.
+func newGrainLFSR(n, width, nbFullRounds, nbPartialRounds int) *grainLFSR {
+	var g grainLFSR
+	i := 0
+	push := func(v, nbBits int) {
+		for j := nbBits - 1; j >= 0; j-- {
+			g.state[i] = uint8((v >> j) & 1)
+			i++
+		}
+	}
+	push(1, 2) // prime field
+	push(0, 4) // S-box x^d
+	push(n, 12)
+	push(width, 12)
+	push(nbFullRounds, 10)
+	push(nbPartialRounds, 10)
+	push(1<<30-1, 30)
+	for j := 0; j < 160; j++ {
+		g.clock()
+	}
+	return &g
+}
+
+func (g *grainLFSR) clock() uint8 {
+	s := &g.state
+	b := s[62] ^ s[51] ^ s[38] ^ s[23] ^ s[13] ^ s[0]
+	copy(s[:], s[1:])
+	s[79] = b
+	return b
+}
+
+// nextBit returns the next output bit. The bits are taken in pairs and the
+// second bit is output only if the first one is 1.
+func (g *grainLFSR) nextBit() uint8 {
+	for {
+		b1, b2 := g.clock(), g.clock()
+		if b1 == 1 {
+			return b2
+		}
+	}
+}
+
+// nextElement samples n bits, most significant first, until they encode an
+// integer smaller than modulus.
+func (g *grainLFSR) nextElement(n int, modulus *big.Int) *big.Int {
+	for {
+		r := new(big.Int)
+		for j := 0; j < n; j++ {
+			r.Lsh(r, 1)
+			if g.nextBit() == 1 {
+				r.SetBit(r, 0, 1)
+			}
+		}
+		if r.Cmp(modulus) < 0 {
+			return r
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package poseidon2 implements the Poseidon2 permutation over the native field.
+//
+// Poseidon2 [Poseidon2] is an arithmetization-friendly permutation which
+// requires considerably fewer constraints than MiMC. The permutation is
+// instantiated over the scalar field of the curve the circuit is defined over,
+// see [GetDefaultParameters] for the supported curves. This package exposes
+// only the permutation and the compression function. For hashing use the
+// [github.com/airchains-network/gnark/std/hash/poseidon2] package.
+//
+// The cost for a single compression over BN254 with the default parameters is:
+//   - 216 constraints in Groth16
+//   - 540 constraints in Plonk
+//
+// [Poseidon2]: https://eprint.iacr.org/2023/323
+package poseidon2
+
+import (
+	"errors"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/internal/utils"
+)
+
+// Permutation is the in-circuit Poseidon2 permutation.
+type Permutation struct {
+	api    frontend.API
+	params *Parameters
+}
+
+// NewPoseidon2 returns a new Poseidon2 permutation instance with the default
+// parameters for the native field.
+func NewPoseidon2(api frontend.API) (*Permutation, error) {
+	params, err := GetDefaultParameters(utils.FieldToCurve(api.Compiler().Field()))
+	if err != nil {
+		return nil, err
+	}
+	return NewPoseidon2FromParameters(api, params)
+}
+
+// NewPoseidon2FromParameters returns a new Poseidon2 permutation instance with
+// the given parameters. The field of the parameters must correspond to the
+// native field.
+func NewPoseidon2FromParameters(api frontend.API, params *Parameters) (*Permutation, error) {
+	if api.Compiler().Field().Cmp(params.Field) != 0 {
+		return nil, errors.New("poseidon2: parameters field mismatch")
+	}
+	return &Permutation{api: api, params: params}, nil
+}
+
+// Permutation applies the permutation on the input in place. The length of the
+// input must equal the width of the permutation.
+func (h *Permutation) Permutation(input []frontend.Variable) error {
+	if len(input) != h.params.Width {
+		return errors.New("poseidon2: invalid input length")
+	}
+	rf := h.params.NbFullRounds / 2
+
+	h.matMulExternal(input)
+	for i := 0; i < h.params.NbFullRounds+h.params.NbPartialRounds; i++ {
+		if i < rf || i >= rf+h.params.NbPartialRounds {
+			for j := range input {
+				input[j] = h.api.Add(input[j], h.params.RoundKeys[i][j])
+				input[j] = h.sBox(input[j])
+			}
+			h.matMulExternal(input)
+		} else {
+			input[0] = h.api.Add(input[0], h.params.RoundKeys[i][0])
+			input[0] = h.sBox(input[0])
+			h.matMulInternal(input)
+		}
+	}
+	return nil
+}
+
+// Compress applies the permutation on (left, right) and returns the second
+// element of the output added to right (feed-forward). It requires the width
+// of the permutation to be 2. It is the compression function used in
+// Merkle-Damgård construction.
+func (h *Permutation) Compress(left, right frontend.Variable) frontend.Variable {
+	if h.params.Width != 2 {
+		panic("poseidon2: compression requires width 2")
+	}
+	state := []frontend.Variable{left, right}
+	if err := h.Permutation(state); err != nil {
+		panic(err) // cannot happen, width is checked above
+	}
+	return h.api.Add(state[1], right)
+}
+
+// sBox computes x^d using square-and-multiply.
+func (h *Permutation) sBox(x frontend.Variable) frontend.Variable {
+	d := h.params.DegreeSBox
+	var res frontend.Variable
+	acc := x
+	for d > 0 {
+		if d&1 == 1 {
+			if res == nil {
+				res = acc
+			} else {
+				res = h.api.Mul(res, acc)
+			}
+		}
+		d >>= 1
+		if d > 0 {
+			acc = h.api.Mul(acc, acc)
+		}
+	}
+	return res
+}
+
+func (h *Permutation) matMulExternal(state []frontend.Variable) {
+	// see [Parameters.nativeMatMulExternal]
+	sum := h.api.Add(state[0], state[1], state[2:]...)
+	for i := range state {
+		state[i] = h.api.Add(state[i], sum)
+	}
+}
+
+func (h *Permutation) matMulInternal(state []frontend.Variable) {
+	// see [Parameters.nativeMatMulInternal]
+	last := state[len(state)-1]
+	h.matMulExternal(state)
+	state[len(state)-1] = h.api.Add(state[len(state)-1], last)
+}
@@ -0,0 +1,99 @@
+package poseidon2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+type compressCircuit struct {
+	Left, Right frontend.Variable
+	Expected    frontend.Variable
+}
+
+func (c *compressCircuit) Define(api frontend.API) error {
+	h, err := NewPoseidon2(api)
+	if err != nil {
+		return err
+	}
+	res := h.Compress(c.Left, c.Right)
+	api.AssertIsEqual(res, c.Expected)
+	return nil
+}
+
+func TestCompress(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381} {
+		params, err := GetDefaultParameters(curve)
+		assert.NoError(err)
+		left, right := big.NewInt(12345), new(big.Int).Sub(curve.ScalarField(), big.NewInt(1))
+		expected, err := params.Compress(left, right)
+		assert.NoError(err)
+		assert.CheckCircuit(&compressCircuit{},
+			test.WithValidAssignment(&compressCircuit{Left: left, Right: right, Expected: expected}),
+			test.WithInvalidAssignment(&compressCircuit{Left: left, Right: right, Expected: 0}),
+			test.WithCurves(curve))
+	}
+}
+
+type permutationCircuit struct {
+	In       [3]frontend.Variable
+	Expected [3]frontend.Variable
+	params   *Parameters
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	h, err := NewPoseidon2FromParameters(api, c.params)
+	if err != nil {
+		return err
+	}
+	state := c.In[:]
+	if err := h.Permutation(state); err != nil {
+		return err
+	}
+	for i := range state {
+		api.AssertIsEqual(state[i], c.Expected[i])
+	}
+	return nil
+}
+
+// testVectorBN254 is the test vector of the reference implementation for the
+// BN254 instance with t=3, d=5, R_F=8 and R_P=56: the permutation of (0, 1, 2).
+var testVectorBN254 = [3]string{
+	"0x0bb61d24daca55eebcb1929a82650f328134334da98ea4f847f760054f4a3033",
+	"0x303b6f7c86d043bfcbcc80214f26a30277a15d3f74ca654992defe7ff8d03570",
+	"0x1ed25194542b12eef8617361c3ba7c52e660b145994427cc86296242cf766ec8",
+}
+
+func TestPermutationWidth3(t *testing.T) {
+	assert := test.NewAssert(t)
+	params, err := NewParameters(ecc.BN254.ScalarField(), 3, 5, 8, 56)
+	assert.NoError(err)
+	// first round constant of the reference instance
+	assert.Equal("1d066a255517b7fd8bddd3a93f7804ef7f8fcde48bb4c37a59a09a1a97052816", params.RoundKeys[0][0].Text(16))
+
+	state := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2)}
+	assert.NoError(params.Permute(state))
+	witness := permutationCircuit{In: [3]frontend.Variable{0, 1, 2}}
+	for i := range state {
+		expected, ok := new(big.Int).SetString(testVectorBN254[i], 0)
+		assert.True(ok)
+		assert.Equal(0, expected.Cmp(state[i]), "element %d", i)
+		witness.Expected[i] = expected
+	}
+	err = test.IsSolved(&permutationCircuit{params: params}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestParameters(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, err := NewParameters(ecc.BN254.ScalarField(), 2, 3, 8, 56)
+	assert.ErrorIs(err, ErrInvalidSBox)
+	_, err = NewParameters(ecc.BN254.ScalarField(), 5, 5, 8, 56)
+	assert.ErrorIs(err, ErrInvalidWidth)
+	_, err = GetDefaultParameters(ecc.BW6_761)
+	assert.Error(err)
+}
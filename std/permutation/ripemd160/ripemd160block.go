@@ -0,0 +1,98 @@
+// Package ripemd160 implements the RIPEMD-160 compression function.
+//
+// This package exposes only the compression function. For the full hash
+// function see the [github.com/airchains-network/gnark/std/hash/ripemd160]
+// package.
+package ripemd160
+
+import (
+	"github.com/airchains-network/gnark/std/math/uints"
+)
+
+// message word selection and rotation amounts for the left line
+var (
+	_n = [80]int{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+		3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+		1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+		4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+	}
+	_r = [80]int{
+		11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+		7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+		11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+		11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+		9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+	}
+	_k = [5]uints.U32{
+		uints.NewU32(0x00000000), uints.NewU32(0x5a827999), uints.NewU32(0x6ed9eba1),
+		uints.NewU32(0x8f1bbcdc), uints.NewU32(0xa953fd4e),
+	}
+)
+
+// same for the parallel right line
+var (
+	n_ = [80]int{
+		5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+		6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+		15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+		8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+		12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+	}
+	r_ = [80]int{
+		8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+		9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+		9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+		15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+		8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+	}
+	k_ = [5]uints.U32{
+		uints.NewU32(0x50a28be6), uints.NewU32(0x5c4dd124), uints.NewU32(0x6d703ef3),
+		uints.NewU32(0x7a6d76e9), uints.NewU32(0x00000000),
+	}
+)
+
+// Permute applies the RIPEMD-160 compression function on the current state
+// with the 64-byte message block p and returns the new state.
+func Permute(uapi *uints.BinaryField[uints.U32], currentHash [5]uints.U32, p [64]uints.U8) (newHash [5]uints.U32) {
+	var x [16]uints.U32
+	for i := range x {
+		x[i] = uapi.PackLSB(p[4*i], p[4*i+1], p[4*i+2], p[4*i+3])
+	}
+
+	// the boolean functions. All ORs are either of disjoint values (and can be
+	// replaced with XOR) or rewritten using De Morgan's laws.
+	f := func(j int, b, c, d uints.U32) uints.U32 {
+		switch j / 16 {
+		case 0:
+			return uapi.Xor(b, c, d)
+		case 1:
+			return uapi.Xor(uapi.And(b, c), uapi.And(uapi.Not(b), d))
+		case 2:
+			return uapi.Xor(uapi.Not(uapi.And(uapi.Not(b), c)), d)
+		case 3:
+			return uapi.Xor(uapi.And(b, d), uapi.And(c, uapi.Not(d)))
+		default:
+			return uapi.Xor(b, uapi.Not(uapi.And(uapi.Not(c), d)))
+		}
+	}
+
+	a, b, c, d, e := currentHash[0], currentHash[1], currentHash[2], currentHash[3], currentHash[4]
+	aa, bb, cc, dd, ee := a, b, c, d, e
+	for j := 0; j < 80; j++ {
+		t := uapi.Add(uapi.Lrot(uapi.Add(a, f(j, b, c, d), x[_n[j]], _k[j/16]), _r[j]), e)
+		a, b, c, d, e = e, t, b, uapi.Lrot(c, 10), d
+
+		// parallel line
+		t = uapi.Add(uapi.Lrot(uapi.Add(aa, f(79-j, bb, cc, dd), x[n_[j]], k_[j/16]), r_[j]), ee)
+		aa, bb, cc, dd, ee = ee, t, bb, uapi.Lrot(cc, 10), dd
+	}
+
+	newHash[0] = uapi.Add(currentHash[1], c, dd)
+	newHash[1] = uapi.Add(currentHash[2], d, ee)
+	newHash[2] = uapi.Add(currentHash[3], e, aa)
+	newHash[3] = uapi.Add(currentHash[4], a, bb)
+	newHash[4] = uapi.Add(currentHash[0], b, cc)
+	return
+}
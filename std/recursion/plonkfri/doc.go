@@ -0,0 +1,11 @@
+// Package plonkfri implements in-circuit PlonkFRI verifier.
+//
+// As FRI does not use pairings, the verifier works directly over the native
+// field of the outer circuit. It means that the inner proof has to be computed
+// over the scalar field of the outer circuit, for example a BN254 PlonkFRI
+// proof is verified in a BN254 circuit. To make the inner proof efficiently
+// verifiable, the native prover and verifier must use the MiMC-based hash
+// functions set by [GetNativeProverOptions] and [GetNativeVerifierOptions],
+// also at setup with
+// [github.com/airchains-network/gnark/backend/plonkfri.SetupWithOptions].
+package plonkfri
@@ -0,0 +1,151 @@
+package plonkfri_test
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/airchains-network/gnark/backend/groth16"
+	native_plonkfri "github.com/airchains-network/gnark/backend/plonkfri"
+	"github.com/airchains-network/gnark/backend/witness"
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/r1cs"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/airchains-network/gnark/std/recursion/plonkfri"
+)
+
+// InnerCircuit is the user-defined circuit that we want to prove with
+// PlonkFRI and verify recursively.
+type InnerCircuit struct {
+	P, Q frontend.Variable
+	N    frontend.Variable `gnark:",public"`
+}
+
+func (c *InnerCircuit) Define(api frontend.API) error {
+	res := api.Mul(c.P, c.Q)
+	api.AssertIsEqual(res, c.N)
+	return nil
+}
+
+// computeInnerProof computes the PlonkFRI proof for the inner circuit over the
+// given field. The hash functions of the setup, prover and verifier are set to
+// be efficiently verifiable in-circuit.
+func computeInnerProof(field *big.Int) (constraint.ConstraintSystem, native_plonkfri.VerifyingKey, witness.Witness, native_plonkfri.Proof) {
+	innerCcs, err := frontend.Compile(field, scs.NewBuilder, &InnerCircuit{})
+	if err != nil {
+		panic(err)
+	}
+	innerPK, innerVK, err := native_plonkfri.SetupWithOptions(innerCcs, plonkfri.GetNativeProverOptions(field))
+	if err != nil {
+		panic(err)
+	}
+	innerAssignment := &InnerCircuit{
+		P: 3,
+		Q: 5,
+		N: 15,
+	}
+	innerWitness, err := frontend.NewWitness(innerAssignment, field)
+	if err != nil {
+		panic(err)
+	}
+	innerProof, err := native_plonkfri.Prove(innerCcs, innerPK, innerWitness, plonkfri.GetNativeProverOptions(field))
+	if err != nil {
+		panic(err)
+	}
+	innerPubWitness, err := innerWitness.Public()
+	if err != nil {
+		panic(err)
+	}
+	err = native_plonkfri.Verify(innerProof, innerVK, innerPubWitness, plonkfri.GetNativeVerifierOptions(field))
+	if err != nil {
+		panic(err)
+	}
+	return innerCcs, innerVK, innerPubWitness, innerProof
+}
+
+// OuterCircuit is the generic outer circuit which can verify PlonkFRI proofs.
+type OuterCircuit struct {
+	Proof        plonkfri.Proof
+	VerifyingKey plonkfri.VerifyingKey `gnark:"-"` // the verifying key is a constant of the circuit
+	InnerWitness plonkfri.Witness      `gnark:",public"`
+}
+
+func (c *OuterCircuit) Define(api frontend.API) error {
+	verifier, err := plonkfri.NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	return verifier.AssertProof(c.VerifyingKey, c.Proof, c.InnerWitness)
+}
+
+// Example of verifying recursively BN254 PlonkFRI proof in BN254 Groth16
+// circuit. As FRI does not need pairings, the inner proof is verified over the
+// native field of the outer circuit.
+func Example_native() {
+	// compute the proof which we want to verify recursively
+	innerCcs, innerVK, innerWitness, innerProof := computeInnerProof(ecc.BN254.ScalarField())
+
+	// initialize the witness elements
+	circuitVk, err := plonkfri.ValueOfVerifyingKey(innerVK)
+	if err != nil {
+		panic(err)
+	}
+	circuitWitness, err := plonkfri.ValueOfWitness(innerWitness)
+	if err != nil {
+		panic(err)
+	}
+	circuitProof, err := plonkfri.ValueOfProof(innerProof)
+	if err != nil {
+		panic(err)
+	}
+
+	// the witness size depends on the size of the inner circuit. We use the
+	// compiled inner circuit to deduce the required size for the outer witness
+	// using functions [plonkfri.PlaceholderWitness] and
+	// [plonkfri.PlaceholderProof].
+	outerCircuit := &OuterCircuit{
+		InnerWitness: plonkfri.PlaceholderWitness(innerCcs),
+		Proof:        plonkfri.PlaceholderProof(innerCcs),
+		VerifyingKey: circuitVk,
+	}
+	outerAssignment := &OuterCircuit{
+		InnerWitness: circuitWitness,
+		Proof:        circuitProof,
+	}
+
+	// compile the outer circuit over the same field as the inner circuit
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, outerCircuit)
+	if err != nil {
+		panic("compile failed: " + err.Error())
+	}
+
+	// create Groth16 setup. NB! UNSAFE
+	pk, vk, err := groth16.Setup(ccs) // UNSAFE! Use MPC
+	if err != nil {
+		panic("setup failed: " + err.Error())
+	}
+
+	// create prover witness from the assignment
+	secretWitness, err := frontend.NewWitness(outerAssignment, ecc.BN254.ScalarField())
+	if err != nil {
+		panic("secret witness failed: " + err.Error())
+	}
+
+	// create public witness from the assignment
+	publicWitness, err := secretWitness.Public()
+	if err != nil {
+		panic("public witness failed: " + err.Error())
+	}
+
+	// construct the Groth16 proof of verifying PlonkFRI proof in-circuit
+	outerProof, err := groth16.Prove(ccs, pk, secretWitness)
+	if err != nil {
+		panic("proving failed: " + err.Error())
+	}
+
+	// verify the Groth16 proof
+	err = groth16.Verify(outerProof, vk, publicWitness)
+	if err != nil {
+		panic("circuit verification failed: " + err.Error())
+	}
+}
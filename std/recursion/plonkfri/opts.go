@@ -0,0 +1,59 @@
+package plonkfri
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/airchains-network/gnark/backend"
+	"github.com/airchains-network/gnark/std/recursion"
+)
+
+// GetNativeProverOptions returns PlonkFRI prover options for the native setup
+// and prover to initialize the configuration suitable for in-circuit
+// verification. The field is the scalar field of the inner and outer circuits.
+func GetNativeProverOptions(field *big.Int) backend.ProverOption {
+	return func(pc *backend.ProverConfig) error {
+		fsProverHasher, err := recursion.NewShort(field, field)
+		if err != nil {
+			return fmt.Errorf("get prover fs hash: %w", err)
+		}
+		ioppProverHasher, err := recursion.NewShort(field, field)
+		if err != nil {
+			return fmt.Errorf("get prover iopp hash: %w", err)
+		}
+		fsOpt := backend.WithProverChallengeHashFunction(fsProverHasher)
+		if err = fsOpt(pc); err != nil {
+			return fmt.Errorf("apply prover fs hash option: %w", err)
+		}
+		ioppOpt := backend.WithProverIOPPHashFunction(ioppProverHasher)
+		if err = ioppOpt(pc); err != nil {
+			return fmt.Errorf("apply prover iopp hash option: %w", err)
+		}
+		return nil
+	}
+}
+
+// GetNativeVerifierOptions returns PlonkFRI verifier options to initialize the
+// configuration to be compatible with in-circuit verification. The field is
+// the scalar field of the inner and outer circuits.
+func GetNativeVerifierOptions(field *big.Int) backend.VerifierOption {
+	return func(vc *backend.VerifierConfig) error {
+		fsVerifierHasher, err := recursion.NewShort(field, field)
+		if err != nil {
+			return fmt.Errorf("get verifier fs hash: %w", err)
+		}
+		ioppVerifierHasher, err := recursion.NewShort(field, field)
+		if err != nil {
+			return fmt.Errorf("get verifier iopp hash: %w", err)
+		}
+		fsOpt := backend.WithVerifierChallengeHashFunction(fsVerifierHasher)
+		if err = fsOpt(vc); err != nil {
+			return fmt.Errorf("apply verifier fs hash option: %w", err)
+		}
+		ioppOpt := backend.WithVerifierIOPPHashFunction(ioppVerifierHasher)
+		if err = ioppOpt(vc); err != nil {
+			return fmt.Errorf("apply verifier iopp hash option: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,863 @@
+package plonkfri
+
+import (
+	"fmt"
+	"math/big"
+	stdbits "math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	fr_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	fft_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	fft_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	fft_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
+	fft_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
+	fft_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	fft_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/fft"
+	fft_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/fft"
+	fri_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fri"
+	fri_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fri"
+	fri_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fri"
+	fri_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fri"
+	fri_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/fri"
+	fri_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/fri"
+	fri_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/fri"
+	backend_plonkfri "github.com/airchains-network/gnark/backend/plonkfri"
+	plonkfribackend_bls12377 "github.com/airchains-network/gnark/backend/plonkfri/bls12-377"
+	plonkfribackend_bls12381 "github.com/airchains-network/gnark/backend/plonkfri/bls12-381"
+	plonkfribackend_bls24315 "github.com/airchains-network/gnark/backend/plonkfri/bls24-315"
+	plonkfribackend_bls24317 "github.com/airchains-network/gnark/backend/plonkfri/bls24-317"
+	plonkfribackend_bn254 "github.com/airchains-network/gnark/backend/plonkfri/bn254"
+	plonkfribackend_bw6633 "github.com/airchains-network/gnark/backend/plonkfri/bw6-633"
+	plonkfribackend_bw6761 "github.com/airchains-network/gnark/backend/plonkfri/bw6-761"
+	"github.com/airchains-network/gnark/backend/witness"
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/accumulator/merkle"
+	"github.com/airchains-network/gnark/std/commitments/fri"
+	fiatshamir "github.com/airchains-network/gnark/std/fiat-shamir"
+	"github.com/airchains-network/gnark/std/hash/mimc"
+)
+
+// same constant as in gnark-crypto
+const rho = 8
+
+// OpeningProof is an opening of a committed polynomial at a position of the
+// FRI domain. The first entry of the Merkle path is the opened value.
+type OpeningProof struct {
+	Path []frontend.Variable
+}
+
+// Proof is a typed PlonkFRI proof of SNARK. Use [ValueOfProof] to initialize
+// the witness from the native proof. Use [PlaceholderProof] to initialize the
+// placeholder witness for compiling the circuit.
+type Proof struct {
+
+	// commitments to the solution vectors
+	LROpp [3]fri.ProofOfProximity
+
+	// commitment to Z (permutation polynomial)
+	Zpp fri.ProofOfProximity
+
+	// commitments to h1, h2, h3 such that h = h1 + x**n*h2 + x**2n*h3
+	Hpp [3]fri.ProofOfProximity
+
+	// opening proofs for L, R, O
+	OpeningsLROmp [3]OpeningProof
+
+	// opening proofs for Z, Zu
+	OpeningsZmp [2]OpeningProof
+
+	// opening proof for H
+	OpeningsHmp [3]OpeningProof
+
+	// opening proofs for ql, qr, qm, qo, qk
+	OpeningsQlQrQmQoQkincompletemp [5]OpeningProof
+
+	// openings of S1, S2, S3
+	OpeningsS1S2S3mp [3]OpeningProof
+
+	// openings of Id1, Id2, Id3
+	OpeningsId1Id2Id3mp [3]OpeningProof
+}
+
+// VerifyingKey is a typed PlonkFRI verification key. Use [ValueOfVerifyingKey]
+// for initializing.
+//
+// Only the Merkle roots of the committed polynomials are kept from the
+// proofs of proximity of the native verification key. The proximity of the
+// polynomials of the verification key is not checked in-circuit, so the roots
+// are constants of the circuit and not part of the witness. The verifying key
+// must thus be set when compiling the outer circuit.
+type VerifyingKey struct {
+	// Size circuit
+	Size              uint64
+	NbPublicVariables uint64
+
+	// Merkle roots of the commitments to S1, S2, S3
+	S [3]*big.Int `gnark:"-"`
+
+	// Merkle roots of the commitments to Id1, Id2, Id3
+	Id [3]*big.Int `gnark:"-"`
+
+	// Merkle roots of the commitments to ql, qr, qm, qo, qk prepended with as
+	// many zeroes (ones for l) as there are public inputs. In particular Qk is
+	// not complete.
+	Q [5]*big.Int `gnark:"-"`
+}
+
+// Witness is a public witness to verify the PlonkFRI proof for. Use
+// [ValueOfWitness] or [PlaceholderWitness] for initializing.
+type Witness struct {
+	// Public is the public inputs. The first element does not need to be one
+	// wire and is not treated as such.
+	Public []frontend.Variable
+}
+
+// ValueOfProof returns the typed witness of the native proof. It returns an
+// error if the native proof is not a PlonkFRI proof.
+func ValueOfProof(proof backend_plonkfri.Proof) (Proof, error) {
+	var ret Proof
+	// the proofs of proximity of L, R, O, Z, H1, H2, H3 and the proof sets of
+	// the openings in the order of the fields of the proof.
+	var pps [7][]nativeRound
+	var proofSets [][][]byte
+	switch p := proof.(type) {
+	case *plonkfribackend_bls12377.Proof:
+		for i, pp := range []fri_bls12377.ProofOfProximity{p.LROpp[0], p.LROpp[1], p.LROpp[2], p.Zpp, p.Hpp[0], p.Hpp[1], p.Hpp[2]} {
+			pps[i] = nativeRoundsBLS12377(pp)
+		}
+		for _, ops := range [][]fri_bls12377.OpeningProof{p.OpeningsLROmp[:], p.OpeningsZmp[:], p.OpeningsHmp[:], p.OpeningsQlQrQmQoQkincompletemp[:], p.OpeningsS1S2S3mp[:], p.OpeningsId1Id2Id3mp[:]} {
+			for i := range ops {
+				proofSets = append(proofSets, ops[i].ProofSet)
+			}
+		}
+	case *plonkfribackend_bls12381.Proof:
+		for i, pp := range []fri_bls12381.ProofOfProximity{p.LROpp[0], p.LROpp[1], p.LROpp[2], p.Zpp, p.Hpp[0], p.Hpp[1], p.Hpp[2]} {
+			pps[i] = nativeRoundsBLS12381(pp)
+		}
+		for _, ops := range [][]fri_bls12381.OpeningProof{p.OpeningsLROmp[:], p.OpeningsZmp[:], p.OpeningsHmp[:], p.OpeningsQlQrQmQoQkincompletemp[:], p.OpeningsS1S2S3mp[:], p.OpeningsId1Id2Id3mp[:]} {
+			for i := range ops {
+				proofSets = append(proofSets, ops[i].ProofSet)
+			}
+		}
+	case *plonkfribackend_bls24315.Proof:
+		for i, pp := range []fri_bls24315.ProofOfProximity{p.LROpp[0], p.LROpp[1], p.LROpp[2], p.Zpp, p.Hpp[0], p.Hpp[1], p.Hpp[2]} {
+			pps[i] = nativeRoundsBLS24315(pp)
+		}
+		for _, ops := range [][]fri_bls24315.OpeningProof{p.OpeningsLROmp[:], p.OpeningsZmp[:], p.OpeningsHmp[:], p.OpeningsQlQrQmQoQkincompletemp[:], p.OpeningsS1S2S3mp[:], p.OpeningsId1Id2Id3mp[:]} {
+			for i := range ops {
+				proofSets = append(proofSets, ops[i].ProofSet)
+			}
+		}
+	case *plonkfribackend_bls24317.Proof:
+		for i, pp := range []fri_bls24317.ProofOfProximity{p.LROpp[0], p.LROpp[1], p.LROpp[2], p.Zpp, p.Hpp[0], p.Hpp[1], p.Hpp[2]} {
+			pps[i] = nativeRoundsBLS24317(pp)
+		}
+		for _, ops := range [][]fri_bls24317.OpeningProof{p.OpeningsLROmp[:], p.OpeningsZmp[:], p.OpeningsHmp[:], p.OpeningsQlQrQmQoQkincompletemp[:], p.OpeningsS1S2S3mp[:], p.OpeningsId1Id2Id3mp[:]} {
+			for i := range ops {
+				proofSets = append(proofSets, ops[i].ProofSet)
+			}
+		}
+	case *plonkfribackend_bn254.Proof:
+		for i, pp := range []fri_bn254.ProofOfProximity{p.LROpp[0], p.LROpp[1], p.LROpp[2], p.Zpp, p.Hpp[0], p.Hpp[1], p.Hpp[2]} {
+			pps[i] = nativeRoundsBN254(pp)
+		}
+		for _, ops := range [][]fri_bn254.OpeningProof{p.OpeningsLROmp[:], p.OpeningsZmp[:], p.OpeningsHmp[:], p.OpeningsQlQrQmQoQkincompletemp[:], p.OpeningsS1S2S3mp[:], p.OpeningsId1Id2Id3mp[:]} {
+			for i := range ops {
+				proofSets = append(proofSets, ops[i].ProofSet)
+			}
+		}
+	case *plonkfribackend_bw6633.Proof:
+		for i, pp := range []fri_bw6633.ProofOfProximity{p.LROpp[0], p.LROpp[1], p.LROpp[2], p.Zpp, p.Hpp[0], p.Hpp[1], p.Hpp[2]} {
+			pps[i] = nativeRoundsBW6633(pp)
+		}
+		for _, ops := range [][]fri_bw6633.OpeningProof{p.OpeningsLROmp[:], p.OpeningsZmp[:], p.OpeningsHmp[:], p.OpeningsQlQrQmQoQkincompletemp[:], p.OpeningsS1S2S3mp[:], p.OpeningsId1Id2Id3mp[:]} {
+			for i := range ops {
+				proofSets = append(proofSets, ops[i].ProofSet)
+			}
+		}
+	case *plonkfribackend_bw6761.Proof:
+		for i, pp := range []fri_bw6761.ProofOfProximity{p.LROpp[0], p.LROpp[1], p.LROpp[2], p.Zpp, p.Hpp[0], p.Hpp[1], p.Hpp[2]} {
+			pps[i] = nativeRoundsBW6761(pp)
+		}
+		for _, ops := range [][]fri_bw6761.OpeningProof{p.OpeningsLROmp[:], p.OpeningsZmp[:], p.OpeningsHmp[:], p.OpeningsQlQrQmQoQkincompletemp[:], p.OpeningsS1S2S3mp[:], p.OpeningsId1Id2Id3mp[:]} {
+			for i := range ops {
+				proofSets = append(proofSets, ops[i].ProofSet)
+			}
+		}
+	default:
+		return ret, fmt.Errorf("unknown proof type %T", proof)
+	}
+
+	for i := range ret.LROpp {
+		ret.LROpp[i] = valueOfProofOfProximity(pps[i])
+	}
+	ret.Zpp = valueOfProofOfProximity(pps[3])
+	for i := range ret.Hpp {
+		ret.Hpp[i] = valueOfProofOfProximity(pps[4+i])
+	}
+	for _, ops := range [][]OpeningProof{
+		ret.OpeningsLROmp[:], ret.OpeningsZmp[:], ret.OpeningsHmp[:],
+		ret.OpeningsQlQrQmQoQkincompletemp[:], ret.OpeningsS1S2S3mp[:],
+		ret.OpeningsId1Id2Id3mp[:],
+	} {
+		for i := range ops {
+			ops[i] = valueOfOpeningProof(proofSets[0])
+			proofSets = proofSets[1:]
+		}
+	}
+	return ret, nil
+}
+
+// PlaceholderProof returns a placeholder proof witness to be use for compiling
+// the outer circuit for witness alignment. For actual witness assignment use
+// [ValueOfProof].
+func PlaceholderProof(ccs constraint.ConstraintSystem) Proof {
+	var ret Proof
+	size := domainSize(ccs)
+
+	// the Iopp is created with size+2 to handle the blinding, and the
+	// polynomials are evaluated on a domain rho times larger.
+	ioppSize := rho * ecc.NextPowerOfTwo(size+2)
+	nbSteps := stdbits.TrailingZeros64(ecc.NextPowerOfTwo(size + 2))
+	placeholderPoP := func() fri.ProofOfProximity {
+		var pp fri.ProofOfProximity
+		pp.Rounds = make([]fri.Round, 1)
+		pp.Rounds[0].Interactions = make([][2]merkle.MerkleProof, nbSteps)
+		for i := 0; i < nbSteps; i++ {
+			depth := stdbits.TrailingZeros64(ioppSize >> i)
+			pp.Rounds[0].Interactions[i][0].Path = make([]frontend.Variable, depth+1)
+			pp.Rounds[0].Interactions[i][1].Path = make([]frontend.Variable, depth+1)
+		}
+		return pp
+	}
+	depth := stdbits.TrailingZeros64(ioppSize)
+	placeholderOpening := func() OpeningProof {
+		return OpeningProof{Path: make([]frontend.Variable, depth+1)}
+	}
+
+	for i := range ret.LROpp {
+		ret.LROpp[i] = placeholderPoP()
+	}
+	ret.Zpp = placeholderPoP()
+	for i := range ret.Hpp {
+		ret.Hpp[i] = placeholderPoP()
+	}
+	for i := range ret.OpeningsLROmp {
+		ret.OpeningsLROmp[i] = placeholderOpening()
+	}
+	for i := range ret.OpeningsZmp {
+		ret.OpeningsZmp[i] = placeholderOpening()
+	}
+	for i := range ret.OpeningsHmp {
+		ret.OpeningsHmp[i] = placeholderOpening()
+	}
+	for i := range ret.OpeningsQlQrQmQoQkincompletemp {
+		ret.OpeningsQlQrQmQoQkincompletemp[i] = placeholderOpening()
+	}
+	for i := range ret.OpeningsS1S2S3mp {
+		ret.OpeningsS1S2S3mp[i] = placeholderOpening()
+	}
+	for i := range ret.OpeningsId1Id2Id3mp {
+		ret.OpeningsId1Id2Id3mp[i] = placeholderOpening()
+	}
+	return ret
+}
+
+// ValueOfVerifyingKey initializes the constant verifying key of the outer
+// circuit from the given PlonkFRI verifying key. It returns an error if the
+// verifying key is not a PlonkFRI verifying key.
+func ValueOfVerifyingKey(vk backend_plonkfri.VerifyingKey) (VerifyingKey, error) {
+	var ret VerifyingKey
+	// the proofs of proximity of S1, S2, S3, Id1, Id2, Id3, Ql, Qr, Qm, Qo, Qk
+	var pps [11][]nativeRound
+	switch v := vk.(type) {
+	case *plonkfribackend_bls12377.VerifyingKey:
+		ret.Size, ret.NbPublicVariables = v.Size, v.NbPublicVariables
+		for i, pp := range []fri_bls12377.ProofOfProximity{v.Spp[0], v.Spp[1], v.Spp[2], v.Idpp[0], v.Idpp[1], v.Idpp[2], v.Qpp[0], v.Qpp[1], v.Qpp[2], v.Qpp[3], v.Qpp[4]} {
+			pps[i] = nativeRoundsBLS12377(pp)
+		}
+	case *plonkfribackend_bls12381.VerifyingKey:
+		ret.Size, ret.NbPublicVariables = v.Size, v.NbPublicVariables
+		for i, pp := range []fri_bls12381.ProofOfProximity{v.Spp[0], v.Spp[1], v.Spp[2], v.Idpp[0], v.Idpp[1], v.Idpp[2], v.Qpp[0], v.Qpp[1], v.Qpp[2], v.Qpp[3], v.Qpp[4]} {
+			pps[i] = nativeRoundsBLS12381(pp)
+		}
+	case *plonkfribackend_bls24315.VerifyingKey:
+		ret.Size, ret.NbPublicVariables = v.Size, v.NbPublicVariables
+		for i, pp := range []fri_bls24315.ProofOfProximity{v.Spp[0], v.Spp[1], v.Spp[2], v.Idpp[0], v.Idpp[1], v.Idpp[2], v.Qpp[0], v.Qpp[1], v.Qpp[2], v.Qpp[3], v.Qpp[4]} {
+			pps[i] = nativeRoundsBLS24315(pp)
+		}
+	case *plonkfribackend_bls24317.VerifyingKey:
+		ret.Size, ret.NbPublicVariables = v.Size, v.NbPublicVariables
+		for i, pp := range []fri_bls24317.ProofOfProximity{v.Spp[0], v.Spp[1], v.Spp[2], v.Idpp[0], v.Idpp[1], v.Idpp[2], v.Qpp[0], v.Qpp[1], v.Qpp[2], v.Qpp[3], v.Qpp[4]} {
+			pps[i] = nativeRoundsBLS24317(pp)
+		}
+	case *plonkfribackend_bn254.VerifyingKey:
+		ret.Size, ret.NbPublicVariables = v.Size, v.NbPublicVariables
+		for i, pp := range []fri_bn254.ProofOfProximity{v.Spp[0], v.Spp[1], v.Spp[2], v.Idpp[0], v.Idpp[1], v.Idpp[2], v.Qpp[0], v.Qpp[1], v.Qpp[2], v.Qpp[3], v.Qpp[4]} {
+			pps[i] = nativeRoundsBN254(pp)
+		}
+	case *plonkfribackend_bw6633.VerifyingKey:
+		ret.Size, ret.NbPublicVariables = v.Size, v.NbPublicVariables
+		for i, pp := range []fri_bw6633.ProofOfProximity{v.Spp[0], v.Spp[1], v.Spp[2], v.Idpp[0], v.Idpp[1], v.Idpp[2], v.Qpp[0], v.Qpp[1], v.Qpp[2], v.Qpp[3], v.Qpp[4]} {
+			pps[i] = nativeRoundsBW6633(pp)
+		}
+	case *plonkfribackend_bw6761.VerifyingKey:
+		ret.Size, ret.NbPublicVariables = v.Size, v.NbPublicVariables
+		for i, pp := range []fri_bw6761.ProofOfProximity{v.Spp[0], v.Spp[1], v.Spp[2], v.Idpp[0], v.Idpp[1], v.Idpp[2], v.Qpp[0], v.Qpp[1], v.Qpp[2], v.Qpp[3], v.Qpp[4]} {
+			pps[i] = nativeRoundsBW6761(pp)
+		}
+	default:
+		return ret, fmt.Errorf("unknown verifying key type %T", vk)
+	}
+
+	roots := make([]*big.Int, len(pps))
+	for i := range pps {
+		if len(pps[i]) == 0 || len(pps[i][0].interactions) == 0 {
+			return ret, fmt.Errorf("proof of proximity %d of the verifying key is empty", i)
+		}
+		roots[i] = new(big.Int).SetBytes(pps[i][0].interactions[0][0].root)
+	}
+	copy(ret.S[:], roots[:3])
+	copy(ret.Id[:], roots[3:6])
+	copy(ret.Q[:], roots[6:])
+	return ret, nil
+}
+
+// ValueOfWitness assigns a outer-circuit witness from the inner circuit
+// witness. It returns an error if the witness is not defined over one of the
+// fields supported by PlonkFRI.
+func ValueOfWitness(w witness.Witness) (Witness, error) {
+	var ret Witness
+	pubw, err := w.Public()
+	if err != nil {
+		return ret, fmt.Errorf("get public witness: %w", err)
+	}
+	switch vec := pubw.Vector().(type) {
+	case fr_bls12377.Vector:
+		for i := range vec {
+			ret.Public = append(ret.Public, vec[i].BigInt(new(big.Int)))
+		}
+	case fr_bls12381.Vector:
+		for i := range vec {
+			ret.Public = append(ret.Public, vec[i].BigInt(new(big.Int)))
+		}
+	case fr_bls24315.Vector:
+		for i := range vec {
+			ret.Public = append(ret.Public, vec[i].BigInt(new(big.Int)))
+		}
+	case fr_bls24317.Vector:
+		for i := range vec {
+			ret.Public = append(ret.Public, vec[i].BigInt(new(big.Int)))
+		}
+	case fr_bn254.Vector:
+		for i := range vec {
+			ret.Public = append(ret.Public, vec[i].BigInt(new(big.Int)))
+		}
+	case fr_bw6633.Vector:
+		for i := range vec {
+			ret.Public = append(ret.Public, vec[i].BigInt(new(big.Int)))
+		}
+	case fr_bw6761.Vector:
+		for i := range vec {
+			ret.Public = append(ret.Public, vec[i].BigInt(new(big.Int)))
+		}
+	default:
+		return ret, fmt.Errorf("unexpected witness vector type %T", pubw.Vector())
+	}
+	return ret, nil
+}
+
+// PlaceholderWitness creates a stub witness which can be used to allocate the
+// variables in the circuit if the actual witness is not yet known.
+func PlaceholderWitness(ccs constraint.ConstraintSystem) Witness {
+	return Witness{
+		Public: make([]frontend.Variable, ccs.GetNbPublicVariables()),
+	}
+}
+
+// Verifier verifies PlonkFRI proofs.
+type Verifier struct {
+	api frontend.API
+}
+
+// NewVerifier returns a new [Verifier] instance. It returns an error if the
+// native field of the circuit does not have a PlonkFRI backend.
+func NewVerifier(api frontend.API) (*Verifier, error) {
+	if _, err := friGenerator(api.Compiler().Field(), 1); err != nil {
+		return nil, err
+	}
+	return &Verifier{api: api}, nil
+}
+
+// AssertProof asserts that the proof is valid for the given verification key
+// and public witness. It mirrors the native PlonkFRI verifier. The Merkle roots
+// of the verification key are constants of the circuit.
+func (v *Verifier) AssertProof(vk VerifyingKey, proof Proof, witness Witness) error {
+	api := v.api
+	field := api.Compiler().Field()
+	if len(witness.Public) != int(vk.NbPublicVariables) {
+		return fmt.Errorf("invalid witness size, got %d, expected %d", len(witness.Public), vk.NbPublicVariables)
+	}
+	for _, roots := range [][]*big.Int{vk.S[:], vk.Id[:], vk.Q[:]} {
+		for i := range roots {
+			if roots[i] == nil {
+				return fmt.Errorf("verifying key not initialized, use ValueOfVerifyingKey")
+			}
+		}
+	}
+
+	// constants of the domains. The polynomials are opened at genOpening^{i}
+	// where i is the opening position, and genOpening is the generator of the
+	// FRI domain of size 2*rho*vk.Size.
+	friSize := 2 * rho * vk.Size
+	logFriSize := stdbits.TrailingZeros64(friSize)
+	genOpening, err := friGenerator(field, friSize)
+	if err != nil {
+		return err
+	}
+	var generator, sizeInv big.Int
+	generator.Exp(genOpening, big.NewInt(2*rho), field)
+	sizeInv.SetUint64(vk.Size).ModInverse(&sizeInv, field)
+
+	// 0 - derive the challenges with Fiat Shamir
+	fsHash, err := mimc.NewMiMC(api)
+	if err != nil {
+		return fmt.Errorf("new fs hash: %w", err)
+	}
+	fs := fiatshamir.NewTranscript(api, &fsHash, []string{"gamma", "beta", "alpha", "zeta"}, fiatshamir.WithDomainSeparation())
+
+	// as in the native verifier, the Merkle roots of the commitments to L, R,
+	// O, Z and H are bound before the challenges depending on them. The
+	// transcript chains the challenges, so the later ones depend on all the
+	// roots bound before.
+	if err := fs.Bind("gamma", witness.Public); err != nil {
+		return err
+	}
+	lroRoots := []frontend.Variable{
+		proofOfProximityRoot(proof.LROpp[0]),
+		proofOfProximityRoot(proof.LROpp[1]),
+		proofOfProximityRoot(proof.LROpp[2]),
+	}
+	if err := fs.Bind("gamma", lroRoots); err != nil {
+		return err
+	}
+	beta, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return err
+	}
+	gamma, err := fs.ComputeChallenge("beta")
+	if err != nil {
+		return err
+	}
+	if err := fs.Bind("alpha", []frontend.Variable{proofOfProximityRoot(proof.Zpp)}); err != nil {
+		return err
+	}
+	alpha, err := fs.ComputeChallenge("alpha")
+	if err != nil {
+		return err
+	}
+	hRoots := []frontend.Variable{
+		proofOfProximityRoot(proof.Hpp[0]),
+		proofOfProximityRoot(proof.Hpp[1]),
+		proofOfProximityRoot(proof.Hpp[2]),
+	}
+	if err := fs.Bind("zeta", hRoots); err != nil {
+		return err
+	}
+	zetaSeed, err := fs.ComputeChallenge("zeta")
+	if err != nil {
+		return err
+	}
+
+	// the opening position is the challenge modulo the size of the FRI
+	// domain, i.e. its least significant bits.
+	seedBits := api.ToBinary(zetaSeed)
+	positionBits := seedBits[:logFriSize]
+	openingPosition := api.FromBinary(positionBits...)
+	shiftedBits := api.ToBinary(api.Add(openingPosition, 2*rho), logFriSize+1)
+	shiftedPositionBits := shiftedBits[:logFriSize]
+
+	// 1 - verify that the commitments are low degree polynomials
+	friHash, err := mimc.NewMiMC(api)
+	if err != nil {
+		return fmt.Errorf("new iopp hash: %w", err)
+	}
+	var genOpeningInv big.Int
+	genOpeningInv.ModInverse(genOpening, field)
+	// the +2 is to handle the blinding.
+	iopp := fri.NewRadixTwoFri(ecc.NextPowerOfTwo(vk.Size+2), &friHash, genOpeningInv)
+	pps := []fri.ProofOfProximity{
+		proof.LROpp[0], proof.LROpp[1], proof.LROpp[2],
+		proof.Zpp,
+		proof.Hpp[0], proof.Hpp[1], proof.Hpp[2],
+	}
+	for i := range pps {
+		if err := iopp.VerifyProofOfProximity(api, pps[i]); err != nil {
+			return fmt.Errorf("proof of proximity %d: %w", i, err)
+		}
+	}
+
+	// 2 - verify the openings
+	position := sortedPosition(api, positionBits)
+	shiftedPosition := sortedPosition(api, shiftedPositionBits)
+	openings := []struct {
+		op   OpeningProof
+		root frontend.Variable
+	}{
+		{proof.OpeningsQlQrQmQoQkincompletemp[0], vk.Q[0]},
+		{proof.OpeningsQlQrQmQoQkincompletemp[1], vk.Q[1]},
+		{proof.OpeningsQlQrQmQoQkincompletemp[2], vk.Q[2]},
+		{proof.OpeningsQlQrQmQoQkincompletemp[3], vk.Q[3]},
+		{proof.OpeningsQlQrQmQoQkincompletemp[4], vk.Q[4]},
+		{proof.OpeningsLROmp[0], proofOfProximityRoot(proof.LROpp[0])},
+		{proof.OpeningsLROmp[1], proofOfProximityRoot(proof.LROpp[1])},
+		{proof.OpeningsLROmp[2], proofOfProximityRoot(proof.LROpp[2])},
+		{proof.OpeningsHmp[0], proofOfProximityRoot(proof.Hpp[0])},
+		{proof.OpeningsHmp[1], proofOfProximityRoot(proof.Hpp[1])},
+		{proof.OpeningsHmp[2], proofOfProximityRoot(proof.Hpp[2])},
+		{proof.OpeningsS1S2S3mp[0], vk.S[0]},
+		{proof.OpeningsS1S2S3mp[1], vk.S[1]},
+		{proof.OpeningsS1S2S3mp[2], vk.S[2]},
+		{proof.OpeningsId1Id2Id3mp[0], vk.Id[0]},
+		{proof.OpeningsId1Id2Id3mp[1], vk.Id[1]},
+		{proof.OpeningsId1Id2Id3mp[2], vk.Id[2]},
+		{proof.OpeningsZmp[0], proofOfProximityRoot(proof.Zpp)},
+	}
+	for i := range openings {
+		if len(openings[i].op.Path) != logFriSize+1 {
+			return fmt.Errorf("opening %d: invalid Merkle path length", i)
+		}
+		mp := merkle.MerkleProof{RootHash: openings[i].root, Path: openings[i].op.Path}
+		mp.VerifyProof(api, &friHash, position)
+	}
+	if len(proof.OpeningsZmp[1].Path) != logFriSize+1 {
+		return fmt.Errorf("shifted opening: invalid Merkle path length")
+	}
+	mp := merkle.MerkleProof{RootHash: proofOfProximityRoot(proof.Zpp), Path: proof.OpeningsZmp[1].Path}
+	mp.VerifyProof(api, &friHash, shiftedPosition)
+
+	// verification of the algebraic relation
+	ql := proof.OpeningsQlQrQmQoQkincompletemp[0].Path[0]
+	qr := proof.OpeningsQlQrQmQoQkincompletemp[1].Path[0]
+	qm := proof.OpeningsQlQrQmQoQkincompletemp[2].Path[0]
+	qo := proof.OpeningsQlQrQmQoQkincompletemp[3].Path[0]
+	qk := proof.OpeningsQlQrQmQoQkincompletemp[4].Path[0]
+	l := proof.OpeningsLROmp[0].Path[0]
+	r := proof.OpeningsLROmp[1].Path[0]
+	o := proof.OpeningsLROmp[2].Path[0]
+	h1 := proof.OpeningsHmp[0].Path[0]
+	h2 := proof.OpeningsHmp[1].Path[0]
+	h3 := proof.OpeningsHmp[2].Path[0]
+	s1 := proof.OpeningsS1S2S3mp[0].Path[0]
+	s2 := proof.OpeningsS1S2S3mp[1].Path[0]
+	s3 := proof.OpeningsS1S2S3mp[2].Path[0]
+	id1 := proof.OpeningsId1Id2Id3mp[0].Path[0]
+	id2 := proof.OpeningsId1Id2Id3mp[1].Path[0]
+	id3 := proof.OpeningsId1Id2Id3mp[2].Path[0]
+	z := proof.OpeningsZmp[0].Path[0]
+	zshift := proof.OpeningsZmp[1].Path[0]
+
+	// zeta = genOpening^{openingPosition}
+	var zeta frontend.Variable = 1
+	var acc big.Int
+	acc.Set(genOpening)
+	for i := range positionBits {
+		var c big.Int
+		c.Sub(&acc, big.NewInt(1))
+		zeta = api.Mul(zeta, api.Add(1, api.Mul(positionBits[i], &c)))
+		acc.Mul(&acc, &acc).Mod(&acc, field)
+	}
+	zetaPowerN := zeta
+	for i := uint64(1); i < vk.Size; i <<= 1 {
+		zetaPowerN = api.Mul(zetaPowerN, zetaPowerN)
+	}
+	zhZeta := api.Sub(zetaPowerN, 1)
+
+	// 2.1 (ql*l+..+qk)
+	t1 := api.Add(
+		api.Mul(l, ql),
+		api.Mul(r, qr),
+		api.Mul(qm, l, r),
+		api.Mul(o, qo),
+		qk,
+		v.completeQk(witness.Public, zeta, zhZeta, &generator, &sizeInv),
+	)
+
+	// 2.2 (z(ux)*(l+β*s1+γ)*..-z*(l+β*id1+γ))
+	t2 := api.Mul(
+		api.Add(l, api.Mul(beta, s1), gamma),
+		api.Add(r, api.Mul(beta, s2), gamma),
+		api.Add(o, api.Mul(beta, s3), gamma),
+		zshift,
+	)
+	t2 = api.Sub(t2, api.Mul(
+		api.Add(l, api.Mul(beta, id1), gamma),
+		api.Add(r, api.Mul(beta, id2), gamma),
+		api.Add(o, api.Mul(beta, id3), gamma),
+		z,
+	))
+
+	// 2.3 (z-1)*l1. As in the native verifier, the inverse of zero is zero.
+	den := api.Sub(zeta, 1)
+	denIsZero := api.IsZero(den)
+	denInv := api.DivUnchecked(1, api.Select(denIsZero, 1, den))
+	denInv = api.Select(denIsZero, 0, denInv)
+	t3 := api.Mul(zhZeta, denInv, &sizeInv, api.Sub(z, 1))
+
+	// 2.4 (ql*l+s+qk) + α*(z(ux)*(l+β*s1+γ)*...-z*(l+β*id1+γ)..)+ α²*z*(l1-1)
+	lhs := api.Add(api.Mul(api.Add(api.Mul(t3, alpha), t2), alpha), t1)
+
+	// 3 - compute the RHS
+	zetaPowerN2 := api.Mul(zetaPowerN, zeta, zeta)
+	rhs := api.Add(api.Mul(api.Add(api.Mul(h3, zetaPowerN2), h2), zetaPowerN2), h1)
+	rhs = api.Mul(rhs, zhZeta)
+
+	// 4 - verify the relation LHS==RHS
+	api.AssertIsEqual(lhs, rhs)
+
+	return nil
+}
+
+// completeQk returns ∑_{i<nb_public_inputs}w_i*L_i(ζ). The Lagrange
+// polynomials are evaluated with L_i(ζ) = ωⁱ(ζⁿ-1)/(n(ζ-ωⁱ)), and with
+// L_i(ζ) = 1 when ζ = ωⁱ.
+func (v *Verifier) completeQk(public []frontend.Variable, zeta, zhZeta frontend.Variable, generator, sizeInv *big.Int) frontend.Variable {
+	api := v.api
+	field := api.Compiler().Field()
+	var res frontend.Variable = 0
+	var acc big.Int
+	acc.SetUint64(1)
+	for i := range public {
+		var c big.Int
+		c.Mul(&acc, sizeInv).Mod(&c, field)
+		den := api.Sub(zeta, &acc)
+		isZero := api.IsZero(den)
+		li := api.DivUnchecked(api.Mul(zhZeta, &c), api.Select(isZero, 1, den))
+		li = api.Select(isZero, 1, li)
+		res = api.Add(res, api.Mul(li, public[i]))
+		acc.Mul(&acc, generator).Mod(&acc, field)
+	}
+	return res
+}
+
+// sortedPosition returns the position in the sorted FRI evaluations of the
+// position given by its bits (cf gnark-crypto). With n the size of the domain
+// and p the position, it is 2p if p < n/2 and 2p-(n-1) otherwise.
+func sortedPosition(api frontend.API, bits []frontend.Variable) frontend.Variable {
+	n := new(big.Int).Lsh(big.NewInt(1), uint(len(bits)))
+	n.Sub(n, big.NewInt(1))
+	p := api.FromBinary(bits...)
+	return api.Sub(api.Mul(p, 2), api.Mul(bits[len(bits)-1], n))
+}
+
+// proofOfProximityRoot returns the Merkle root of the committed polynomial.
+func proofOfProximityRoot(pp fri.ProofOfProximity) frontend.Variable {
+	return pp.Rounds[0].Interactions[0][0].RootHash
+}
+
+// domainSize returns the size of the evaluation domain of the PlonkFRI
+// polynomials of the circuit.
+func domainSize(ccs constraint.ConstraintSystem) uint64 {
+	// the public inputs are constrained with placeholder constraints
+	return ecc.NextPowerOfTwo(uint64(ccs.GetNbConstraints() + ccs.GetNbPublicVariables()))
+}
+
+// friGenerator returns the generator of the domain of size n used by the
+// PlonkFRI backend over the given field.
+func friGenerator(field *big.Int, n uint64) (*big.Int, error) {
+	res := new(big.Int)
+	switch field.String() {
+	case ecc.BLS12_377.ScalarField().String():
+		d := fft_bls12377.NewDomain(n)
+		d.Generator.BigInt(res)
+	case ecc.BLS12_381.ScalarField().String():
+		d := fft_bls12381.NewDomain(n)
+		d.Generator.BigInt(res)
+	case ecc.BLS24_315.ScalarField().String():
+		d := fft_bls24315.NewDomain(n)
+		d.Generator.BigInt(res)
+	case ecc.BLS24_317.ScalarField().String():
+		d := fft_bls24317.NewDomain(n)
+		d.Generator.BigInt(res)
+	case ecc.BN254.ScalarField().String():
+		d := fft_bn254.NewDomain(n)
+		d.Generator.BigInt(res)
+	case ecc.BW6_633.ScalarField().String():
+		d := fft_bw6633.NewDomain(n)
+		d.Generator.BigInt(res)
+	case ecc.BW6_761.ScalarField().String():
+		d := fft_bw6761.NewDomain(n)
+		d.Generator.BigInt(res)
+	default:
+		return nil, fmt.Errorf("no PlonkFRI backend for field %s", field.String())
+	}
+	return res, nil
+}
+
+// nativeRound is the content of a round of the FRI proofs of proximity of
+// gnark-crypto, which are generated for every curve with the same layout.
+type nativeRound struct {
+	interactions [][2]nativeMerkleProof
+	evaluation   *big.Int
+}
+
+// nativeMerkleProof is the content of a Merkle proof of an interaction of a
+// FRI round of gnark-crypto.
+type nativeMerkleProof struct {
+	root     []byte
+	proofSet [][]byte
+}
+
+// valueOfProofOfProximity returns the witness of the native proof of
+// proximity given by its rounds. In the native proof only one of the two
+// Merkle proofs of an interaction is complete, the other one shares all but
+// its two first entries.
+func valueOfProofOfProximity(rounds []nativeRound) fri.ProofOfProximity {
+	var ret fri.ProofOfProximity
+	ret.Rounds = make([]fri.Round, len(rounds))
+	for i := range ret.Rounds {
+		ret.Rounds[i].Evaluation = rounds[i].evaluation
+		ret.Rounds[i].Interactions = make([][2]merkle.MerkleProof, len(rounds[i].interactions))
+		for j, interaction := range rounds[i].interactions {
+			full := 0
+			if len(interaction[1].proofSet) > len(interaction[0].proofSet) {
+				full = 1
+			}
+			for k := range interaction {
+				mp := &ret.Rounds[i].Interactions[j][k]
+				mp.RootHash = new(big.Int).SetBytes(interaction[k].root)
+				mp.Path = make([]frontend.Variable, len(interaction[full].proofSet))
+				for l := range mp.Path {
+					if l < 2 {
+						mp.Path[l] = new(big.Int).SetBytes(interaction[k].proofSet[l])
+					} else {
+						mp.Path[l] = new(big.Int).SetBytes(interaction[full].proofSet[l])
+					}
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// valueOfOpeningProof returns the witness of the native opening proof given by
+// its proof set.
+func valueOfOpeningProof(proofSet [][]byte) OpeningProof {
+	ret := OpeningProof{Path: make([]frontend.Variable, len(proofSet))}
+	for i := range proofSet {
+		ret.Path[i] = new(big.Int).SetBytes(proofSet[i])
+	}
+	return ret
+}
+
+// nativeRoundsBLS12377 returns the rounds of the BLS12-377 proof of proximity pp.
+func nativeRoundsBLS12377(pp fri_bls12377.ProofOfProximity) []nativeRound {
+	rounds := make([]nativeRound, len(pp.Rounds))
+	for i, r := range pp.Rounds {
+		rounds[i].evaluation = r.Evaluation.BigInt(new(big.Int))
+		rounds[i].interactions = make([][2]nativeMerkleProof, len(r.Interactions))
+		for j, interaction := range r.Interactions {
+			for k := range interaction {
+				rounds[i].interactions[j][k] = nativeMerkleProof{root: interaction[k].MerkleRoot, proofSet: interaction[k].ProofSet}
+			}
+		}
+	}
+	return rounds
+}
+
+// nativeRoundsBLS12381 returns the rounds of the BLS12-381 proof of proximity pp.
+func nativeRoundsBLS12381(pp fri_bls12381.ProofOfProximity) []nativeRound {
+	rounds := make([]nativeRound, len(pp.Rounds))
+	for i, r := range pp.Rounds {
+		rounds[i].evaluation = r.Evaluation.BigInt(new(big.Int))
+		rounds[i].interactions = make([][2]nativeMerkleProof, len(r.Interactions))
+		for j, interaction := range r.Interactions {
+			for k := range interaction {
+				rounds[i].interactions[j][k] = nativeMerkleProof{root: interaction[k].MerkleRoot, proofSet: interaction[k].ProofSet}
+			}
+		}
+	}
+	return rounds
+}
+
+// nativeRoundsBLS24315 returns the rounds of the BLS24-315 proof of proximity pp.
+func nativeRoundsBLS24315(pp fri_bls24315.ProofOfProximity) []nativeRound {
+	rounds := make([]nativeRound, len(pp.Rounds))
+	for i, r := range pp.Rounds {
+		rounds[i].evaluation = r.Evaluation.BigInt(new(big.Int))
+		rounds[i].interactions = make([][2]nativeMerkleProof, len(r.Interactions))
+		for j, interaction := range r.Interactions {
+			for k := range interaction {
+				rounds[i].interactions[j][k] = nativeMerkleProof{root: interaction[k].MerkleRoot, proofSet: interaction[k].ProofSet}
+			}
+		}
+	}
+	return rounds
+}
+
+// nativeRoundsBLS24317 returns the rounds of the BLS24-317 proof of proximity pp.
+func nativeRoundsBLS24317(pp fri_bls24317.ProofOfProximity) []nativeRound {
+	rounds := make([]nativeRound, len(pp.Rounds))
+	for i, r := range pp.Rounds {
+		rounds[i].evaluation = r.Evaluation.BigInt(new(big.Int))
+		rounds[i].interactions = make([][2]nativeMerkleProof, len(r.Interactions))
+		for j, interaction := range r.Interactions {
+			for k := range interaction {
+				rounds[i].interactions[j][k] = nativeMerkleProof{root: interaction[k].MerkleRoot, proofSet: interaction[k].ProofSet}
+			}
+		}
+	}
+	return rounds
+}
+
+// nativeRoundsBN254 returns the rounds of the BN254 proof of proximity pp.
+func nativeRoundsBN254(pp fri_bn254.ProofOfProximity) []nativeRound {
+	rounds := make([]nativeRound, len(pp.Rounds))
+	for i, r := range pp.Rounds {
+		rounds[i].evaluation = r.Evaluation.BigInt(new(big.Int))
+		rounds[i].interactions = make([][2]nativeMerkleProof, len(r.Interactions))
+		for j, interaction := range r.Interactions {
+			for k := range interaction {
+				rounds[i].interactions[j][k] = nativeMerkleProof{root: interaction[k].MerkleRoot, proofSet: interaction[k].ProofSet}
+			}
+		}
+	}
+	return rounds
+}
+
+// nativeRoundsBW6633 returns the rounds of the BW6-633 proof of proximity pp.
+func nativeRoundsBW6633(pp fri_bw6633.ProofOfProximity) []nativeRound {
+	rounds := make([]nativeRound, len(pp.Rounds))
+	for i, r := range pp.Rounds {
+		rounds[i].evaluation = r.Evaluation.BigInt(new(big.Int))
+		rounds[i].interactions = make([][2]nativeMerkleProof, len(r.Interactions))
+		for j, interaction := range r.Interactions {
+			for k := range interaction {
+				rounds[i].interactions[j][k] = nativeMerkleProof{root: interaction[k].MerkleRoot, proofSet: interaction[k].ProofSet}
+			}
+		}
+	}
+	return rounds
+}
+
+// nativeRoundsBW6761 returns the rounds of the BW6-761 proof of proximity pp.
+func nativeRoundsBW6761(pp fri_bw6761.ProofOfProximity) []nativeRound {
+	rounds := make([]nativeRound, len(pp.Rounds))
+	for i, r := range pp.Rounds {
+		rounds[i].evaluation = r.Evaluation.BigInt(new(big.Int))
+		rounds[i].interactions = make([][2]nativeMerkleProof, len(r.Interactions))
+		for j, interaction := range r.Interactions {
+			for k := range interaction {
+				rounds[i].interactions[j][k] = nativeMerkleProof{root: interaction[k].MerkleRoot, proofSet: interaction[k].ProofSet}
+			}
+		}
+	}
+	return rounds
+}
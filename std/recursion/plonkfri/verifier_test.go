@@ -0,0 +1,126 @@
+package plonkfri
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	native_plonkfri "github.com/airchains-network/gnark/backend/plonkfri"
+	"github.com/airchains-network/gnark/backend/witness"
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/airchains-network/gnark/test"
+)
+
+type OuterCircuit struct {
+	Proof        Proof
+	VerifyingKey VerifyingKey `gnark:"-"`
+	InnerWitness Witness      `gnark:",public"`
+}
+
+func (c *OuterCircuit) Define(api frontend.API) error {
+	verifier, err := NewVerifier(api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	return verifier.AssertProof(c.VerifyingKey, c.Proof, c.InnerWitness)
+}
+
+type InnerCircuit struct {
+	P, Q frontend.Variable
+	N    frontend.Variable `gnark:",public"`
+}
+
+func (c *InnerCircuit) Define(api frontend.API) error {
+	res := api.Mul(c.P, c.Q)
+	res = api.Add(res, c.P, 5)
+	api.AssertIsEqual(res, c.N)
+	return nil
+}
+
+func getInner(assert *test.Assert, field *big.Int) (constraint.ConstraintSystem, native_plonkfri.VerifyingKey, witness.Witness, native_plonkfri.Proof) {
+	innerCcs, err := frontend.Compile(field, scs.NewBuilder, &InnerCircuit{})
+	assert.NoError(err)
+	innerPK, innerVK, err := native_plonkfri.SetupWithOptions(innerCcs, GetNativeProverOptions(field))
+	assert.NoError(err)
+
+	// inner proof
+	innerAssignment := &InnerCircuit{
+		P: 3,
+		Q: 5,
+		N: 23,
+	}
+	innerWitness, err := frontend.NewWitness(innerAssignment, field)
+	assert.NoError(err)
+	innerProof, err := native_plonkfri.Prove(innerCcs, innerPK, innerWitness, GetNativeProverOptions(field))
+	assert.NoError(err)
+	innerPubWitness, err := innerWitness.Public()
+	assert.NoError(err)
+	err = native_plonkfri.Verify(innerProof, innerVK, innerPubWitness, GetNativeVerifierOptions(field))
+	assert.NoError(err)
+	return innerCcs, innerVK, innerPubWitness, innerProof
+}
+
+func TestVerifier(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377} {
+		assert.Run(func(assert *test.Assert) {
+			innerCcs, innerVK, innerWitness, innerProof := getInner(assert, curve.ScalarField())
+
+			circuitVk, err := ValueOfVerifyingKey(innerVK)
+			assert.NoError(err)
+			circuitWitness, err := ValueOfWitness(innerWitness)
+			assert.NoError(err)
+			circuitProof, err := ValueOfProof(innerProof)
+			assert.NoError(err)
+
+			outerCircuit := &OuterCircuit{
+				InnerWitness: PlaceholderWitness(innerCcs),
+				Proof:        PlaceholderProof(innerCcs),
+				VerifyingKey: circuitVk,
+			}
+			outerAssignment := &OuterCircuit{
+				InnerWitness: circuitWitness,
+				Proof:        circuitProof,
+			}
+			err = test.IsSolved(outerCircuit, outerAssignment, curve.ScalarField())
+			assert.NoError(err)
+
+			// the proof must not verify for another public input
+			outerAssignment.InnerWitness = Witness{Public: []frontend.Variable{24}}
+			err = test.IsSolved(outerCircuit, outerAssignment, curve.ScalarField())
+			assert.Error(err)
+
+			// the proof must not verify for a tampered commitment
+			outerAssignment.InnerWitness = circuitWitness
+			tamperedProof, err := ValueOfProof(innerProof)
+			assert.NoError(err)
+			tamperedProof.LROpp[0].Rounds[0].Interactions[0][0].RootHash = 1
+			outerAssignment.Proof = tamperedProof
+			err = test.IsSolved(outerCircuit, outerAssignment, curve.ScalarField())
+			assert.Error(err)
+
+			// the roots of the verifying key are constants of the circuit
+			outerAssignment.Proof = circuitProof
+			tamperedVk, err := ValueOfVerifyingKey(innerVK)
+			assert.NoError(err)
+			tamperedVk.Q[0] = big.NewInt(1)
+			outerCircuit.VerifyingKey = tamperedVk
+			err = test.IsSolved(outerCircuit, outerAssignment, curve.ScalarField())
+			assert.Error(err)
+			outerCircuit.VerifyingKey = VerifyingKey{Size: circuitVk.Size, NbPublicVariables: circuitVk.NbPublicVariables}
+			err = test.IsSolved(outerCircuit, outerAssignment, curve.ScalarField())
+			assert.ErrorContains(err, "verifying key not initialized")
+		}, curve.String())
+	}
+}
+
+func TestValueOfUnknownType(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, err := ValueOfProof(nil)
+	assert.Error(err)
+	_, err = ValueOfVerifyingKey(nil)
+	assert.Error(err)
+}
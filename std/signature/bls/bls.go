@@ -0,0 +1,146 @@
+/*
+Package bls implements BLS signature verification over the BLS12-381 curve.
+
+We use the minimal-pubkey-size variant of the scheme where public keys are in
+G1 and signatures in G2, as used in the Ethereum consensus layer. The messages
+are hashed to G2 using the BLS12381G2_XMD:SHA-256_SSWU_RO_ suite from [RFC
+9380]. The package depends on the [emulated/sw_bls12381] package for the group
+operations and the pairing using non-native arithmetic, so the signatures can be
+verified in any circuit.
+
+See [BLS] for the signature scheme.
+
+[RFC 9380]: https://datatracker.ietf.org/doc/html/rfc9380
+[BLS]: https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05
+*/
+package bls
+
+import (
+	"fmt"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/airchains-network/gnark/std/math/uints"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// DSTProofOfPossession is the domain separation tag for the proof of
+// possession ciphersuite. It is used for signing messages in the Ethereum
+// consensus layer.
+const DSTProofOfPossession = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+
+// PublicKey is the BLS public key.
+type PublicKey = sw_bls12381.G1Affine
+
+// Signature is the BLS signature.
+type Signature = sw_bls12381.G2Affine
+
+// Verifier verifies BLS signatures for a fixed domain separation tag.
+type Verifier struct {
+	api     frontend.API
+	pairing *sw_bls12381.Pairing
+	g2      *sw_bls12381.G2
+	curve   *sw_emulated.Curve[sw_bls12381.BaseField, sw_bls12381.ScalarField]
+	negG1   *PublicKey
+	dst     []byte
+}
+
+// NewVerifier returns a new verifier of the signatures with the domain
+// separation tag dst.
+func NewVerifier(api frontend.API, dst []byte) (*Verifier, error) {
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return nil, fmt.Errorf("new pairing: %w", err)
+	}
+	curve, err := sw_emulated.New[sw_bls12381.BaseField, sw_bls12381.ScalarField](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	_, _, g1, _ := bls12381.Generators()
+	g1.Neg(&g1)
+	negG1 := sw_bls12381.NewG1Affine(g1)
+	return &Verifier{
+		api:     api,
+		pairing: pairing,
+		g2:      sw_bls12381.NewG2(api),
+		curve:   curve,
+		negG1:   &negG1,
+		dst:     dst,
+	}, nil
+}
+
+// HashToG2 hashes the message msg to G2 using the domain separation tag of
+// the verifier.
+func (v *Verifier) HashToG2(msg []uints.U8) (*sw_bls12381.G2Affine, error) {
+	return v.g2.HashToG2(msg, v.dst)
+}
+
+// AggregatePublicKeys returns the sum of the public keys pks. The public keys
+// may repeat.
+func (v *Verifier) AggregatePublicKeys(pks []*PublicKey) (*PublicKey, error) {
+	if len(pks) == 0 {
+		return nil, fmt.Errorf("no public keys to aggregate")
+	}
+	res := pks[0]
+	for i := 1; i < len(pks); i++ {
+		res = v.curve.AddUnified(res, pks[i])
+	}
+	return res, nil
+}
+
+// Verify asserts that sig is a valid signature of the message msg for the
+// public key pk. The signature is checked to be in G2.
+//
+// ⚠️  The public key is assumed to be valid, i.e. a non-zero point in G1. This
+// is not checked.
+func (v *Verifier) Verify(pk *PublicKey, msg []uints.U8, sig *Signature) error {
+	return v.AggregateVerify([]*PublicKey{pk}, [][]uints.U8{msg}, sig)
+}
+
+// FastAggregateVerify asserts that sig is a valid aggregate signature of the
+// same message msg for all the public keys pks. This is the verification
+// method used for the sync committee signatures in Ethereum.
+//
+// ⚠️  The public keys are assumed to be valid and accompanied with a proof of
+// possession. This is not checked.
+func (v *Verifier) FastAggregateVerify(pks []*PublicKey, msg []uints.U8, sig *Signature) error {
+	apk, err := v.AggregatePublicKeys(pks)
+	if err != nil {
+		return err
+	}
+	return v.Verify(apk, msg, sig)
+}
+
+// AggregateVerify asserts that sig is a valid aggregate signature of the
+// messages msgs for the corresponding public keys pks. All the pairings are
+// computed in a single multi-pairing check:
+//
+//	e(pk₁, H(m₁)) ⋯ e(pkₙ, H(mₙ)) ⋅ e(-g₁, sig) == 1
+//
+// ⚠️  The public keys are assumed to be valid. This is not checked.
+func (v *Verifier) AggregateVerify(pks []*PublicKey, msgs [][]uints.U8, sig *Signature) error {
+	if len(pks) != len(msgs) {
+		return fmt.Errorf("mismatching public keys and messages slice lengths")
+	}
+	if len(pks) == 0 {
+		return fmt.Errorf("no public keys")
+	}
+	v.pairing.AssertIsOnG2(sig)
+	P := make([]*sw_bls12381.G1Affine, 0, len(pks)+1)
+	Q := make([]*sw_bls12381.G2Affine, 0, len(pks)+1)
+	for i := range pks {
+		h, err := v.HashToG2(msgs[i])
+		if err != nil {
+			return fmt.Errorf("hash message %d: %w", i, err)
+		}
+		P = append(P, pks[i])
+		Q = append(Q, h)
+	}
+	P = append(P, v.negG1)
+	Q = append(Q, sig)
+	if err := v.pairing.PairingCheck(P, Q); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// sign returns a random public key and the signature of msg.
+func sign(t *testing.T, msg []byte) (bls12381.G1Affine, bls12381.G2Affine) {
+	var sk fr.Element
+	if _, err := sk.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	h, err := bls12381.HashToG2(msg, []byte(DSTProofOfPossession))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skb := sk.BigInt(new(big.Int))
+	var pk bls12381.G1Affine
+	var sig bls12381.G2Affine
+	pk.ScalarMultiplicationBase(skb)
+	sig.ScalarMultiplication(&h, skb)
+	return pk, sig
+}
+
+type verifyCircuit struct {
+	PublicKey PublicKey
+	Msg       []uints.U8
+	Signature Signature
+}
+
+func (c *verifyCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api, []byte(DSTProofOfPossession))
+	if err != nil {
+		return err
+	}
+	return v.Verify(&c.PublicKey, c.Msg, &c.Signature)
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	msg := []byte("hello world")
+	pk, sig := sign(t, msg)
+	circuit := verifyCircuit{Msg: make([]uints.U8, len(msg))}
+	witness := verifyCircuit{
+		PublicKey: sw_bls12381.NewG1Affine(pk),
+		Msg:       uints.NewU8Array(msg),
+		Signature: sw_bls12381.NewG2Affine(sig),
+	}
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	witness.Msg = uints.NewU8Array([]byte("hello World"))
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type fastAggregateVerifyCircuit struct {
+	PublicKeys []PublicKey
+	Msg        []uints.U8
+	Signature  Signature
+}
+
+func (c *fastAggregateVerifyCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api, []byte(DSTProofOfPossession))
+	if err != nil {
+		return err
+	}
+	pks := make([]*PublicKey, len(c.PublicKeys))
+	for i := range pks {
+		pks[i] = &c.PublicKeys[i]
+	}
+	return v.FastAggregateVerify(pks, c.Msg, &c.Signature)
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	msg := make([]byte, 32)
+	pk0, sig0 := sign(t, msg)
+	pk1, sig1 := sign(t, msg)
+	// the same key may appear several times in the sync committee
+	var sig bls12381.G2Affine
+	sig.Add(&sig0, &sig1).Add(&sig, &sig1)
+	circuit := fastAggregateVerifyCircuit{
+		PublicKeys: make([]PublicKey, 3),
+		Msg:        make([]uints.U8, len(msg)),
+	}
+	witness := fastAggregateVerifyCircuit{
+		PublicKeys: []PublicKey{sw_bls12381.NewG1Affine(pk0), sw_bls12381.NewG1Affine(pk1), sw_bls12381.NewG1Affine(pk1)},
+		Msg:        uints.NewU8Array(msg),
+		Signature:  sw_bls12381.NewG2Affine(sig),
+	}
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type aggregateVerifyCircuit struct {
+	PublicKeys []PublicKey
+	Msgs       [][]uints.U8
+	Signature  Signature
+}
+
+func (c *aggregateVerifyCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api, []byte(DSTProofOfPossession))
+	if err != nil {
+		return err
+	}
+	pks := make([]*PublicKey, len(c.PublicKeys))
+	for i := range pks {
+		pks[i] = &c.PublicKeys[i]
+	}
+	return v.AggregateVerify(pks, c.Msgs, &c.Signature)
+}
+
+func TestAggregateVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	msgs := [][]byte{[]byte("first"), []byte("second message")}
+	pk0, sig0 := sign(t, msgs[0])
+	pk1, sig1 := sign(t, msgs[1])
+	var sig bls12381.G2Affine
+	sig.Add(&sig0, &sig1)
+	circuit := aggregateVerifyCircuit{
+		PublicKeys: make([]PublicKey, 2),
+		Msgs:       [][]uints.U8{make([]uints.U8, len(msgs[0])), make([]uints.U8, len(msgs[1]))},
+	}
+	witness := aggregateVerifyCircuit{
+		PublicKeys: []PublicKey{sw_bls12381.NewG1Affine(pk0), sw_bls12381.NewG1Affine(pk1)},
+		Msgs:       [][]uints.U8{uints.NewU8Array(msgs[0]), uints.NewU8Array(msgs[1])},
+		Signature:  sw_bls12381.NewG2Affine(sig),
+	}
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
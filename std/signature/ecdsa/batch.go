@@ -0,0 +1,145 @@
+package ecdsa
+
+import (
+	"fmt"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/algopts"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/airchains-network/gnark/std/math/bits"
+	"github.com/airchains-network/gnark/std/math/emulated"
+	"github.com/airchains-network/gnark/std/recursion"
+)
+
+// BatchVerify asserts that the signatures sigs verify for the messages msgs and
+// public keys pks. The curve parameters params define the elliptic curve.
+//
+// Instead of verifying every signature separately, we obtain the commitments
+// Rᵢ = [uᵢ]G + [vᵢ]Pᵢ with uᵢ = msgᵢ/sᵢ and vᵢ = rᵢ/sᵢ from a hint, check
+// that the x-coordinate of Rᵢ is rᵢ and then check a random linear combination
+//
+//	∑ᵢ [γⁱ]Rᵢ - ∑ᵢ [γⁱvᵢ]Pᵢ - [∑ᵢ γⁱuᵢ]G = 0
+//
+// in a single multi-scalar multiplication of 2n+1 points (see
+// [algopts.WithWindowedMultiScalarMul]). The public keys and commitments need
+// not be distinct. In particular, a single key may sign all the messages. The
+// challenge γ is derived using Fiat-Shamir from the public keys, commitments
+// and scalars.
+//
+// We derive the challenge with an in-circuit transcript instead of using
+// [github.com/airchains-network/gnark/std/multicommit]: the multi-commitment
+// callbacks are called after the range checks and the multiplication checks
+// of the non-native fields are finalized, so they cannot perform the
+// non-native arithmetic which depends on the challenge.
+//
+// We assume that the messages msgs are already hashed to the scalar field.
+func BatchVerify[T, S emulated.FieldParams](api frontend.API, params sw_emulated.CurveParams, pks []*PublicKey[T, S], msgs []*emulated.Element[S], sigs []*Signature[S]) error {
+	if len(pks) != len(msgs) || len(pks) != len(sigs) {
+		return fmt.Errorf("mismatching public keys, messages and signatures slice lengths")
+	}
+	if len(pks) == 0 {
+		return nil
+	}
+	cr, err := sw_emulated.New[T, S](api, params)
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	scalarApi, err := emulated.NewField[S](api)
+	if err != nil {
+		return fmt.Errorf("new scalar field: %w", err)
+	}
+	baseApi, err := emulated.NewField[T](api)
+	if err != nil {
+		return fmt.Errorf("new base field: %w", err)
+	}
+	var fr S
+	fs, err := recursion.NewTranscript(api, fr.Modulus(), []string{"gamma"})
+	if err != nil {
+		return fmt.Errorf("new transcript: %w", err)
+	}
+	a := baseApi.NewElement(params.A)
+	gx := baseApi.NewElement(params.Gx)
+	gy := baseApi.NewElement(params.Gy)
+
+	n := len(pks)
+	us := make([]*emulated.Element[S], n)
+	vs := make([]*emulated.Element[S], n)
+	Rs := make([]*sw_emulated.AffinePoint[T], n)
+	for i := range pks {
+		pkpt := (*sw_emulated.AffinePoint[T])(pks[i])
+		// the batch equation only holds for points of the curve, an off-curve
+		// key would mix points of different curves in the combination.
+		cr.AssertIsOnCurve(pkpt)
+		sInv := scalarApi.Inverse(&sigs[i].S)
+		us[i] = scalarApi.MulMod(msgs[i], sInv)
+		vs[i] = scalarApi.MulMod(&sigs[i].R, sInv)
+
+		// the hint takes the inputs in the base field
+		u := baseApi.FromBits(scalarApi.ToBits(us[i])...)
+		v := baseApi.FromBits(scalarApi.ToBits(vs[i])...)
+		res, err := baseApi.NewHint(jointScalarMulHint, 2, a, gx, gy, &pkpt.X, &pkpt.Y, u, v)
+		if err != nil {
+			return fmt.Errorf("hint: %w", err)
+		}
+		Rs[i] = &sw_emulated.AffinePoint[T]{X: *res[0], Y: *res[1]}
+		cr.AssertIsOnCurve(Rs[i])
+
+		// x-coordinate of Rᵢ is rᵢ. As rᵢ is non-zero, Rᵢ is not (0,0).
+		baseApi.AssertIsInRange(&Rs[i].X)
+		rxBits := baseApi.ToBits(&Rs[i].X)
+		rBits := scalarApi.ToBits(&sigs[i].R)
+		if len(rBits) != len(rxBits) {
+			return fmt.Errorf("non-equal lengths")
+		}
+		for j := range rBits {
+			api.AssertIsEqual(rBits[j], rxBits[j])
+		}
+		api.AssertIsEqual(api.IsZero(api.Add(rBits[0], rBits[1], rBits[2:]...)), 0)
+
+		for _, data := range [][]frontend.Variable{
+			cr.MarshalG1(*pkpt),
+			cr.MarshalG1(*Rs[i]),
+			cr.MarshalScalar(*us[i]),
+			cr.MarshalScalar(*vs[i]),
+		} {
+			if err := fs.Bind("gamma", data); err != nil {
+				return fmt.Errorf("bind %d-th signature: %w", i, err)
+			}
+		}
+	}
+	gamma, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return fmt.Errorf("compute challenge: %w", err)
+	}
+	bGamma := bits.ToBinary(api, gamma, bits.WithNbDigits(fr.Modulus().BitLen()))
+	gammaS := scalarApi.FromBits(bGamma...)
+
+	// all the terms are accumulated in a single multi-scalar multiplication.
+	// The windowed method offsets the accumulator by a point of unknown
+	// discrete logarithm and constrains all the denominators to be
+	// invertible. Repeated public keys and commitments are thus handled
+	// correctly, whereas a key or commitment chosen to collide with the
+	// offset makes the circuit unsatisfiable instead of leaving the slopes
+	// unconstrained.
+	n2 := 2*n + 1
+	points := make([]*sw_emulated.AffinePoint[T], 0, n2)
+	scalars := make([]*emulated.Element[S], 0, n2)
+	coef := scalarApi.One()
+	u := us[0]
+	for i := range pks {
+		if i > 0 {
+			coef = scalarApi.MulMod(coef, gammaS)
+			u = scalarApi.Add(u, scalarApi.MulMod(coef, us[i]))
+		}
+		points = append(points, Rs[i], (*sw_emulated.AffinePoint[T])(pks[i]))
+		scalars = append(scalars, coef, scalarApi.Neg(scalarApi.MulMod(coef, vs[i])))
+	}
+	points = append(points, cr.Generator())
+	scalars = append(scalars, scalarApi.Neg(u))
+	res, err := cr.MultiScalarMul(points, scalars, algopts.WithWindowedMultiScalarMul(0))
+	if err != nil {
+		return fmt.Errorf("multi scalar mul: %w", err)
+	}
+	cr.AssertIsEqual(res, &sw_emulated.AffinePoint[T]{X: *baseApi.Zero(), Y: *baseApi.Zero()})
+	return nil
+}
@@ -0,0 +1,305 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/constraint/solver"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/airchains-network/gnark/std/math/emulated"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/ecdsa"
+)
+
+// randomSecp256k1Signature returns the witness assignment for a signature of
+// the message msg under a random key.
+func randomSecp256k1Signature(t *testing.T, msg []byte) (PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], emulated.Element[emulated.Secp256k1Fr], Signature[emulated.Secp256k1Fr]) {
+	privKey, err := ecdsa.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secp256k1Signature(t, privKey, msg)
+}
+
+// secp256k1Signature returns the witness assignment for a signature of the
+// message msg under the key privKey.
+func secp256k1Signature(t *testing.T, privKey *ecdsa.PrivateKey, msg []byte) (PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], emulated.Element[emulated.Secp256k1Fr], Signature[emulated.Secp256k1Fr]) {
+	sigBin, err := privKey.Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig ecdsa.Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		t.Fatal(err)
+	}
+	r, s := new(big.Int), new(big.Int)
+	r.SetBytes(sig.R[:32])
+	s.SetBytes(sig.S[:32])
+	hash := ecdsa.HashToInt(msg)
+	return PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](privKey.PublicKey.A.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](privKey.PublicKey.A.Y),
+		},
+		emulated.ValueOf[emulated.Secp256k1Fr](hash),
+		Signature[emulated.Secp256k1Fr]{
+			R: emulated.ValueOf[emulated.Secp256k1Fr](r),
+			S: emulated.ValueOf[emulated.Secp256k1Fr](s),
+		}
+}
+
+type batchVerifyCircuit[T, S emulated.FieldParams] struct {
+	Pubs []PublicKey[T, S]
+	Msgs []emulated.Element[S]
+	Sigs []Signature[S]
+}
+
+func (c *batchVerifyCircuit[T, S]) Define(api frontend.API) error {
+	pks := make([]*PublicKey[T, S], len(c.Pubs))
+	msgs := make([]*emulated.Element[S], len(c.Msgs))
+	sigs := make([]*Signature[S], len(c.Sigs))
+	for i := range pks {
+		pks[i], msgs[i], sigs[i] = &c.Pubs[i], &c.Msgs[i], &c.Sigs[i]
+	}
+	return BatchVerify(api, sw_emulated.GetCurveParams[T](), pks, msgs, sigs)
+}
+
+func TestBatchVerify(t *testing.T) {
+	testBatchVerify(t, false)
+}
+
+func TestBatchVerifyRepeatedKey(t *testing.T) {
+	testBatchVerify(t, true)
+}
+
+func testBatchVerify(t *testing.T, repeatedKey bool) {
+	assert := test.NewAssert(t)
+	privKey, err := ecdsa.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	const nbSigs = 3
+	type circuitT = batchVerifyCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+	circuit := circuitT{
+		Pubs: make([]PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], nbSigs),
+		Msgs: make([]emulated.Element[emulated.Secp256k1Fr], nbSigs),
+		Sigs: make([]Signature[emulated.Secp256k1Fr], nbSigs),
+	}
+	witness := circuitT{
+		Pubs: make([]PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], nbSigs),
+		Msgs: make([]emulated.Element[emulated.Secp256k1Fr], nbSigs),
+		Sigs: make([]Signature[emulated.Secp256k1Fr], nbSigs),
+	}
+	for i := 0; i < nbSigs; i++ {
+		if repeatedKey {
+			witness.Pubs[i], witness.Msgs[i], witness.Sigs[i] = secp256k1Signature(t, privKey, []byte{byte(i)})
+		} else {
+			witness.Pubs[i], witness.Msgs[i], witness.Sigs[i] = randomSecp256k1Signature(t, []byte{byte(i)})
+		}
+	}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// invalid signature in the batch
+	witness.Msgs[0], witness.Msgs[1] = witness.Msgs[1], witness.Msgs[0]
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestBatchVerifyOffCurveKey(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbSigs = 2
+	type circuitT = batchVerifyCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+	circuit := circuitT{
+		Pubs: make([]PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], nbSigs),
+		Msgs: make([]emulated.Element[emulated.Secp256k1Fr], nbSigs),
+		Sigs: make([]Signature[emulated.Secp256k1Fr], nbSigs),
+	}
+	witness := circuitT{
+		Pubs: make([]PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], nbSigs),
+		Msgs: make([]emulated.Element[emulated.Secp256k1Fr], nbSigs),
+		Sigs: make([]Signature[emulated.Secp256k1Fr], nbSigs),
+	}
+	for i := 0; i < nbSigs; i++ {
+		witness.Pubs[i], witness.Msgs[i], witness.Sigs[i] = randomSecp256k1Signature(t, []byte{byte(i)})
+	}
+	assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+
+	// (x, y+1) isn't on the curve y² = x³ + 7
+	privKey, err := ecdsa.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	x := privKey.PublicKey.A.X.BigInt(new(big.Int))
+	y := privKey.PublicKey.A.Y.BigInt(new(big.Int))
+	y.Add(y, big.NewInt(1)).Mod(y, emulated.Secp256k1Fp{}.Modulus())
+	witness.Pubs[1] = PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		X: emulated.ValueOf[emulated.Secp256k1Fp](x),
+		Y: emulated.ValueOf[emulated.Secp256k1Fp](y),
+	}
+	assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+}
+
+type multiVerifyCircuit[T, S emulated.FieldParams] struct {
+	Pubs []PublicKey[T, S]
+	Msgs []emulated.Element[S]
+	Sigs []Signature[S]
+}
+
+func (c *multiVerifyCircuit[T, S]) Define(api frontend.API) error {
+	for i := range c.Pubs {
+		c.Pubs[i].Verify(api, sw_emulated.GetCurveParams[T](), &c.Msgs[i], &c.Sigs[i])
+	}
+	return nil
+}
+
+func TestBatchVerifyConstraints(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbSigs = 4
+	batch := batchVerifyCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Pubs: make([]PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], nbSigs),
+		Msgs: make([]emulated.Element[emulated.Secp256k1Fr], nbSigs),
+		Sigs: make([]Signature[emulated.Secp256k1Fr], nbSigs),
+	}
+	multi := multiVerifyCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Pubs: batch.Pubs,
+		Msgs: batch.Msgs,
+		Sigs: batch.Sigs,
+	}
+	batchCcs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &batch)
+	assert.NoError(err)
+	multiCcs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &multi)
+	assert.NoError(err)
+	// the terms of all the signatures are aggregated in a single multi-scalar
+	// multiplication, which shares the doublings.
+	assert.Less(batchCcs.GetNbConstraints(), multiCcs.GetNbConstraints()*2/3)
+}
+
+type isValidCircuit[T, S emulated.FieldParams] struct {
+	Sig      Signature[S]
+	Msg      emulated.Element[S]
+	Pub      PublicKey[T, S]
+	Expected frontend.Variable
+}
+
+func (c *isValidCircuit[T, S]) Define(api frontend.API) error {
+	res := c.Pub.IsValid(api, sw_emulated.GetCurveParams[T](), &c.Msg, &c.Sig)
+	api.AssertIsEqual(res, c.Expected)
+	return nil
+}
+
+func TestIsValid(t *testing.T) {
+	assert := test.NewAssert(t)
+	type circuitT = isValidCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+	pub, msg, sig := randomSecp256k1Signature(t, []byte("valid"))
+	_, otherMsg, _ := randomSecp256k1Signature(t, []byte("other"))
+	zeroSig := Signature[emulated.Secp256k1Fr]{
+		R: sig.R,
+		S: emulated.ValueOf[emulated.Secp256k1Fr](0),
+	}
+	// public keys which are not on the curve
+	offCurve := pub
+	offCurve.Y = emulated.ValueOf[emulated.Secp256k1Fp](1)
+	infinity := PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		X: emulated.ValueOf[emulated.Secp256k1Fp](0),
+		Y: emulated.ValueOf[emulated.Secp256k1Fp](0),
+	}
+	for _, tc := range []struct {
+		pub      PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+		msg      emulated.Element[emulated.Secp256k1Fr]
+		sig      Signature[emulated.Secp256k1Fr]
+		expected int
+	}{
+		{pub, msg, sig, 1},
+		{pub, otherMsg, sig, 0},
+		{pub, msg, zeroSig, 0},
+		{offCurve, msg, sig, 0},
+		{infinity, msg, sig, 0},
+	} {
+		witness := circuitT{Sig: tc.sig, Msg: tc.msg, Pub: tc.pub, Expected: tc.expected}
+		err := test.IsSolved(&circuitT{}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+}
+
+func TestBatchVerifyCollidingCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	type circuitT = batchVerifyCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+	const nbSigs = 2
+	circuit := circuitT{
+		Pubs: make([]PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], nbSigs),
+		Msgs: make([]emulated.Element[emulated.Secp256k1Fr], nbSigs),
+		Sigs: make([]Signature[emulated.Secp256k1Fr], nbSigs),
+	}
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit)
+	assert.NoError(err)
+
+	params := sw_emulated.GetSecp256k1Params()
+	fp := emulated.Secp256k1Fp{}.Modulus()
+	fr := emulated.Secp256k1Fr{}.Modulus()
+	// the offset of the windowed multi-scalar multiplication is the point with
+	// the smallest x-coordinate
+	offset := &nativePoint{x: big.NewInt(0), y: new(big.Int)}
+	for {
+		offset.x.Add(offset.x, big.NewInt(1))
+		rhs := new(big.Int).Exp(offset.x, big.NewInt(3), fp)
+		rhs.Add(rhs, params.B).Mod(rhs, fp)
+		if offset.y.ModSqrt(rhs, fp) != nil {
+			break
+		}
+	}
+	privKey, err := ecdsa.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	pk := &nativePoint{
+		x: privKey.PublicKey.A.X.BigInt(new(big.Int)),
+		y: privKey.PublicKey.A.Y.BigInt(new(big.Int)),
+	}
+	for _, tc := range []struct {
+		name        string
+		pub, commit *nativePoint
+	}{
+		{"offset key and commitment", offset, offset},
+		{"commitment equal to the key", pk, pk},
+	} {
+		// the commitment is given by a malicious hint, the forged signature
+		// has rᵢ = x(Rᵢ) and random sᵢ.
+		if tc.commit.x.Cmp(fr) >= 0 {
+			continue
+		}
+		witness := circuitT{
+			Pubs: make([]PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], nbSigs),
+			Msgs: make([]emulated.Element[emulated.Secp256k1Fr], nbSigs),
+			Sigs: make([]Signature[emulated.Secp256k1Fr], nbSigs),
+		}
+		witness.Pubs[0], witness.Msgs[0], witness.Sigs[0] = randomSecp256k1Signature(t, []byte("valid"))
+		s, err := rand.Int(rand.Reader, fr)
+		assert.NoError(err)
+		witness.Pubs[1] = PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](tc.pub.x),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](tc.pub.y),
+		}
+		witness.Msgs[1] = emulated.ValueOf[emulated.Secp256k1Fr](ecdsa.HashToInt([]byte("forged")))
+		witness.Sigs[1] = Signature[emulated.Secp256k1Fr]{
+			R: emulated.ValueOf[emulated.Secp256k1Fr](tc.commit.x),
+			S: emulated.ValueOf[emulated.Secp256k1Fr](s),
+		}
+		w, err := frontend.NewWitness(&witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		maliciousHint := func(nativeMod *big.Int, nativeInputs, nativeOutputs []*big.Int) error {
+			return emulated.UnwrapHint(nativeInputs, nativeOutputs,
+				func(mod *big.Int, inputs, outputs []*big.Int) error {
+					res := tc.commit
+					if inputs[3].Cmp(tc.pub.x) != 0 || inputs[4].Cmp(tc.pub.y) != 0 {
+						c := nativeCurve{p: mod, a: inputs[0]}
+						g := &nativePoint{inputs[1], inputs[2]}
+						pk := &nativePoint{inputs[3], inputs[4]}
+						res = c.add(c.scalarMul(g, inputs[5]), c.scalarMul(pk, inputs[6]))
+					}
+					outputs[0].Set(res.x)
+					outputs[1].Set(res.y)
+					return nil
+				})
+		}
+		_, err = ccs.Solve(w, solver.OverrideHint(solver.GetHintID(jointScalarMulHint), maliciousHint))
+		assert.Error(err, tc.name)
+	}
+}
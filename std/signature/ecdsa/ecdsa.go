@@ -6,6 +6,9 @@ operations using non-native arithmetic. Thus we can verify ECDSA signatures over
 any curve. The cost for a single secp256k1 signature verification is
 approximately 4M constraints in R1CS and 10M constraints in PLONKish.
 
+For verifying many signatures at once, see [BatchVerify] which checks a random
+linear combination of the signatures.
+
 See [ECDSA] for the signature verification algorithm.
 
 [ECDSA]:
@@ -62,3 +65,83 @@ func (pk PublicKey[T, S]) Verify(api frontend.API, params sw_emulated.CurveParam
 		api.AssertIsEqual(rbits[i], qxBits[i])
 	}
 }
+
+// IsValid returns a boolean variable indicating if the signature sig verifies
+// for the message msg and public key pk. Contrary to [PublicKey.Verify], the
+// method does not assert the validity of the signature, allowing to skip
+// invalid signatures. The curve parameters params define the elliptic curve.
+//
+// The method returns 0 for public keys which are not on the curve and for
+// signatures with zero r or s, and the circuit is satisfiable for any input.
+// For that, the scalar multiplication by the public key uses unified formulas,
+// which is more expensive than in [PublicKey.Verify].
+//
+// We assume that the message msg is already hashed to the scalar field.
+func (pk PublicKey[T, S]) IsValid(api frontend.API, params sw_emulated.CurveParams, msg *emulated.Element[S], sig *Signature[S]) frontend.Variable {
+	cr, err := sw_emulated.New[T, S](api, params)
+	if err != nil {
+		panic(err)
+	}
+	scalarApi, err := emulated.NewField[S](api)
+	if err != nil {
+		panic(err)
+	}
+	baseApi, err := emulated.NewField[T](api)
+	if err != nil {
+		panic(err)
+	}
+
+	// replace zero r and s by one to avoid unsatisfiable inversion.
+	rZero := scalarApi.IsZero(&sig.R)
+	sZero := scalarApi.IsZero(&sig.S)
+	r := scalarApi.Select(rZero, scalarApi.One(), &sig.R)
+	s := scalarApi.Select(sZero, scalarApi.One(), &sig.S)
+
+	// replace a public key which is not on the curve by the generator. The
+	// point (0,0) is not on the curve as b is non-zero.
+	pkpt := sw_emulated.AffinePoint[T](pk)
+	left := baseApi.Mul(&pkpt.Y, &pkpt.Y)
+	right := baseApi.Mul(&pkpt.X, baseApi.Mul(&pkpt.X, &pkpt.X))
+	right = baseApi.Add(right, baseApi.NewElement(params.B))
+	right = baseApi.Add(right, baseApi.Mul(baseApi.NewElement(params.A), &pkpt.X))
+	onCurve := baseApi.IsZero(baseApi.Sub(left, right))
+	p := cr.Select(onCurve, &pkpt, cr.Generator())
+
+	sInv := scalarApi.Inverse(s)
+	msInv := scalarApi.MulMod(msg, sInv)
+	rsInv := scalarApi.MulMod(r, sInv)
+
+	// q = [rsInv]p + [msInv]g
+	q := cr.AddUnified(cr.ScalarMulBase(msInv), scalarMulUnified(cr, baseApi, scalarApi, p, rsInv))
+	qx := baseApi.Reduce(&q.X)
+	baseApi.AssertIsInRange(qx)
+	qxBits := baseApi.ToBits(qx)
+	rr := scalarApi.Reduce(&sig.R)
+	scalarApi.AssertIsInRange(rr)
+	rbits := scalarApi.ToBits(rr)
+	if len(rbits) != len(qxBits) {
+		panic("non-equal lengths")
+	}
+	var diff frontend.Variable = 0
+	for i := range rbits {
+		diff = api.Add(diff, api.Xor(rbits[i], qxBits[i]))
+	}
+	res := api.IsZero(diff)
+	res = api.And(res, api.Sub(1, rZero))
+	res = api.And(res, api.Sub(1, sZero))
+	res = api.And(res, onCurve)
+	return res
+}
+
+// scalarMulUnified computes [s]p using the double-and-add algorithm with
+// unified formulas, so that it is satisfiable for any scalar s and point p.
+func scalarMulUnified[T, S emulated.FieldParams](cr *sw_emulated.Curve[T, S], baseApi *emulated.Field[T], scalarApi *emulated.Field[S], p *sw_emulated.AffinePoint[T], s *emulated.Element[S]) *sw_emulated.AffinePoint[T] {
+	sBits := scalarApi.ToBits(scalarApi.Reduce(s))
+	zero := baseApi.Zero()
+	res := cr.Select(sBits[len(sBits)-1], p, &sw_emulated.AffinePoint[T]{X: *zero, Y: *zero})
+	for i := len(sBits) - 2; i >= 0; i-- {
+		res = cr.AddUnified(res, res)
+		res = cr.Select(sBits[i], cr.AddUnified(res, p), res)
+	}
+	return res
+}
@@ -0,0 +1,99 @@
+package ecdsa
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/airchains-network/gnark/constraint/solver"
+	"github.com/airchains-network/gnark/std/math/emulated"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		jointScalarMulHint,
+	}
+}
+
+// jointScalarMulHint computes [u1]G + [u2]P on the curve Y² = X³ + aX + b. The
+// inputs are a, Gx, Gy, Px, Py, u1 and u2 and the outputs the coordinates of
+// the result. The point at infinity is returned as (0,0).
+func jointScalarMulHint(nativeMod *big.Int, nativeInputs, nativeOutputs []*big.Int) error {
+	return emulated.UnwrapHint(nativeInputs, nativeOutputs,
+		func(mod *big.Int, inputs, outputs []*big.Int) error {
+			if len(inputs) != 7 {
+				return fmt.Errorf("expecting seven inputs")
+			}
+			if len(outputs) != 2 {
+				return fmt.Errorf("expecting two outputs")
+			}
+			c := nativeCurve{p: mod, a: inputs[0]}
+			g := &nativePoint{inputs[1], inputs[2]}
+			pk := &nativePoint{inputs[3], inputs[4]}
+			res := c.add(c.scalarMul(g, inputs[5]), c.scalarMul(pk, inputs[6]))
+			if res == nil {
+				outputs[0].SetUint64(0)
+				outputs[1].SetUint64(0)
+			} else {
+				outputs[0].Set(res.x)
+				outputs[1].Set(res.y)
+			}
+			return nil
+		})
+}
+
+// nativePoint is an affine point. The nil point is the point at infinity.
+type nativePoint struct {
+	x, y *big.Int
+}
+
+// nativeCurve implements the affine group law on a short Weierstrass curve
+// over big integers for computing the hints.
+type nativeCurve struct {
+	p, a *big.Int
+}
+
+func (c nativeCurve) add(p, q *nativePoint) *nativePoint {
+	if p == nil {
+		return q
+	}
+	if q == nil {
+		return p
+	}
+	var lambda, t big.Int
+	if p.x.Cmp(q.x) == 0 {
+		if t.Add(p.y, q.y).Mod(&t, c.p).Sign() == 0 {
+			return nil
+		}
+		// λ = (3x² + a) / 2y
+		lambda.Mul(p.x, p.x).Mul(&lambda, big.NewInt(3)).Add(&lambda, c.a)
+		t.Lsh(p.y, 1)
+	} else {
+		// λ = (q.y - p.y) / (q.x - p.x)
+		lambda.Sub(q.y, p.y)
+		t.Sub(q.x, p.x)
+	}
+	t.Mod(&t, c.p)
+	t.ModInverse(&t, c.p)
+	lambda.Mul(&lambda, &t).Mod(&lambda, c.p)
+	x := new(big.Int).Mul(&lambda, &lambda)
+	x.Sub(x, p.x).Sub(x, q.x).Mod(x, c.p)
+	y := new(big.Int).Sub(p.x, x)
+	y.Mul(y, &lambda).Sub(y, p.y).Mod(y, c.p)
+	return &nativePoint{x, y}
+}
+
+func (c nativeCurve) scalarMul(p *nativePoint, s *big.Int) *nativePoint {
+	var res *nativePoint
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = c.add(res, res)
+		if s.Bit(i) == 1 {
+			res = c.add(res, p)
+		}
+	}
+	return res
+}
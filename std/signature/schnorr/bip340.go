@@ -0,0 +1,289 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/algopts"
+	"github.com/airchains-network/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/airchains-network/gnark/std/hash/sha2"
+	"github.com/airchains-network/gnark/std/math/emulated"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+// tags used for domain separation of the hashes in BIP-340 verification.
+const (
+	challengeTag = "BIP0340/challenge"
+	batchTag     = "BIP0340/batch"
+)
+
+// BIP340PublicKey is a BIP-340 public key. BIP-340 public keys are serialized
+// as x-only points, the y coordinate is the even square root of x³+7. In the
+// circuit we take the y coordinate as an input and assert that the point is
+// on the curve and that y is even. Use [ValueOfBIP340PublicKey] to lift the
+// serialized key.
+type BIP340PublicKey sw_emulated.AffinePoint[emulated.Secp256k1Fp]
+
+// BIP340Signature is a BIP-340 signature (r, s). Similarly to the public key,
+// the nonce commitment R is given with its even y coordinate. Use
+// [ValueOfBIP340Signature] to lift the serialized signature.
+type BIP340Signature struct {
+	R sw_emulated.AffinePoint[emulated.Secp256k1Fp]
+	S emulated.Element[emulated.Secp256k1Fr]
+}
+
+// ValueOfBIP340PublicKey returns the witness assignment of the 32-byte
+// serialized x-only public key pk. It returns an error if the key is not a
+// valid BIP-340 public key.
+func ValueOfBIP340PublicKey(pk []byte) (BIP340PublicKey, error) {
+	if len(pk) != 32 {
+		return BIP340PublicKey{}, fmt.Errorf("public key length %d, expected 32", len(pk))
+	}
+	x, y, err := liftX(pk)
+	if err != nil {
+		return BIP340PublicKey{}, fmt.Errorf("lift public key: %w", err)
+	}
+	return BIP340PublicKey{
+		X: emulated.ValueOf[emulated.Secp256k1Fp](x),
+		Y: emulated.ValueOf[emulated.Secp256k1Fp](y),
+	}, nil
+}
+
+// ValueOfBIP340Signature returns the witness assignment of the 64-byte
+// serialized signature sig. It returns an error if r is not the x coordinate
+// of a point on the curve or if s is not less than the group order.
+func ValueOfBIP340Signature(sig []byte) (BIP340Signature, error) {
+	if len(sig) != 64 {
+		return BIP340Signature{}, fmt.Errorf("signature length %d, expected 64", len(sig))
+	}
+	x, y, err := liftX(sig[:32])
+	if err != nil {
+		return BIP340Signature{}, fmt.Errorf("lift nonce commitment: %w", err)
+	}
+	var s fr.Element
+	if err := s.SetBytesCanonical(sig[32:]); err != nil {
+		return BIP340Signature{}, fmt.Errorf("s: %w", err)
+	}
+	return BIP340Signature{
+		R: sw_emulated.AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](x),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](y),
+		},
+		S: emulated.ValueOf[emulated.Secp256k1Fr](s.BigInt(new(big.Int))),
+	}, nil
+}
+
+// liftX returns the point with the x coordinate xb and even y coordinate.
+func liftX(xb []byte) (x, y *big.Int, err error) {
+	var xe, ye, c fp.Element
+	if err := xe.SetBytesCanonical(xb); err != nil {
+		return nil, nil, err
+	}
+	c.Square(&xe).Mul(&c, &xe).Add(&c, new(fp.Element).SetUint64(7))
+	if ye.Sqrt(&c) == nil {
+		return nil, nil, errors.New("x coordinate not on curve")
+	}
+	if yb := ye.Bytes(); yb[len(yb)-1]&1 == 1 {
+		ye.Neg(&ye)
+	}
+	return xe.BigInt(new(big.Int)), ye.BigInt(new(big.Int)), nil
+}
+
+// VerifyBIP340 asserts that sig is a valid BIP-340 signature of the message
+// msg under the public key pk. The message may be of arbitrary length.
+func VerifyBIP340(api frontend.API, pk *BIP340PublicKey, msg []uints.U8, sig *BIP340Signature) error {
+	v, err := newBIP340Verifier(api)
+	if err != nil {
+		return err
+	}
+	pkpt := (*sw_emulated.AffinePoint[emulated.Secp256k1Fp])(pk)
+	pxBytes := v.assertLifted(pkpt)
+	rxBytes := v.assertLifted(&sig.R)
+	v.scalarApi.AssertIsInRange(&sig.S)
+	_, e, err := v.challenge(rxBytes, pxBytes, msg)
+	if err != nil {
+		return err
+	}
+	// R = [s]G - [e]P
+	R := v.curve.JointScalarMulBase(v.curve.Neg(pkpt), e, &sig.S)
+	v.curve.AssertIsEqual(R, &sig.R)
+	return nil
+}
+
+// BatchVerifyBIP340 asserts that all the signatures sigs are valid BIP-340
+// signatures of the messages msgs under the public keys pks.
+//
+// Following the batch verification algorithm from BIP-340, we check that
+//
+//	[s₁ + a₂s₂ + ... + aₙsₙ]G = R₁ + [a₂]R₂ + ... + [aₙ]Rₙ + [e₁]P₁ + [a₂e₂]P₂ + ... + [aₙeₙ]Pₙ
+//
+// where the coefficients aᵢ are the powers of a 128-bit challenge derived from
+// a tagged hash of all the challenges eᵢ and scalars sᵢ. All the terms are
+// computed in a single multi-scalar multiplication of 2n+1 points (see
+// [algopts.WithWindowedMultiScalarMul]). The public keys and nonce commitments
+// need not be distinct. In particular, a single key may sign all the messages.
+func BatchVerifyBIP340(api frontend.API, pks []*BIP340PublicKey, msgs [][]uints.U8, sigs []*BIP340Signature) error {
+	if len(pks) != len(msgs) || len(pks) != len(sigs) {
+		return fmt.Errorf("mismatching public keys, messages and signatures slice lengths")
+	}
+	if len(pks) == 0 {
+		return nil
+	}
+	v, err := newBIP340Verifier(api)
+	if err != nil {
+		return err
+	}
+	n := len(pks)
+	es := make([]*emulated.Element[emulated.Secp256k1Fr], n)
+	var seed [][]uints.U8
+	for i := range pks {
+		pkpt := (*sw_emulated.AffinePoint[emulated.Secp256k1Fp])(pks[i])
+		pxBytes := v.assertLifted(pkpt)
+		rxBytes := v.assertLifted(&sigs[i].R)
+		v.scalarApi.AssertIsInRange(&sigs[i].S)
+		eBytes, e, err := v.challenge(rxBytes, pxBytes, msgs[i])
+		if err != nil {
+			return fmt.Errorf("challenge %d: %w", i, err)
+		}
+		es[i] = e
+		seed = append(seed, eBytes, elementBytes(api, v.scalarApi, &sigs[i].S))
+	}
+	seedHash, err := v.taggedHash(batchTag, seed...)
+	if err != nil {
+		return err
+	}
+	// we use the 128 most significant bits of the seed as the challenge. We
+	// pad the bits to obtain an element with full number of limbs.
+	aBits := bytesToBits(api, seedHash[:16])
+	for len(aBits) < 8*len(seedHash) {
+		aBits = append(aBits, 0)
+	}
+	a := v.scalarApi.FromBits(aBits...)
+
+	// all the terms are accumulated in a single multi-scalar multiplication.
+	// The windowed method offsets the accumulator by a point of unknown
+	// discrete logarithm and constrains all the denominators to be
+	// invertible. Repeated public keys and nonce commitments are thus handled
+	// correctly, whereas a key or commitment chosen to collide with the
+	// offset makes the circuit unsatisfiable instead of leaving the slopes
+	// unconstrained.
+	points := make([]*sw_emulated.AffinePoint[emulated.Secp256k1Fp], 0, 2*n+1)
+	scalars := make([]*emulated.Element[emulated.Secp256k1Fr], 0, 2*n+1)
+	ai := v.scalarApi.One()
+	s := &sigs[0].S
+	for i := range pks {
+		if i > 0 {
+			ai = v.scalarApi.MulMod(ai, a)
+			s = v.scalarApi.Add(s, v.scalarApi.MulMod(ai, &sigs[i].S))
+		}
+		points = append(points, &sigs[i].R, (*sw_emulated.AffinePoint[emulated.Secp256k1Fp])(pks[i]))
+		scalars = append(scalars, ai, v.scalarApi.MulMod(ai, es[i]))
+	}
+	points = append(points, v.curve.Generator())
+	scalars = append(scalars, v.scalarApi.Neg(s))
+	res, err := v.curve.MultiScalarMul(points, scalars, algopts.WithWindowedMultiScalarMul(0))
+	if err != nil {
+		return fmt.Errorf("multi scalar mul: %w", err)
+	}
+	v.curve.AssertIsEqual(res, &sw_emulated.AffinePoint[emulated.Secp256k1Fp]{
+		X: *v.baseApi.Zero(),
+		Y: *v.baseApi.Zero(),
+	})
+	return nil
+}
+
+type bip340Verifier struct {
+	api       frontend.API
+	curve     *sw_emulated.Curve[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+	baseApi   *emulated.Field[emulated.Secp256k1Fp]
+	scalarApi *emulated.Field[emulated.Secp256k1Fr]
+}
+
+func newBIP340Verifier(api frontend.API) (*bip340Verifier, error) {
+	curve, err := sw_emulated.New[emulated.Secp256k1Fp, emulated.Secp256k1Fr](api, sw_emulated.GetSecp256k1Params())
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	baseApi, err := emulated.NewField[emulated.Secp256k1Fp](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base field: %w", err)
+	}
+	scalarApi, err := emulated.NewField[emulated.Secp256k1Fr](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
+	return &bip340Verifier{api: api, curve: curve, baseApi: baseApi, scalarApi: scalarApi}, nil
+}
+
+// assertLifted asserts that p is on the curve and has canonical coordinates
+// with even y coordinate, i.e. that p is the lifting of its x coordinate. It
+// returns the 32-byte serialization of the x coordinate.
+func (v *bip340Verifier) assertLifted(p *sw_emulated.AffinePoint[emulated.Secp256k1Fp]) []uints.U8 {
+	v.curve.AssertIsOnCurve(p)
+	x := v.baseApi.Reduce(&p.X)
+	v.baseApi.AssertIsInRange(x)
+	y := v.baseApi.Reduce(&p.Y)
+	v.baseApi.AssertIsInRange(y)
+	yBits := v.baseApi.ToBits(y)
+	v.api.AssertIsEqual(yBits[0], 0)
+	return elementBytes(v.api, v.baseApi, x)
+}
+
+// challenge computes the BIP-340 challenge hash(r || px || msg) and returns it
+// both as bytes and as a scalar.
+func (v *bip340Verifier) challenge(rx, px, msg []uints.U8) ([]uints.U8, *emulated.Element[emulated.Secp256k1Fr], error) {
+	h, err := v.taggedHash(challengeTag, rx, px, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the scalar field operations reduce the challenge modulo the group order.
+	return h, v.scalarApi.FromBits(bytesToBits(v.api, h)...), nil
+}
+
+// taggedHash computes the BIP-340 tagged hash SHA256(SHA256(tag) ||
+// SHA256(tag) || data).
+func (v *bip340Verifier) taggedHash(tag string, data ...[]uints.U8) ([]uints.U8, error) {
+	h, err := sha2.New(v.api)
+	if err != nil {
+		return nil, fmt.Errorf("new hasher: %w", err)
+	}
+	tagHash := sha256.Sum256([]byte(tag))
+	h.Write(uints.NewU8Array(tagHash[:]))
+	h.Write(uints.NewU8Array(tagHash[:]))
+	for i := range data {
+		h.Write(data[i])
+	}
+	return h.Sum(), nil
+}
+
+// elementBytes returns the big-endian byte decomposition of e. The element
+// must have zero overflow.
+func elementBytes[T emulated.FieldParams](api frontend.API, f *emulated.Field[T], e *emulated.Element[T]) []uints.U8 {
+	bits := f.ToBits(e)
+	nbBytes := (len(bits) + 7) / 8
+	res := make([]uints.U8, nbBytes)
+	for i := range res {
+		lo := 8 * (nbBytes - 1 - i)
+		hi := lo + 8
+		if hi > len(bits) {
+			hi = len(bits)
+		}
+		res[i] = uints.U8{Val: api.FromBinary(bits[lo:hi]...)}
+	}
+	return res
+}
+
+// bytesToBits returns the little-endian bit decomposition of the big-endian
+// byte slice b.
+func bytesToBits(api frontend.API, b []uints.U8) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 8*len(b))
+	for i := len(b) - 1; i >= 0; i-- {
+		res = append(res, api.ToBinary(b[i].Val, 8)...)
+	}
+	return res
+}
@@ -0,0 +1,248 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/math/uints"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+type bip340Circuit struct {
+	PublicKey BIP340PublicKey
+	Msg       []uints.U8
+	Signature BIP340Signature
+}
+
+func (c *bip340Circuit) Define(api frontend.API) error {
+	return VerifyBIP340(api, &c.PublicKey, c.Msg, &c.Signature)
+}
+
+type bip340BatchCircuit struct {
+	PublicKeys []BIP340PublicKey
+	Msgs       [][]uints.U8
+	Signatures []BIP340Signature
+}
+
+func (c *bip340BatchCircuit) Define(api frontend.API) error {
+	pks := make([]*BIP340PublicKey, len(c.PublicKeys))
+	sigs := make([]*BIP340Signature, len(c.Signatures))
+	for i := range pks {
+		pks[i] = &c.PublicKeys[i]
+		sigs[i] = &c.Signatures[i]
+	}
+	return BatchVerifyBIP340(api, pks, c.Msgs, sigs)
+}
+
+func nativeTaggedHash(tag string, data ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for i := range data {
+		h.Write(data[i])
+	}
+	return h.Sum(nil)
+}
+
+// bip340Sign returns the serialized public key and BIP-340 signature of msg
+// for a random secret key.
+func bip340Sign(t *testing.T, msg []byte) (pk, sig []byte) {
+	var d fr.Element
+	if _, err := d.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	return bip340SignWith(t, d, msg)
+}
+
+// bip340SignWith returns the serialized public key and BIP-340 signature of
+// msg for the secret key d.
+func bip340SignWith(t *testing.T, d fr.Element, msg []byte) (pk, sig []byte) {
+	n := fr.Modulus()
+	evenY := func(p *secp256k1.G1Affine) bool {
+		yb := p.Y.Bytes()
+		return yb[len(yb)-1]&1 == 0
+	}
+	var k fr.Element
+	if _, err := k.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	var P, R secp256k1.G1Affine
+	P.ScalarMultiplicationBase(d.BigInt(new(big.Int)))
+	if !evenY(&P) {
+		d.Neg(&d)
+	}
+	R.ScalarMultiplicationBase(k.BigInt(new(big.Int)))
+	if !evenY(&R) {
+		k.Neg(&k)
+	}
+	px, rx := P.X.Bytes(), R.X.Bytes()
+	eb := nativeTaggedHash(challengeTag, rx[:], px[:], msg)
+	var e fr.Element
+	e.SetBigInt(new(big.Int).Mod(new(big.Int).SetBytes(eb), n))
+	var s fr.Element
+	s.Mul(&e, &d).Add(&s, &k)
+	sb := s.Bytes()
+	return px[:], append(rx[:], sb[:]...)
+}
+
+func bip340Assignment(t *testing.T, pk, msg, sig []byte) (BIP340PublicKey, []uints.U8, BIP340Signature) {
+	pkw, err := ValueOfBIP340PublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigw, err := ValueOfBIP340Signature(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkw, uints.NewU8Array(msg), sigw
+}
+
+func TestBIP340(t *testing.T) {
+	assert := test.NewAssert(t)
+	// test vector 1 from BIP-340
+	pk, _ := hex.DecodeString("DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659")
+	msg, _ := hex.DecodeString("243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89")
+	sig, _ := hex.DecodeString("6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A")
+	pkw, msgw, sigw := bip340Assignment(t, pk, msg, sig)
+	circuit := &bip340Circuit{Msg: make([]uints.U8, len(msg))}
+	witness := &bip340Circuit{PublicKey: pkw, Msg: msgw, Signature: sigw}
+	err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// modified message
+	msg[0] ^= 1
+	witness.Msg = uints.NewU8Array(msg)
+	err = test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestBIP340Random(t *testing.T) {
+	assert := test.NewAssert(t)
+	msg := make([]byte, 100)
+	_, err := rand.Reader.Read(msg)
+	assert.NoError(err)
+	pk, sig := bip340Sign(t, msg)
+	pkw, msgw, sigw := bip340Assignment(t, pk, msg, sig)
+	circuit := &bip340Circuit{Msg: make([]uints.U8, len(msg))}
+	witness := &bip340Circuit{PublicKey: pkw, Msg: msgw, Signature: sigw}
+	err = test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestBatchBIP340(t *testing.T) {
+	testBatchBIP340(t, false)
+}
+
+func TestBatchBIP340RepeatedKey(t *testing.T) {
+	testBatchBIP340(t, true)
+}
+
+func testBatchBIP340(t *testing.T, repeatedKey bool) {
+	assert := test.NewAssert(t)
+	const nbSigs = 3
+	var d fr.Element
+	if _, err := d.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	circuit := &bip340BatchCircuit{
+		PublicKeys: make([]BIP340PublicKey, nbSigs),
+		Msgs:       make([][]uints.U8, nbSigs),
+		Signatures: make([]BIP340Signature, nbSigs),
+	}
+	witness := &bip340BatchCircuit{
+		PublicKeys: make([]BIP340PublicKey, nbSigs),
+		Msgs:       make([][]uints.U8, nbSigs),
+		Signatures: make([]BIP340Signature, nbSigs),
+	}
+	msgs := make([][]byte, nbSigs)
+	for i := 0; i < nbSigs; i++ {
+		msgs[i] = make([]byte, 32+i)
+		_, err := rand.Reader.Read(msgs[i])
+		assert.NoError(err)
+		var pk, sig []byte
+		if repeatedKey {
+			pk, sig = bip340SignWith(t, d, msgs[i])
+		} else {
+			pk, sig = bip340Sign(t, msgs[i])
+		}
+		circuit.Msgs[i] = make([]uints.U8, len(msgs[i]))
+		witness.PublicKeys[i], witness.Msgs[i], witness.Signatures[i] = bip340Assignment(t, pk, msgs[i], sig)
+	}
+	err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// swap the signatures of two messages
+	witness.Signatures[0], witness.Signatures[1] = witness.Signatures[1], witness.Signatures[0]
+	err = test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestValueOfBIP340(t *testing.T) {
+	assert := test.NewAssert(t)
+	// x coordinate not on curve, from test vector 5 of BIP-340
+	pk, _ := hex.DecodeString("EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34")
+	_, err := ValueOfBIP340PublicKey(pk)
+	assert.Error(err)
+	_, err = ValueOfBIP340PublicKey(pk[:31])
+	assert.Error(err)
+	// s equal to the group order, from test vector 13 of BIP-340
+	sig, _ := hex.DecodeString("6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E177769FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141")
+	_, err = ValueOfBIP340Signature(sig)
+	assert.Error(err)
+}
+
+func TestBatchBIP340CollidingPoints(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the offset of the windowed multi-scalar multiplication is the point with
+	// the smallest x-coordinate
+	var offset secp256k1.G1Affine
+	var rhs, seven fp.Element
+	seven.SetUint64(7)
+	for x := uint64(1); ; x++ {
+		offset.X.SetUint64(x)
+		rhs.Square(&offset.X).Mul(&rhs, &offset.X).Add(&rhs, &seven)
+		if offset.Y.Sqrt(&rhs) != nil {
+			break
+		}
+	}
+	offsetX := offset.X.Bytes()
+	randomPk, _ := bip340Sign(t, []byte("key"))
+	for _, tc := range []struct {
+		name   string
+		pk, rx []byte
+	}{
+		{"offset key and commitment", offsetX[:], offsetX[:]},
+		{"commitment equal to the key", randomPk, randomPk},
+	} {
+		var s fr.Element
+		if _, err := s.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		sb := s.Bytes()
+		msgs := [][]byte{[]byte("valid"), []byte("forged")}
+		pk, sig := bip340Sign(t, msgs[0])
+		circuit := &bip340BatchCircuit{
+			PublicKeys: make([]BIP340PublicKey, 2),
+			Msgs:       [][]uints.U8{make([]uints.U8, len(msgs[0])), make([]uints.U8, len(msgs[1]))},
+			Signatures: make([]BIP340Signature, 2),
+		}
+		witness := &bip340BatchCircuit{
+			PublicKeys: make([]BIP340PublicKey, 2),
+			Msgs:       make([][]uints.U8, 2),
+			Signatures: make([]BIP340Signature, 2),
+		}
+		witness.PublicKeys[0], witness.Msgs[0], witness.Signatures[0] = bip340Assignment(t, pk, msgs[0], sig)
+		witness.PublicKeys[1], witness.Msgs[1], witness.Signatures[1] = bip340Assignment(t, tc.pk, msgs[1], append(append([]byte{}, tc.rx...), sb[:]...))
+		err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+		assert.Error(err, tc.name)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Package schnorr implements Schnorr signature verification.
+
+The package provides two flavours of the scheme:
+  - [BIP-340] signatures over secp256k1 as used in Bitcoin Taproot. The group
+    operations are performed using the [emulated/sw_emulated] package and the
+    challenge is computed using tagged SHA256 hashes, see [VerifyBIP340] and
+    [BatchVerifyBIP340].
+  - generic Schnorr signatures over twisted Edwards curves defined over the
+    native field. The challenge is computed with a pluggable
+    [hash.FieldHasher], making it cheap to use with SNARK-friendly hash
+    functions, see [Verify].
+
+Batch verification of BIP-340 signatures checks a random linear combination of
+the verification equations, so that all the scalar multiplications are
+performed in a single windowed multi-scalar multiplication.
+
+[BIP-340]: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+*/
+package schnorr
+
+import (
+	"math/big"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/native/twistededwards"
+	"github.com/airchains-network/gnark/std/hash"
+)
+
+// PublicKey stores a Schnorr public key over a twisted Edwards curve (to be
+// used in gnark circuit).
+type PublicKey struct {
+	A twistededwards.Point
+}
+
+// Signature stores a Schnorr signature over a twisted Edwards curve (to be
+// used in gnark circuit). The signature is a tuple (E, S) where E is the
+// challenge H(R, A, M) for the commitment R=[k]G and S=k+E*a mod l for the
+// secret key a and the prime subgroup order l.
+type Signature struct {
+	E, S frontend.Variable
+}
+
+// Verify verifies a Schnorr signature sig on the message msg for the public
+// key pubKey. It recomputes the commitment R=[S]G-[E]A and asserts that
+// H(R, A, M) equals the challenge E. S must be reduced modulo the prime subgroup
+// order l.
+//
+// ⚠️  The public key must be in the prime-order subgroup, this is not checked.
+func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pubKey PublicKey, hash hash.FieldHasher) error {
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+	curve.AssertIsOnCurve(pubKey.A)
+
+	// S < l, otherwise S+l would also verify and the signature is malleable
+	order := curve.Params().Order
+	curve.API().AssertIsLessOrEqual(sig.S, new(big.Int).Sub(order, big.NewInt(1)))
+
+	// [S]G-[E]A
+	_A := curve.Neg(pubKey.A)
+	R := curve.DoubleBaseScalarMul(base, _A, sig.S, sig.E)
+
+	// compute H(R, A, M)
+	hash.Reset()
+	hash.Write(R.X)
+	hash.Write(R.Y)
+	hash.Write(pubKey.A.X)
+	hash.Write(pubKey.A.Y)
+	hash.Write(msg)
+	e := hash.Sum()
+
+	curve.API().AssertIsEqual(e, sig.E)
+	return nil
+}
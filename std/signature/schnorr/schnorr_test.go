@@ -0,0 +1,83 @@
+package schnorr
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/std/algebra/native/twistededwards"
+	"github.com/airchains-network/gnark/std/hash/mimc"
+	"github.com/airchains-network/gnark/test"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	nativemimc "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+)
+
+type schnorrCircuit struct {
+	PublicKey PublicKey
+	Signature Signature
+	Message   frontend.Variable
+}
+
+func (c *schnorrCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return Verify(curve, c.Signature, c.Message, c.PublicKey, &h)
+}
+
+func TestSchnorrTwistedEdwards(t *testing.T) {
+	assert := test.NewAssert(t)
+	ed := edbn254.GetEdwardsCurve()
+
+	// key generation
+	var a, k, msg fr.Element
+	a.SetRandom()
+	k.SetRandom()
+	msg.SetRandom()
+	var A, R edbn254.PointAffine
+	A.ScalarMultiplication(&ed.Base, a.BigInt(new(big.Int)))
+	R.ScalarMultiplication(&ed.Base, k.BigInt(new(big.Int)))
+
+	// e = H(R, A, M) and s = k + e*a mod l
+	h := nativemimc.NewMiMC()
+	for _, v := range []fr.Element{R.X, R.Y, A.X, A.Y, msg} {
+		b := v.Bytes()
+		h.Write(b[:])
+	}
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	s := new(big.Int).Mul(e, a.BigInt(new(big.Int)))
+	s.Add(s, k.BigInt(new(big.Int)))
+	s.Mod(s, &ed.Order)
+
+	witness := &schnorrCircuit{
+		PublicKey: PublicKey{A: twistededwards.Point{X: A.X, Y: A.Y}},
+		Signature: Signature{E: e, S: s},
+		Message:   msg,
+	}
+	err := test.IsSolved(&schnorrCircuit{}, witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// S+l gives the same commitment, but it isn't reduced
+	witness.Signature.S = new(big.Int).Add(s, &ed.Order)
+	err = test.IsSolved(&schnorrCircuit{}, witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	witness.Signature.S = s
+	witness.Message = 0
+	err = test.IsSolved(&schnorrCircuit{}, witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	assert.CheckCircuit(&schnorrCircuit{}, test.WithValidAssignment(&schnorrCircuit{
+		PublicKey: PublicKey{A: twistededwards.Point{X: A.X, Y: A.Y}},
+		Signature: Signature{E: e, S: s},
+		Message:   msg,
+	}), test.WithCurves(ecc.BN254))
+}
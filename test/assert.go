@@ -17,8 +17,10 @@ limitations under the License.
 package test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"strings"
 	"testing"
@@ -226,3 +228,26 @@ func (assert *Assert) roundTripCheck(from any, builder func() any, descs ...stri
 		assert.NoError(gnarkio.RoundTripCheck(from, builder))
 	}, descs...)
 }
+
+// roundTripVerifyCheck is like roundTripCheck for objects which do not
+// serialize their unexported state: it checks that the decoded object
+// encodes to the same bytes and passes check.
+func (assert *Assert) roundTripVerifyCheck(from any, builder func() any, check func(decoded any) error, descs ...string) {
+	assert.Run(func(assert *Assert) {
+		var buf bytes.Buffer
+		written, err := from.(io.WriterTo).WriteTo(&buf)
+		assert.NoError(err)
+
+		decoded := builder()
+		read, err := decoded.(io.ReaderFrom).ReadFrom(bytes.NewReader(buf.Bytes()))
+		assert.NoError(err)
+		assert.Equal(written, read, "bytes written / read don't match")
+
+		var reencoded bytes.Buffer
+		_, err = decoded.(io.WriterTo).WriteTo(&reencoded)
+		assert.NoError(err)
+		assert.Equal(buf.Bytes(), reencoded.Bytes(), "reconstructed object don't match original")
+
+		assert.NoError(check(decoded), "reconstructed object doesn't verify")
+	}, descs...)
+}
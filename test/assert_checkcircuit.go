@@ -1,7 +1,6 @@
 package test
 
 import (
-	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/airchains-network/gnark/backend"
 	"github.com/airchains-network/gnark/backend/groth16"
 	"github.com/airchains-network/gnark/backend/plonk"
@@ -11,6 +10,7 @@ import (
 	"github.com/airchains-network/gnark/frontend"
 	"github.com/airchains-network/gnark/frontend/schema"
 	"github.com/airchains-network/gnark/test/unsafekzg"
+	"github.com/consensys/gnark-crypto/ecc"
 )
 
 // CheckCircuit performs a series of check on the provided circuit.
@@ -22,6 +22,8 @@ import (
 //
 // Depending on the above flags, the following checks are performed:
 //   - the circuit compiles
+//   - the circuit has no unconstrained wires (with WithUnconstrainedWiresCheck)
+//   - the hint outputs are uniquely determined (with WithWitnessDeterminismCheck)
 //   - the circuit can be solved with the test engine
 //   - the circuit can be solved with the constraint system solver
 //   - the circuit can be solved with the prover
@@ -74,6 +76,20 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 					ccs, err := assert.compile(circuit, curve, b, opt.compileOpts)
 					assert.noError(err, nil)
 
+					if opt.checkUnconstrainedWires {
+						assert.noError(ccs.CheckUnconstrainedWires(), nil)
+					}
+
+					if opt.checkWitnessDeterminism {
+						for _, w := range validWitnesses {
+							w := w
+							assert.Run(func(assert *Assert) {
+								err := CheckWitnessDeterminism(ccs, w.full, opt.nbDeterminismAttempts, opt.solverOpts...)
+								assert.noError(err, &w)
+							}, "witness_determinism")
+						}
+					}
+
 					// TODO @gbotrel check serialization round trip with constraint system.
 
 					// 2- if we are not running the full prover;
@@ -139,7 +155,15 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 							}
 
 							// check proof serialization
-							assert.roundTripCheck(proof, proofBuilder, "proof")
+							if b == backend.PLONKFRI {
+								// PlonkFRI proofs only serialize the exported FRI data, the
+								// decoded proof is checked by the verifier instead.
+								assert.roundTripVerifyCheck(proof, proofBuilder, func(decoded any) error {
+									return concreteBackend.verify(decoded, vk, w.public, opt.verifierOpts...)
+								}, "proof")
+							} else {
+								assert.roundTripCheck(proof, proofBuilder, "proof")
+							}
 						}, "valid_witness")
 					}
 
@@ -278,13 +302,13 @@ var (
 			pkBuilder, vkBuilder, proofBuilder func() any,
 			err error) {
 			pk, vk, err = plonkfri.Setup(ccs)
-			return pk, vk, func() any { return nil }, func() any { return nil }, func() any { return nil }, err
+			return pk, vk, func() any { return plonkfri.NewProvingKey(curve) }, func() any { return plonkfri.NewVerifyingKey(curve) }, func() any { return plonkfri.NewProof(curve) }, err
 		},
 		prove: func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error) {
 			return plonkfri.Prove(ccs, pk.(plonkfri.ProvingKey), fullWitness, opts...)
 		},
 		verify: func(proof, vk any, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
-			return plonkfri.Verify(proof, vk.(plonkfri.VerifyingKey), publicWitness, opts...)
+			return plonkfri.Verify(proof.(plonkfri.Proof), vk.(plonkfri.VerifyingKey), publicWitness, opts...)
 		},
 	}
 )
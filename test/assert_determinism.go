@@ -0,0 +1,233 @@
+package test
+
+import (
+	"fmt"
+	"math/big"
+	mrand "math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/airchains-network/gnark/backend/witness"
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/airchains-network/gnark/constraint/solver"
+	"github.com/airchains-network/gnark/frontend/cs"
+)
+
+// NonUniqueWitnessError is returned by CheckWitnessDeterminism when the outputs
+// of a hint call can be replaced by other values without the constraint system
+// rejecting the witness.
+type NonUniqueWitnessError struct {
+	Hint        string
+	Inputs      []*big.Int
+	Outputs     []*big.Int // outputs computed by the hint
+	Alternative []*big.Int // outputs which also satisfy the constraints
+}
+
+func (e *NonUniqueWitnessError) Error() string {
+	return fmt.Sprintf("witness is not unique: hint %s with inputs %s returned %s, but %s also satisfies the constraints",
+		e.Hint, formatValues(e.Inputs), formatValues(e.Outputs), formatValues(e.Alternative))
+}
+
+// CheckWitnessDeterminism solves the constraint system with the given full
+// witness, then solves it again several times, each time replacing the outputs
+// of a single hint call with perturbed values (see solver.OverrideHint). If one
+// of these runs succeeds, the constraints don't uniquely determine the hint
+// outputs and a *NonUniqueWitnessError is returned.
+//
+// The perturbations are, in this order: negating an output, incrementing an
+// output, replacing all the outputs with random values and decrementing an
+// output. nbAttempts bounds the number of perturbed runs; if it is not
+// positive, every hint call is perturbed with every perturbation. Hint calls are
+// identified by the hint and its inputs, so calls with the same inputs are
+// perturbed together.
+//
+// This is a heuristic: a nil error doesn't mean the witness is unique.
+func CheckWitnessDeterminism(ccs constraint.ConstraintSystem, fullWitness witness.Witness, nbAttempts int, opts ...solver.Option) error {
+	config, err := solver.NewConfig(opts...)
+	if err != nil {
+		return err
+	}
+
+	// record the hint calls of the honest run
+	var (
+		lock     sync.Mutex
+		recorded = make(map[string]*hintCall)
+	)
+	recordingOpts := append([]solver.Option{}, opts...)
+	for id, f := range config.HintFunctions {
+		id, f := id, f
+		recordingOpts = append(recordingOpts, solver.OverrideHint(id, func(q *big.Int, inputs, outputs []*big.Int) error {
+			if err := callHint(f, q, inputs, outputs); err != nil {
+				return err
+			}
+			call := &hintCall{
+				id:      id,
+				name:    solver.GetHintName(f),
+				inputs:  copyValues(inputs),
+				outputs: copyValues(outputs),
+			}
+			call.key = hintCallKey(id, inputs)
+			lock.Lock()
+			recorded[call.key] = call
+			lock.Unlock()
+			return nil
+		}))
+	}
+	if _, err := ccs.Solve(fullWitness, recordingOpts...); err != nil {
+		return fmt.Errorf("solving with the honest hints: %w", err)
+	}
+
+	// the commitment is random when solving without the prover and is bound
+	// by the proof, not by the constraints
+	commitmentID := solver.GetHintID(cs.Bsb22CommitmentComputePlaceholder)
+	calls := make([]*hintCall, 0, len(recorded))
+	for _, call := range recorded {
+		if call.id != commitmentID && len(call.outputs) != 0 {
+			calls = append(calls, call)
+		}
+	}
+	sort.Slice(calls, func(i, j int) bool { return calls[i].key < calls[j].key })
+
+	rng := mrand.New(mrand.NewSource(int64(len(calls)))) //#nosec G404 weak rng is fine here
+	rng.Shuffle(len(calls), func(i, j int) { calls[i], calls[j] = calls[j], calls[i] })
+
+	q := ccs.Field()
+	attempts := 0
+	for _, perturb := range perturbations {
+		for _, target := range calls {
+			if nbAttempts > 0 && attempts == nbAttempts {
+				return nil
+			}
+			alternative := perturb(q, target.outputs, rng)
+			if alternative == nil {
+				continue
+			}
+			attempts++
+
+			// replay the honest outputs, except for the target call
+			perturbedOpts := append([]solver.Option{}, opts...)
+			for id, f := range config.HintFunctions {
+				id, f := id, f
+				perturbedOpts = append(perturbedOpts, solver.OverrideHint(id, func(q *big.Int, inputs, outputs []*big.Int) error {
+					key := hintCallKey(id, inputs)
+					if key == target.key {
+						return setValues(outputs, alternative)
+					}
+					if call, ok := recorded[key]; ok {
+						return setValues(outputs, call.outputs)
+					}
+					return callHint(f, q, inputs, outputs)
+				}))
+			}
+			if _, err := ccs.Solve(fullWitness, perturbedOpts...); err == nil {
+				return &NonUniqueWitnessError{
+					Hint:        target.name,
+					Inputs:      target.inputs,
+					Outputs:     target.outputs,
+					Alternative: alternative,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// hintCall is a hint call recorded while solving.
+type hintCall struct {
+	key             string
+	id              solver.HintID
+	name            string
+	inputs, outputs []*big.Int
+}
+
+// perturbations return modified copies of the hint outputs, or nil when they
+// don't apply.
+var perturbations = []func(q *big.Int, outputs []*big.Int, rng *mrand.Rand) []*big.Int{
+	// negate
+	func(q *big.Int, outputs []*big.Int, rng *mrand.Rand) []*big.Int {
+		res := copyValues(outputs)
+		i := rng.Intn(len(res))
+		if res[i].Sign() == 0 {
+			return nil
+		}
+		res[i].Neg(res[i]).Mod(res[i], q)
+		if res[i].Cmp(outputs[i]) == 0 {
+			return nil
+		}
+		return res
+	},
+	// increment
+	func(q *big.Int, outputs []*big.Int, rng *mrand.Rand) []*big.Int {
+		res := copyValues(outputs)
+		i := rng.Intn(len(res))
+		res[i].Add(res[i], big.NewInt(1)).Mod(res[i], q)
+		return res
+	},
+	// randomize
+	func(q *big.Int, outputs []*big.Int, rng *mrand.Rand) []*big.Int {
+		res := make([]*big.Int, len(outputs))
+		for i := range res {
+			res[i] = new(big.Int).Rand(rng, q)
+			if res[i].Cmp(outputs[i]) == 0 {
+				res[i].Add(res[i], big.NewInt(1)).Mod(res[i], q)
+			}
+		}
+		return res
+	},
+	// decrement
+	func(q *big.Int, outputs []*big.Int, rng *mrand.Rand) []*big.Int {
+		res := copyValues(outputs)
+		i := rng.Intn(len(res))
+		res[i].Sub(res[i], big.NewInt(1)).Mod(res[i], q)
+		return res
+	},
+}
+
+// callHint calls the hint, recovering from panics on unexpected inputs.
+func callHint(f solver.Hint, q *big.Int, inputs, outputs []*big.Int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("hint %s panicked: %v", solver.GetHintName(f), r)
+		}
+	}()
+	return f(q, inputs, outputs)
+}
+
+func hintCallKey(id solver.HintID, inputs []*big.Int) string {
+	var sbb strings.Builder
+	sbb.WriteString(strconv.FormatUint(uint64(id), 10))
+	for _, in := range inputs {
+		sbb.WriteByte(':')
+		sbb.WriteString(in.Text(16))
+	}
+	return sbb.String()
+}
+
+func setValues(dst, src []*big.Int) error {
+	if len(dst) != len(src) {
+		return fmt.Errorf("expected %d outputs, got %d", len(src), len(dst))
+	}
+	for i := range dst {
+		dst[i].Set(src[i])
+	}
+	return nil
+}
+
+func copyValues(values []*big.Int) []*big.Int {
+	res := make([]*big.Int, len(values))
+	for i := range values {
+		res[i] = new(big.Int).Set(values[i])
+	}
+	return res
+}
+
+func formatValues(values []*big.Int) string {
+	s := make([]string, len(values))
+	for i := range values {
+		s[i] = values[i].String()
+	}
+	return "[" + strings.Join(s, ", ") + "]"
+}
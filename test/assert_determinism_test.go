@@ -0,0 +1,67 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/airchains-network/gnark/constraint/solver"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/cs/r1cs"
+	"github.com/airchains-network/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/require"
+)
+
+func sqrtHint(q *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if outputs[0].ModSqrt(inputs[0], q) == nil {
+		return errors.New("no square root")
+	}
+	// return the smallest root
+	if neg := new(big.Int).Sub(q, outputs[0]); neg.Cmp(outputs[0]) < 0 {
+		outputs[0].Set(neg)
+	}
+	return nil
+}
+
+type sqrtCircuit struct {
+	X, Y frontend.Variable
+	// bindRoot constrains the root to X, making the witness unique
+	bindRoot bool
+}
+
+func (c *sqrtCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(sqrtHint, 1, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Mul(res[0], res[0]), c.Y)
+	if c.bindRoot {
+		api.AssertIsEqual(res[0], c.X)
+	} else {
+		api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	}
+	return nil
+}
+
+func TestCheckWitnessDeterminism(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		assert := require.New(t)
+
+		assignment := sqrtCircuit{X: 3, Y: 9}
+		w, err := frontend.NewWitness(&assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+
+		// the root is known up to its sign
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &sqrtCircuit{})
+		assert.NoError(err)
+		err = CheckWitnessDeterminism(ccs, w, 0, solver.WithHints(sqrtHint))
+		var nErr *NonUniqueWitnessError
+		assert.True(errors.As(err, &nErr), "expected a NonUniqueWitnessError, got %v", err)
+		assert.Contains(nErr.Hint, "sqrtHint")
+
+		ccs, err = frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &sqrtCircuit{bindRoot: true})
+		assert.NoError(err)
+		assert.NoError(CheckWitnessDeterminism(ccs, w, 0, solver.WithHints(sqrtHint)))
+	}
+}
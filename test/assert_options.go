@@ -21,6 +21,10 @@ type testingConfig struct {
 	verifierOpts []backend.VerifierOption
 	compileOpts  []frontend.CompileOption
 
+	checkUnconstrainedWires bool
+	checkWitnessDeterminism bool
+	nbDeterminismAttempts   int
+
 	validAssignments   []frontend.Circuit
 	invalidAssignments []frontend.Circuit
 }
@@ -129,7 +133,8 @@ func NoTestEngine() TestingOption {
 // even when the build tags "solccheck" and "release_checks" are set.
 //
 // When the tags are set; this requires gnark-solidity-checker to be installed, which in turns
-// requires solc and abigen to be reachable in the PATH.
+// requires solc and abigen to be reachable in the PATH. The PlonkFRI contracts
+// are run directly, which requires solc and evm to be reachable in the PATH.
 //
 // See https://github.com/ConsenSys/gnark-solidity-checker for more details.
 func NoSolidityChecks() TestingOption {
@@ -176,3 +181,26 @@ func WithVerifierOpts(verifierOpts ...backend.VerifierOption) TestingOption {
 		return nil
 	}
 }
+
+// WithUnconstrainedWiresCheck is a testing option which checks that the compiled
+// constraint systems have no unconstrained inputs nor unconstrained or
+// underconstrained hint outputs (see constraint.FindUnconstrainedWires).
+func WithUnconstrainedWiresCheck() TestingOption {
+	return func(opt *testingConfig) error {
+		opt.checkUnconstrainedWires = true
+		return nil
+	}
+}
+
+// WithWitnessDeterminismCheck is a testing option which checks, for each valid
+// assignment, that the constraints uniquely determine the hint outputs, by
+// solving the constraint system with perturbed hint outputs (see
+// CheckWitnessDeterminism). nbAttempts bounds the number of perturbed solves
+// per assignment; if it is not positive, all the perturbations are tried.
+func WithWitnessDeterminismCheck(nbAttempts int) TestingOption {
+	return func(opt *testingConfig) error {
+		opt.checkWitnessDeterminism = true
+		opt.nbDeterminismAttempts = nbAttempts
+		return nil
+	}
+}
@@ -2,16 +2,20 @@ package test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/airchains-network/gnark/backend"
 	groth16_bn254 "github.com/airchains-network/gnark/backend/groth16/bn254"
 	plonk_bn254 "github.com/airchains-network/gnark/backend/plonk/bn254"
+	plonkfri_bn254 "github.com/airchains-network/gnark/backend/plonkfri/bn254"
 	"github.com/airchains-network/gnark/backend/witness"
 )
 
@@ -22,7 +26,8 @@ type verifyingKey interface {
 
 // solidityVerification checks that the exported solidity contract can verify the proof
 // and that the proof is valid.
-// It uses gnark-solidity-checker see test.WithSolidity option.
+// It uses gnark-solidity-checker see test.WithSolidity option, except for
+// PlonkFRI which is checked with solc and evm.
 func (assert *Assert) solidityVerification(b backend.ID, vk verifyingKey,
 	proof any,
 	validPublicWitness witness.Witness) {
@@ -30,6 +35,12 @@ func (assert *Assert) solidityVerification(b backend.ID, vk verifyingKey,
 		return // nothing to check, will make solc fail.
 	}
 	assert.t.Helper()
+	if b == backend.PLONKFRI {
+		// gnark-solidity-checker does not support PlonkFRI, its contract is
+		// run directly in the evm.
+		assert.plonkFriSolidityVerification(vk, proof.(*plonkfri_bn254.Proof), validPublicWitness)
+		return
+	}
 
 	// make temp dir
 	tmpDir, err := os.MkdirTemp("", "gnark-solidity-check*")
@@ -98,3 +109,86 @@ func (assert *Assert) solidityVerification(b backend.ID, vk verifyingKey,
 	out, err = cmd.CombinedOutput()
 	assert.NoError(err, string(out))
 }
+
+// plonkFriSolidityVerification compiles the exported PlonkFRI contract with
+// solc and runs its Verify function in the go-ethereum evm. It checks that the
+// contract accepts the proof for the valid public witness and rejects it when
+// the first public input is modified. It requires solc and evm in the PATH.
+func (assert *Assert) plonkFriSolidityVerification(vk verifyingKey, proof *plonkfri_bn254.Proof, validPublicWitness witness.Witness) {
+	assert.t.Helper()
+
+	// make temp dir
+	tmpDir, err := os.MkdirTemp("", "gnark-solidity-check*")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// export solidity contract
+	fSolidity, err := os.Create(filepath.Join(tmpDir, "plonkfri_verifier.sol"))
+	assert.NoError(err)
+	err = vk.ExportSolidity(fSolidity)
+	assert.NoError(err)
+	err = fSolidity.Close()
+	assert.NoError(err)
+
+	// compile the contract
+	cmd := exec.Command("solc", "--optimize", "--combined-json", "bin-runtime,hashes", "plonkfri_verifier.sol")
+	cmd.Dir = tmpDir
+	assert.t.Log("running ", cmd.String())
+	out, err := cmd.Output()
+	assert.NoError(err, string(out))
+	var compiled struct {
+		Contracts map[string]struct {
+			BinRuntime string            `json:"bin-runtime"`
+			Hashes     map[string]string `json:"hashes"`
+		}
+	}
+	assert.NoError(json.Unmarshal(out, &compiled))
+	contract, ok := compiled.Contracts["plonkfri_verifier.sol:PlonkFriVerifier"]
+	assert.True(ok, "contract not found in solc output")
+	selector, err := hex.DecodeString(contract.Hashes["Verify(bytes,uint256[])"])
+	assert.NoError(err)
+
+	// public witness, see solidityVerification
+	bPublicWitness, err := validPublicWitness.MarshalBinary()
+	assert.NoError(err)
+	bPublicWitness = bPublicWitness[12:]
+
+	// ABI encoding of Verify(bytes proof, uint256[] public_inputs), the public
+	// inputs are encoded as 32-byte big-endian words as in the witness.
+	proofBytes := proof.MarshalSolidity()
+	call := func(public []byte) []byte {
+		paddedSize := (len(proofBytes) + 31) / 32 * 32
+		word := func(v int) []byte {
+			var w [32]byte
+			binary.BigEndian.PutUint64(w[24:], uint64(v))
+			return w[:]
+		}
+		res := append([]byte{}, selector...)
+		res = append(res, word(0x40)...)
+		res = append(res, word(0x60+paddedSize)...)
+		res = append(res, word(len(proofBytes))...)
+		res = append(res, proofBytes...)
+		res = append(res, make([]byte, paddedSize-len(proofBytes))...)
+		res = append(res, word(len(public)/32)...)
+		res = append(res, public...)
+		return res
+	}
+
+	// the evm prints the returned data, the boolean true for a valid proof.
+	// An invalid proof may also make the call revert, so the exit status is
+	// not checked.
+	run := func(input []byte) string {
+		cmd := exec.Command("evm", "--code", contract.BinRuntime, "--input", hex.EncodeToString(input), "run")
+		assert.t.Log("running evm run")
+		out, _ := cmd.CombinedOutput()
+		return strings.TrimSpace(string(out))
+	}
+	success := "0x" + strings.Repeat("0", 63) + "1"
+	res := run(call(bPublicWitness))
+	assert.Equal(success, res, "the contract must accept the proof")
+
+	invalid := bytes.Clone(bPublicWitness)
+	invalid[31] ^= 1
+	res = run(call(invalid))
+	assert.NotEqual(success, res, "the contract must reject the proof for another public input")
+}
@@ -0,0 +1,238 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/airchains-network/gnark/backend"
+	"github.com/airchains-network/gnark/backend/witness"
+	"github.com/airchains-network/gnark/constraint"
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/airchains-network/gnark/frontend/schema"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/require"
+)
+
+// Fuzz registers a native fuzz target (see testing.F) for the given circuit:
+//
+//	func FuzzMyCircuit(f *testing.F) {
+//		test.Fuzz(f, &MyCircuit{})
+//	}
+//
+// The fuzz input is mapped onto the circuit schema (see fuzzFiller) to build an
+// assignment, which is checked with the test engine (with the inputs as
+// variables and as constants) and with the constraint system solvers of each
+// curve and backend of the testing options. The fuzz target fails if they
+// disagree on whether the assignment is valid.
+//
+// The index of the constraint on which the solver fails is fed back to the
+// fuzzing engine as coverage, so that inputs satisfying more constraints are
+// kept in the corpus.
+//
+// Failing inputs are persisted along with the JSON witness in
+// testdata/gnark-fuzz/<fuzz target name>/ and are replayed as seeds, including
+// when running go test without -fuzz.
+func Fuzz(f *testing.F, circuit frontend.Circuit, opts ...TestingOption) {
+	f.Helper()
+
+	// only the options and the compilation are used, which don't need a *testing.T
+	assert := &Assert{Assertions: require.New(f)}
+	opt := assert.options(opts...)
+	backends := opt.backends
+	if len(backends) == 0 {
+		backends = []backend.ID{backend.GROTH16, backend.PLONK}
+	}
+
+	type target struct {
+		curve ecc.ID
+		b     backend.ID
+		ccs   constraint.ConstraintSystem
+	}
+	var targets []target
+	for _, curve := range opt.curves {
+		for _, b := range backends {
+			if b == backend.PLONKFRI {
+				// same constraint system as PLONK
+				continue
+			}
+			ccs, err := assert.compile(circuit, curve, b, opt.compileOpts)
+			if err != nil {
+				f.Fatalf("compiling for %s/%s: %v", curve, b, err)
+			}
+			targets = append(targets, target{curve, b, ccs})
+		}
+	}
+	if len(targets) == 0 {
+		f.Fatal("no curve to fuzz with")
+	}
+
+	// seeds: all zeros, all ones, and the persisted failing inputs
+	f.Add([]byte{})
+	f.Add([]byte(strings.Repeat("\x07", 256)))
+	dir := filepath.Join("testdata", "gnark-fuzz", f.Name())
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		f.Fatal(err)
+	}
+	for _, entry := range entries {
+		var crasher fuzzCrasher
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			f.Fatal(err)
+		}
+		if err := json.Unmarshal(data, &crasher); err != nil {
+			f.Fatalf("parsing %s: %v", entry.Name(), err)
+		}
+		input, err := hex.DecodeString(crasher.Input)
+		if err != nil {
+			f.Fatalf("parsing %s: %v", entry.Name(), err)
+		}
+		f.Add(input)
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		for _, tg := range targets {
+			w := shallowClone(circuit)
+			filler := fuzzFiller{input: input, field: tg.curve.ScalarField()}
+			fill(w, filler.next)
+
+			errVars := IsSolved(circuit, w, tg.curve.ScalarField())
+			errConsts := IsSolved(circuit, w, tg.curve.ScalarField(), SetAllVariablesAsConstants())
+
+			full, err := frontend.NewWitness(w, tg.curve.ScalarField())
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, errSolver := tg.ccs.Solve(full, opt.solverOpts...)
+			fuzzCoverage(tg.ccs, errSolver)
+
+			var msg string
+			switch {
+			case (errVars == nil) != (errConsts == nil):
+				msg = fmt.Sprintf("test engine with variables (err: %v) and with constants (err: %v) disagree", errVars, errConsts)
+			case (errVars == nil) != (errSolver == nil):
+				msg = fmt.Sprintf("test engine (err: %v) and %s solver (err: %v) disagree", errVars, tg.b, errSolver)
+			default:
+				continue
+			}
+
+			path, err := persistFuzzCrasher(dir, input, circuit, full)
+			if err != nil {
+				t.Log("couldn't persist the failing input:", err)
+			} else {
+				t.Log("failing input persisted in", path)
+			}
+			t.Fatalf("%s/%s: %s", tg.curve, tg.b, msg)
+		}
+	})
+}
+
+// fuzzFiller maps fuzz input bytes onto field elements. For each value, a tag
+// byte selects how the value is read from the next bytes:
+//
+//	tag % 4 == 0: a signed byte, reduced modulo the field
+//	tag % 4 == 1: a byte indexing seedCorpus, reduced modulo the field
+//	tag % 4 == 2: a big-endian field element, reduced modulo the field
+//	tag % 4 == 3: the bit (tag >> 2) & 1
+//
+// Missing bytes are read as zeroes.
+type fuzzFiller struct {
+	input []byte
+	field *big.Int
+}
+
+func (f *fuzzFiller) next() interface{} {
+	tag := f.read(1)[0]
+	res := new(big.Int)
+	switch tag % 4 {
+	case 0:
+		res.SetInt64(int64(int8(f.read(1)[0])))
+	case 1:
+		res.Set(seedCorpus[int(f.read(1)[0])%len(seedCorpus)])
+	case 2:
+		res.SetBytes(f.read((f.field.BitLen() + 7) / 8))
+	case 3:
+		res.SetUint64(uint64(tag>>2) & 1)
+	}
+	return res.Mod(res, f.field)
+}
+
+func (f *fuzzFiller) read(n int) []byte {
+	res := make([]byte, n)
+	copy(res, f.input)
+	if n > len(f.input) {
+		n = len(f.input)
+	}
+	f.input = f.input[n:]
+	return res
+}
+
+// fuzzCrasher is the content of a file persisting a failing fuzz input.
+type fuzzCrasher struct {
+	Input   string          `json:"input"` // hex encoded fuzz input
+	Witness json.RawMessage `json:"witness,omitempty"`
+}
+
+func persistFuzzCrasher(dir string, input []byte, circuit frontend.Circuit, full witness.Witness) (string, error) {
+	crasher := fuzzCrasher{Input: hex.EncodeToString(input)}
+	if s, err := schema.New(circuit, tVariable); err == nil {
+		if w, err := full.ToJSON(s); err == nil {
+			crasher.Witness = w
+		}
+	}
+	data, err := json.MarshalIndent(crasher, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(input)
+	path := filepath.Join(dir, hex.EncodeToString(h[:8])+".json")
+	return path, os.WriteFile(path, data, 0600)
+}
+
+// fuzzCoverage reports which part of the constraint system was reached by the
+// solver to the fuzzing engine. The engine only observes the coverage of the
+// instrumented code, so each bucket of constraints is mapped to a distinct
+// function.
+func fuzzCoverage(ccs constraint.ConstraintSystem, err error) {
+	const (
+		nbConstraintBuckets = len(coverageBuckets) - 2
+		solved              = nbConstraintBuckets
+		otherError          = nbConstraintBuckets + 1
+	)
+	var unsatisfied interface{ ConstraintID() int }
+	bucket := otherError
+	switch {
+	case err == nil:
+		bucket = solved
+	case errors.As(err, &unsatisfied) && ccs.GetNbConstraints() != 0:
+		bucket = unsatisfied.ConstraintID() * nbConstraintBuckets / ccs.GetNbConstraints()
+		if bucket >= nbConstraintBuckets {
+			bucket = nbConstraintBuckets - 1
+		}
+	}
+	coverageBuckets[bucket]()
+}
+
+var coverageCounter int
+
+var coverageBuckets = [...]func(){
+	func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ },
+	func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ },
+	func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ },
+	func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ },
+	func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ },
+	func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ },
+	func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ },
+	func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ }, func() { coverageCounter++ },
+}
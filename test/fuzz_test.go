@@ -0,0 +1,17 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/airchains-network/gnark/frontend"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+func FuzzIsCrumb(f *testing.F) {
+	// valid: all the values are crumbs
+	f.Add([]byte{0, 3, 0, 2, 7, 0, 0})
+	// invalid: the second value is 4
+	f.Add([]byte{0, 1, 0, 4})
+
+	Fuzz(f, &isCrumbCircuit{C: make([]frontend.Variable, 4)}, WithCurves(ecc.BN254))
+}